@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lpmourato/c9s/internal/app"
+	"github.com/lpmourato/c9s/internal/buildinfo"
+)
+
+// Build information, set with -ldflags "-X main.version=... -X main.commit=... -X main.date=..."
+var (
+	version = ""
+	commit  = ""
+	date    = ""
+)
+
+func main() {
+	if err := app.New().WithVersion(buildinfo.New(version, commit, date)).Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "c9s: error: %v\n", err)
+		os.Exit(1)
+	}
+}
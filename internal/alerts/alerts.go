@@ -0,0 +1,208 @@
+// Package alerts evaluates log-based alert rules on streamed log entries and
+// runs the notification hooks of the rules that fire.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/config"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// DefaultWindow is the time window of rules that do not set one
+const DefaultWindow = time.Minute
+
+// Rule is a compiled alert rule
+type Rule struct {
+	Name      string
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+	Command   string
+	Webhook   string
+
+	pattern  *regexp.Regexp
+	severity string
+	query    *logging.Query
+}
+
+// NewRule compiles an alert rule of the configuration file
+func NewRule(cfg config.AlertRule) (*Rule, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("alert rule without a name")
+	}
+	if cfg.Pattern == "" && cfg.Severity == "" && cfg.Query == "" {
+		return nil, fmt.Errorf("alert rule %s: set a pattern, severity or query", cfg.Name)
+	}
+	if cfg.Threshold < 0 || cfg.Window < 0 || cfg.Cooldown < 0 {
+		return nil, fmt.Errorf("alert rule %s: threshold, window and cooldown must not be negative", cfg.Name)
+	}
+
+	r := &Rule{
+		Name:      cfg.Name,
+		Threshold: cfg.Threshold,
+		Window:    cfg.Window,
+		Cooldown:  cfg.Cooldown,
+		Command:   cfg.Command,
+		Webhook:   cfg.Webhook,
+	}
+	if r.Window == 0 {
+		r.Window = DefaultWindow
+	}
+	if r.Cooldown == 0 {
+		r.Cooldown = r.Window
+	}
+
+	var err error
+	if cfg.Pattern != "" {
+		if r.pattern, err = regexp.Compile(cfg.Pattern); err != nil {
+			return nil, fmt.Errorf("alert rule %s: invalid pattern: %v", cfg.Name, err)
+		}
+	}
+	if cfg.Severity != "" {
+		r.severity = strings.ToUpper(cfg.Severity)
+		if r.severity != model.SeverityDefault && model.SeverityRank(r.severity) == 0 {
+			return nil, fmt.Errorf("alert rule %s: unknown severity %q", cfg.Name, cfg.Severity)
+		}
+	}
+	if cfg.Query != "" {
+		if r.query, err = logging.ParseQuery(cfg.Query); err != nil {
+			return nil, fmt.Errorf("alert rule %s: invalid query: %v", cfg.Name, err)
+		}
+	}
+	return r, nil
+}
+
+// NewRules compiles the alert rules of the configuration file
+func NewRules(cfgs []config.AlertRule) ([]*Rule, error) {
+	rules := make([]*Rule, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		rule, err := NewRule(cfg)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Matches reports whether an entry matches any condition of the rule
+func (r *Rule) Matches(entry model.LogEntry) bool {
+	return r.pattern != nil && r.pattern.MatchString(entry.Message) ||
+		r.severity != "" && model.SeverityAtLeast(entry.Severity, r.severity) ||
+		r.query != nil && r.query.Match(entry)
+}
+
+// Alert is a rule firing
+type Alert struct {
+	Rule *Rule
+	// Number of entries matching within the window of the rule
+	Count int
+	// Entry that made the rule fire
+	Entry   model.LogEntry
+	FiredAt time.Time
+}
+
+// MarshalJSON implements json.Marshaler, as passed to the hooks of the rule
+func (a Alert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Rule    string         `json:"rule"`
+		Count   int            `json:"count"`
+		Window  string         `json:"window"`
+		FiredAt time.Time      `json:"firedAt"`
+		Entry   model.LogEntry `json:"entry"`
+	}{a.Rule.Name, a.Count, a.Rule.Window.String(), a.FiredAt, a.Entry})
+}
+
+// Evaluator counts the entries matching each rule and reports the rules firing
+type Evaluator struct {
+	rules []*Rule
+	state []ruleState
+	// Entries older than the window of a rule before start are history and
+	// cannot make it fire
+	start time.Time
+	now   func() time.Time
+}
+
+// ruleState holds the recent matches of a rule
+type ruleState struct {
+	// Timestamps of the matching entries within the window, oldest first
+	matches []time.Time
+	// The rule does not fire for entries before this time
+	mutedUntil time.Time
+}
+
+// NewEvaluator creates an evaluator of rules starting now
+func NewEvaluator(rules []*Rule) *Evaluator {
+	return &Evaluator{
+		rules: rules,
+		state: make([]ruleState, len(rules)),
+		start: time.Now(),
+		now:   time.Now,
+	}
+}
+
+// Check counts an entry and returns the alerts of the rules it makes fire.
+// Windows are measured with the timestamps of the entries.
+func (e *Evaluator) Check(entry model.LogEntry) []Alert {
+	if logging.IsStatus(entry) {
+		return nil
+	}
+
+	var alerts []Alert
+	for i, rule := range e.rules {
+		if entry.Timestamp.Before(e.start.Add(-rule.Window)) || !rule.Matches(entry) {
+			continue
+		}
+
+		state := &e.state[i]
+		state.matches = append(state.matches, entry.Timestamp)
+		cutoff := entry.Timestamp.Add(-rule.Window)
+		for len(state.matches) > 0 && !state.matches[0].After(cutoff) {
+			state.matches = state.matches[1:]
+		}
+
+		if len(state.matches) <= rule.Threshold || entry.Timestamp.Before(state.mutedUntil) {
+			continue
+		}
+		alerts = append(alerts, Alert{Rule: rule, Count: len(state.matches), Entry: entry, FiredAt: e.now()})
+		state.matches = nil
+		state.mutedUntil = entry.Timestamp.Add(rule.Cooldown)
+	}
+	return alerts
+}
+
+// Ensure Stage can run in a log pipeline
+var _ logging.Stage = (*Stage)(nil)
+
+// Stage is a log pipeline stage evaluating alert rules on the entries passing through
+type Stage struct {
+	evaluator *Evaluator
+	notify    func(alert Alert)
+}
+
+// NewStage creates a stage calling notify for every alert of rules.
+// notify is called on the pipeline goroutine and must not block.
+func NewStage(rules []*Rule, notify func(alert Alert)) *Stage {
+	return &Stage{evaluator: NewEvaluator(rules), notify: notify}
+}
+
+// Process implements logging.Stage
+func (s *Stage) Process(ctx context.Context, in <-chan model.LogEntry, out chan<- model.LogEntry) {
+	for entry := range in {
+		for _, alert := range s.evaluator.Check(entry) {
+			s.notify(alert)
+		}
+		select {
+		case out <- entry:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
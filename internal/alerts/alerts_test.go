@@ -0,0 +1,132 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/config"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func mustRules(t *testing.T, cfgs ...config.AlertRule) []*Rule {
+	t.Helper()
+	rules, err := NewRules(cfgs)
+	if err != nil {
+		t.Fatalf("NewRules() failed: %v", err)
+	}
+	return rules
+}
+
+func TestRuleMatches(t *testing.T) {
+	rules := mustRules(t,
+		config.AlertRule{Name: "oom", Pattern: `OutOfMemory`, Severity: "error"},
+		config.AlertRule{Name: "slow", Query: `httpRequest.latency>"2s"`},
+	)
+	oom, slow := rules[0], rules[1]
+
+	tests := []struct {
+		rule  *Rule
+		entry model.LogEntry
+		want  bool
+	}{
+		{oom, model.LogEntry{Severity: "INFO", Message: "java.lang.OutOfMemoryError"}, true},
+		{oom, model.LogEntry{Severity: "CRITICAL", Message: "panic"}, true},
+		{oom, model.LogEntry{Severity: "WARNING", Message: "low memory"}, false},
+		{slow, model.LogEntry{HTTPRequest: &model.HTTPRequest{Latency: 3 * time.Second}}, true},
+		{slow, model.LogEntry{HTTPRequest: &model.HTTPRequest{Latency: time.Second}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Matches(tt.entry); got != tt.want {
+			t.Errorf("%s.Matches(%+v) = %v, want %v", tt.rule.Name, tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestNewRuleErrors(t *testing.T) {
+	for _, cfg := range []config.AlertRule{
+		{Pattern: "x"},
+		{Name: "empty"},
+		{Name: "regex", Pattern: "("},
+		{Name: "severity", Severity: "LOUD"},
+		{Name: "query", Query: "(severity"},
+		{Name: "negative", Severity: "ERROR", Threshold: -1},
+	} {
+		if _, err := NewRule(cfg); err == nil {
+			t.Errorf("NewRule(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
+
+func TestEvaluatorThreshold(t *testing.T) {
+	rules := mustRules(t, config.AlertRule{Name: "errors", Severity: "ERROR", Threshold: 2, Window: time.Minute})
+	e := NewEvaluator(rules)
+	start := e.start
+
+	check := func(offset time.Duration, severity string) int {
+		return len(e.Check(model.LogEntry{Timestamp: start.Add(offset), Severity: severity}))
+	}
+
+	// Two errors within the window are tolerated, the third fires
+	if n := check(0, "ERROR") + check(10*time.Second, "ERROR") + check(20*time.Second, "INFO"); n != 0 {
+		t.Fatalf("fired %d alerts below the threshold", n)
+	}
+	if n := check(30*time.Second, "ERROR"); n != 1 {
+		t.Fatalf("third error fired %d alerts, want 1", n)
+	}
+
+	// The rule is muted for a window after firing
+	for i := 0; i < 3; i++ {
+		if n := check(40*time.Second+time.Duration(i)*time.Second, "ERROR"); n != 0 {
+			t.Fatalf("muted rule fired %d alerts", n)
+		}
+	}
+
+	// Errors spread over more than the window do not add up
+	for i := 0; i < 5; i++ {
+		if n := check(5*time.Minute+time.Duration(i)*40*time.Second, "ERROR"); n != 0 {
+			t.Fatalf("spread errors fired %d alerts", n)
+		}
+	}
+}
+
+func TestEvaluatorIgnoresHistoryAndStatus(t *testing.T) {
+	rules := mustRules(t, config.AlertRule{Name: "any", Severity: "ERROR"})
+	e := NewEvaluator(rules)
+
+	if alerts := e.Check(model.LogEntry{Timestamp: e.start.Add(-time.Hour), Severity: "ERROR"}); len(alerts) != 0 {
+		t.Errorf("old entry fired %d alerts", len(alerts))
+	}
+	status := model.LogEntry{Timestamp: e.start, Severity: "ERROR", LogName: logging.StatusLogName}
+	if alerts := e.Check(status); len(alerts) != 0 {
+		t.Errorf("status message fired %d alerts", len(alerts))
+	}
+	if alerts := e.Check(model.LogEntry{Timestamp: e.start, Severity: "ERROR", Message: "boom"}); len(alerts) != 1 || alerts[0].Count != 1 {
+		t.Errorf("Check() = %+v, want one alert", alerts)
+	}
+}
+
+func TestStage(t *testing.T) {
+	rules := mustRules(t, config.AlertRule{Name: "boom", Pattern: "boom"})
+	var alerts []Alert
+	stage := NewStage(rules, func(alert Alert) { alerts = append(alerts, alert) })
+
+	in := make(chan model.LogEntry, 3)
+	now := time.Now()
+	in <- model.LogEntry{Timestamp: now, Message: "ok"}
+	in <- model.LogEntry{Timestamp: now, Message: "boom"}
+	close(in)
+
+	out := logging.Pipe(context.Background(), in, stage)
+	var passed int
+	for range out {
+		passed++
+	}
+	if passed != 2 {
+		t.Errorf("stage passed %d entries, want 2", passed)
+	}
+	if len(alerts) != 1 || alerts[0].Entry.Message != "boom" {
+		t.Errorf("alerts = %+v, want one for the boom entry", alerts)
+	}
+}
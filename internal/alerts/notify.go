@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultHookTimeout bounds the time a command or webhook may take
+const DefaultHookTimeout = 10 * time.Second
+
+// Notifier runs the command and webhook hooks of the rules that fire
+type Notifier struct {
+	Client  *http.Client
+	Timeout time.Duration
+}
+
+// NewNotifier creates a notifier with the default timeout
+func NewNotifier() *Notifier {
+	return &Notifier{
+		Client:  http.DefaultClient,
+		Timeout: DefaultHookTimeout,
+	}
+}
+
+// Notify runs the hooks of the rule of an alert, passing them the alert as JSON.
+// Both hooks run even when the first fails; their errors are joined.
+func (n *Notifier) Notify(ctx context.Context, alert Alert) error {
+	if alert.Rule.Command == "" && alert.Rule.Webhook == "" {
+		return nil
+	}
+	payload, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert %s: %v", alert.Rule.Name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+
+	var errs []error
+	if alert.Rule.Command != "" {
+		if err := n.runCommand(ctx, alert, payload); err != nil {
+			errs = append(errs, fmt.Errorf("alert %s: command failed: %v", alert.Rule.Name, err))
+		}
+	}
+	if alert.Rule.Webhook != "" {
+		if err := n.postWebhook(ctx, alert, payload); err != nil {
+			errs = append(errs, fmt.Errorf("alert %s: webhook failed: %v", alert.Rule.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runCommand runs the command of a rule with the shell. The alert is passed
+// on stdin and its main fields as C9S_ALERT_* environment variables.
+func (n *Notifier) runCommand(ctx context.Context, alert Alert, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", alert.Rule.Command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Env = append(os.Environ(),
+		"C9S_ALERT_RULE="+alert.Rule.Name,
+		"C9S_ALERT_COUNT="+strconv.Itoa(alert.Count),
+		"C9S_ALERT_SEVERITY="+alert.Entry.Severity,
+		"C9S_ALERT_MESSAGE="+alert.Entry.Message,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// postWebhook POSTs the alert to the webhook of its rule
+func (n *Notifier) postWebhook(ctx context.Context, alert Alert, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, alert.Rule.Webhook, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", alert.Rule.Webhook, resp.Status)
+	}
+	return nil
+}
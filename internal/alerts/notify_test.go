@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/config"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// alertPayload is the JSON passed to the hooks
+type alertPayload struct {
+	Rule   string         `json:"rule"`
+	Count  int            `json:"count"`
+	Window string         `json:"window"`
+	Entry  model.LogEntry `json:"entry"`
+}
+
+func testAlert(t *testing.T, cfg config.AlertRule) Alert {
+	t.Helper()
+	rule, err := NewRule(cfg)
+	if err != nil {
+		t.Fatalf("NewRule() failed: %v", err)
+	}
+	return Alert{
+		Rule:    rule,
+		Count:   6,
+		Entry:   model.LogEntry{Timestamp: time.Now(), Severity: "ERROR", Message: "OutOfMemoryError"},
+		FiredAt: time.Now(),
+	}
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	received := make(chan alertPayload, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook request = %s with %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var payload alertPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("failed to decode webhook body: %v", err)
+		}
+		received <- payload
+	}))
+	defer server.Close()
+
+	alert := testAlert(t, config.AlertRule{Name: "oom", Pattern: "OutOfMemory", Window: time.Minute, Webhook: server.URL})
+	if err := NewNotifier().Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+
+	payload := <-received
+	if payload.Rule != "oom" || payload.Count != 6 || payload.Window != "1m0s" || payload.Entry.Message != "OutOfMemoryError" {
+		t.Errorf("webhook payload = %+v", payload)
+	}
+}
+
+func TestNotifyWebhookError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	alert := testAlert(t, config.AlertRule{Name: "oom", Pattern: "OutOfMemory", Webhook: server.URL})
+	err := NewNotifier().Notify(context.Background(), alert)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Notify() error = %v, want the webhook status", err)
+	}
+}
+
+func TestNotifyCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "alert.json")
+	alert := testAlert(t, config.AlertRule{
+		Name:    "oom",
+		Pattern: "OutOfMemory",
+		Command: `cat > "$OUT" && test "$C9S_ALERT_RULE" = oom`,
+	})
+	t.Setenv("OUT", out)
+
+	if err := NewNotifier().Notify(context.Background(), alert); err != nil {
+		t.Fatalf("Notify() failed: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload alertPayload
+	if err := json.Unmarshal(data, &payload); err != nil || payload.Rule != "oom" {
+		t.Errorf("command stdin = %s (%v)", data, err)
+	}
+
+	alert.Rule.Command = "echo broken >&2; exit 3"
+	if err := NewNotifier().Notify(context.Background(), alert); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("Notify() error = %v, want the command stderr", err)
+	}
+}
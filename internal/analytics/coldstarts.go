@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// coldStartReason extracts the reason of a "Starting new instance" message,
+// e.g. "Starting new instance. Reason: AUTOSCALING - Instance started..."
+var coldStartReason = regexp.MustCompile(`(?i)reason:\s*([A-Z_]+)`)
+
+// ColdStart is the start of a new instance of a revision
+type ColdStart struct {
+	Service    string
+	Revision   string
+	InstanceID string
+	StartedAt  time.Time
+	// Why the instance was started, e.g. DEPLOYMENT or AUTOSCALING
+	Reason string
+	// Time from the start of the instance to its successful startup probe;
+	// zero when the probe was not found
+	StartupLatency time.Duration
+	// Latency of the first request served by the instance; zero when the
+	// request was not found
+	FirstRequestLatency time.Duration
+}
+
+// DetectColdStarts finds the cold starts reported by Cloud Run system log
+// entries ("Starting new instance"). The startup latency of an instance is
+// the time until its startup probe succeeded, and the latency of its first
+// request is read from the request log entries. Entries are matched by the
+// instanceId label, or by revision when it is missing.
+func DetectColdStarts(entries []model.LogEntry) []ColdStart {
+	sorted := make([]model.LogEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	var starts []ColdStart
+	// Index in starts of the latest start of an instance waiting for its
+	// startup probe, and for its first request
+	probing := make(map[string]int)
+	serving := make(map[string]int)
+	for _, entry := range sorted {
+		key := coldStartKey(entry)
+		if req := entry.HTTPRequest; req != nil {
+			if i, ok := serving[key]; ok {
+				starts[i].FirstRequestLatency = req.Latency
+				delete(serving, key)
+			}
+			continue
+		}
+
+		message := strings.ToLower(entry.Message)
+		switch {
+		case strings.Contains(message, "starting new instance"):
+			start := ColdStart{
+				Service:    entry.ResourceLabels["service_name"],
+				Revision:   entry.ResourceLabels["revision_name"],
+				InstanceID: entry.Labels["instanceId"],
+				StartedAt:  entry.Timestamp,
+			}
+			if m := coldStartReason.FindStringSubmatch(entry.Message); m != nil {
+				start.Reason = strings.ToUpper(m[1])
+			}
+			starts = append(starts, start)
+			probing[key] = len(starts) - 1
+			serving[key] = len(starts) - 1
+		case strings.Contains(message, "startup") && strings.Contains(message, "probe succeeded"):
+			if i, ok := probing[key]; ok {
+				starts[i].StartupLatency = entry.Timestamp.Sub(starts[i].StartedAt)
+				delete(probing, key)
+			}
+		}
+	}
+	return starts
+}
+
+// coldStartKey identifies the instance that wrote an entry
+func coldStartKey(entry model.LogEntry) string {
+	if id := entry.Labels["instanceId"]; id != "" {
+		return id
+	}
+	return entry.ResourceLabels["service_name"] + "/" + entry.ResourceLabels["revision_name"]
+}
+
+// ColdStartSummary describes the cold starts of a revision or a service
+type ColdStartSummary struct {
+	// Revision or service the summary is about
+	Name  string
+	Count int
+	// Average number of cold starts per hour over the window
+	PerHour float64
+	// Startup latency percentiles of the instances whose probe was found
+	StartupP50 time.Duration
+	StartupP95 time.Duration
+	// Median latency of the first requests of the new instances
+	FirstRequestP50 time.Duration
+	LastStart       time.Time
+}
+
+// SummarizeColdStartsByRevision summarizes cold starts by revision, most
+// frequent first, over a window used to compute their frequency
+func SummarizeColdStartsByRevision(starts []ColdStart, window time.Duration) []ColdStartSummary {
+	return summarizeColdStarts(starts, window, func(start ColdStart) string { return start.Revision })
+}
+
+// SummarizeColdStartsByService summarizes cold starts by service, most
+// frequent first, over a window used to compute their frequency
+func SummarizeColdStartsByService(starts []ColdStart, window time.Duration) []ColdStartSummary {
+	return summarizeColdStarts(starts, window, func(start ColdStart) string { return start.Service })
+}
+
+// summarizeColdStarts groups cold starts by the name returned by group
+func summarizeColdStarts(starts []ColdStart, window time.Duration, group func(ColdStart) string) []ColdStartSummary {
+	byName := make(map[string][]ColdStart)
+	for _, start := range starts {
+		name := group(start)
+		byName[name] = append(byName[name], start)
+	}
+
+	summaries := make([]ColdStartSummary, 0, len(byName))
+	for name, starts := range byName {
+		summary := ColdStartSummary{Name: name, Count: len(starts)}
+		if window > 0 {
+			summary.PerHour = float64(len(starts)) / window.Hours()
+		}
+
+		var startup, firstRequest []time.Duration
+		for _, start := range starts {
+			if start.StartupLatency > 0 {
+				startup = append(startup, start.StartupLatency)
+			}
+			if start.FirstRequestLatency > 0 {
+				firstRequest = append(firstRequest, start.FirstRequestLatency)
+			}
+			if start.StartedAt.After(summary.LastStart) {
+				summary.LastStart = start.StartedAt
+			}
+		}
+		sort.Slice(startup, func(i, j int) bool { return startup[i] < startup[j] })
+		sort.Slice(firstRequest, func(i, j int) bool { return firstRequest[i] < firstRequest[j] })
+		summary.StartupP50 = Percentile(startup, 50)
+		summary.StartupP95 = Percentile(startup, 95)
+		summary.FirstRequestP50 = Percentile(firstRequest, 50)
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Count != summaries[j].Count {
+			return summaries[i].Count > summaries[j].Count
+		}
+		return summaries[i].Name < summaries[j].Name
+	})
+	return summaries
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func systemLog(ago time.Duration, revision, instance, message string) model.LogEntry {
+	return model.LogEntry{
+		Timestamp:      now.Add(-ago),
+		Message:        message,
+		ResourceLabels: map[string]string{"service_name": "api", "revision_name": revision},
+		Labels:         map[string]string{"instanceId": instance},
+	}
+}
+
+func TestDetectColdStarts(t *testing.T) {
+	firstRequest := request(55*time.Minute, 200, 800*time.Millisecond, "/")
+	firstRequest.Labels = map[string]string{"instanceId": "i1"}
+	secondRequest := request(50*time.Minute, 200, 20*time.Millisecond, "/")
+	secondRequest.Labels = map[string]string{"instanceId": "i1"}
+
+	entries := []model.LogEntry{
+		// Out of order on purpose: the detection sorts the entries
+		secondRequest,
+		firstRequest,
+		systemLog(time.Hour-1500*time.Millisecond, "api-00002", "i1", `Default STARTUP TCP probe succeeded after 1 attempt for container "app" on port 8080.`),
+		systemLog(time.Hour, "api-00002", "i1", "Starting new instance. Reason: AUTOSCALING - Instance started due to configured scaling factors."),
+		systemLog(30*time.Minute, "api-00002", "i2", "Starting new instance. Reason: DEPLOYMENT - Instance started due to traffic shifting between revisions."),
+		systemLog(10*time.Minute, "api-00001", "i3", "Starting new instance. Reason: AUTOSCALING - Instance started due to configured scaling factors."),
+		systemLog(9*time.Minute, "api-00001", "i3", `Default STARTUP TCP probe succeeded after 2 attempts for container "app" on port 8080.`),
+		systemLog(5*time.Minute, "api-00001", "i3", "Container called exit(0)."),
+	}
+
+	starts := DetectColdStarts(entries)
+	if len(starts) != 3 {
+		t.Fatalf("DetectColdStarts() found %d cold starts, want 3: %+v", len(starts), starts)
+	}
+
+	first := starts[0]
+	if first.InstanceID != "i1" || first.Revision != "api-00002" || first.Reason != "AUTOSCALING" {
+		t.Errorf("first cold start = %+v", first)
+	}
+	if first.StartupLatency != 1500*time.Millisecond || first.FirstRequestLatency != 800*time.Millisecond {
+		t.Errorf("first cold start latencies = %v, %v, want 1.5s, 800ms", first.StartupLatency, first.FirstRequestLatency)
+	}
+	if second := starts[1]; second.Reason != "DEPLOYMENT" || second.StartupLatency != 0 || second.FirstRequestLatency != 0 {
+		t.Errorf("second cold start = %+v, want no latencies", second)
+	}
+	if third := starts[2]; third.StartupLatency != time.Minute {
+		t.Errorf("third cold start latency = %v, want 1m", third.StartupLatency)
+	}
+}
+
+func TestSummarizeColdStarts(t *testing.T) {
+	starts := []ColdStart{
+		{Service: "api", Revision: "api-00002", StartedAt: now.Add(-3 * time.Hour), StartupLatency: time.Second},
+		{Service: "api", Revision: "api-00002", StartedAt: now.Add(-2 * time.Hour), StartupLatency: 3 * time.Second, FirstRequestLatency: time.Second},
+		{Service: "api", Revision: "api-00002", StartedAt: now.Add(-time.Hour)},
+		{Service: "api", Revision: "api-00001", StartedAt: now.Add(-4 * time.Hour), StartupLatency: 2 * time.Second},
+	}
+
+	byRevision := SummarizeColdStartsByRevision(starts, 4*time.Hour)
+	if len(byRevision) != 2 {
+		t.Fatalf("SummarizeColdStartsByRevision() = %+v, want two revisions", byRevision)
+	}
+	latest := byRevision[0]
+	if latest.Name != "api-00002" || latest.Count != 3 || latest.PerHour != 0.75 {
+		t.Errorf("latest revision summary = %+v", latest)
+	}
+	if latest.StartupP50 != time.Second || latest.StartupP95 != 3*time.Second || latest.FirstRequestP50 != time.Second {
+		t.Errorf("latest revision latencies = %v, %v, %v", latest.StartupP50, latest.StartupP95, latest.FirstRequestP50)
+	}
+	if !latest.LastStart.Equal(now.Add(-time.Hour)) {
+		t.Errorf("latest revision last start = %v", latest.LastStart)
+	}
+
+	byService := SummarizeColdStartsByService(starts, 4*time.Hour)
+	if len(byService) != 1 || byService[0].Name != "api" || byService[0].Count != 4 || byService[0].PerHour != 1 {
+		t.Errorf("SummarizeColdStartsByService() = %+v", byService)
+	}
+}
@@ -0,0 +1,153 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// SeverityCounts counts log entries by severity class
+type SeverityCounts struct {
+	// Entries of severity ERROR and above
+	Errors int
+	// Entries of severity WARNING
+	Warnings int
+	// Entries of a lower or unknown severity
+	Others int
+}
+
+// Total returns the number of entries of all severities
+func (c SeverityCounts) Total() int {
+	return c.Errors + c.Warnings + c.Others
+}
+
+// add adds delta entries of a severity
+func (c *SeverityCounts) add(severity string, delta int) {
+	switch rank := model.SeverityRank(severity); {
+	case rank >= model.SeverityRank(model.SeverityError):
+		c.Errors += delta
+	case rank >= model.SeverityRank(model.SeverityWarning):
+		c.Warnings += delta
+	default:
+		c.Others += delta
+	}
+}
+
+// HistogramBin is the number of entries by severity received during [Start, End)
+type HistogramBin struct {
+	Start time.Time
+	End   time.Time
+	SeverityCounts
+}
+
+// SeverityHistogram counts log entries by severity in fixed-width time buckets.
+// Entries can be removed as well as added, so it can follow a bounded buffer.
+type SeverityHistogram struct {
+	width   time.Duration
+	buckets map[int64]*SeverityCounts
+	// Indexes of the oldest and newest non-empty buckets
+	first, last int64
+}
+
+// NewSeverityHistogram creates an empty histogram of buckets of the given width
+func NewSeverityHistogram(width time.Duration) *SeverityHistogram {
+	return &SeverityHistogram{
+		width:   width,
+		buckets: make(map[int64]*SeverityCounts),
+	}
+}
+
+// Add counts an entry of a severity received at t
+func (h *SeverityHistogram) Add(t time.Time, severity string) {
+	index := h.index(t)
+	bucket, ok := h.buckets[index]
+	if !ok {
+		if len(h.buckets) == 0 || index < h.first {
+			h.first = index
+		}
+		if len(h.buckets) == 0 || index > h.last {
+			h.last = index
+		}
+		bucket = &SeverityCounts{}
+		h.buckets[index] = bucket
+	}
+	bucket.add(severity, 1)
+}
+
+// Remove forgets an entry previously added with the same time and severity
+func (h *SeverityHistogram) Remove(t time.Time, severity string) {
+	index := h.index(t)
+	bucket, ok := h.buckets[index]
+	if !ok {
+		return
+	}
+	bucket.add(severity, -1)
+	if bucket.Total() > 0 {
+		return
+	}
+
+	delete(h.buckets, index)
+	if index == h.first || index == h.last {
+		h.bounds()
+	}
+}
+
+// Reset removes all the entries
+func (h *SeverityHistogram) Reset() {
+	h.buckets = make(map[int64]*SeverityCounts)
+}
+
+// Bins returns at most n bins covering the buckets from the oldest to the
+// newest entry, oldest first. Each bin groups the same whole number of buckets,
+// and the last one ends with the bucket of the newest entry.
+func (h *SeverityHistogram) Bins(n int) []HistogramBin {
+	if len(h.buckets) == 0 || n < 1 {
+		return nil
+	}
+
+	span := h.last - h.first + 1
+	per := (span + int64(n) - 1) / int64(n)
+	count := (span + per - 1) / per
+	start := h.last + 1 - count*per
+
+	bins := make([]HistogramBin, count)
+	for i := range bins {
+		bins[i].Start = h.time(start + int64(i)*per)
+		bins[i].End = h.time(start + int64(i+1)*per)
+	}
+	for index, bucket := range h.buckets {
+		bin := &bins[(index-start)/per]
+		bin.Errors += bucket.Errors
+		bin.Warnings += bucket.Warnings
+		bin.Others += bucket.Others
+	}
+	return bins
+}
+
+// index returns the index of the bucket holding t
+func (h *SeverityHistogram) index(t time.Time) int64 {
+	index := t.UnixNano() / int64(h.width)
+	if t.UnixNano()%int64(h.width) < 0 {
+		index-- // Round down before the epoch
+	}
+	return index
+}
+
+// time returns the start of a bucket
+func (h *SeverityHistogram) time(index int64) time.Time {
+	return time.Unix(0, index*int64(h.width))
+}
+
+// bounds recomputes the indexes of the oldest and newest buckets
+func (h *SeverityHistogram) bounds() {
+	first := true
+	for index := range h.buckets {
+		if first || index < h.first {
+			h.first = index
+		}
+		if first || index > h.last {
+			h.last = index
+		}
+		first = false
+	}
+}
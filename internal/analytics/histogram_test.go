@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestSeverityHistogramBins(t *testing.T) {
+	h := NewSeverityHistogram(time.Minute)
+	h.Add(now.Add(-4*time.Minute+time.Second), model.SeverityInfo)
+	h.Add(now.Add(-3*time.Minute+time.Second), model.SeverityError)
+	h.Add(now.Add(-3*time.Minute+2*time.Second), model.SeverityCritical)
+	h.Add(now.Add(time.Second), model.SeverityWarning)
+	h.Add(now.Add(2*time.Second), "")
+
+	got := h.Bins(10)
+	want := []HistogramBin{
+		{Start: now.Add(-4 * time.Minute), End: now.Add(-3 * time.Minute), SeverityCounts: SeverityCounts{Others: 1}},
+		{Start: now.Add(-3 * time.Minute), End: now.Add(-2 * time.Minute), SeverityCounts: SeverityCounts{Errors: 2}},
+		{Start: now.Add(-2 * time.Minute), End: now.Add(-1 * time.Minute)},
+		{Start: now.Add(-1 * time.Minute), End: now},
+		{Start: now, End: now.Add(time.Minute), SeverityCounts: SeverityCounts{Warnings: 1, Others: 1}},
+	}
+	if !equalBins(got, want) {
+		t.Errorf("Bins(10) =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// Five minutes in two bins: the newest bins are full, the oldest starts earlier
+	got = h.Bins(2)
+	want = []HistogramBin{
+		{Start: now.Add(-5 * time.Minute), End: now.Add(-2 * time.Minute), SeverityCounts: SeverityCounts{Errors: 2, Others: 1}},
+		{Start: now.Add(-2 * time.Minute), End: now.Add(time.Minute), SeverityCounts: SeverityCounts{Warnings: 1, Others: 1}},
+	}
+	if !equalBins(got, want) {
+		t.Errorf("Bins(2) =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestSeverityHistogramRemove(t *testing.T) {
+	h := NewSeverityHistogram(time.Minute)
+	oldest := now.Add(-2 * time.Minute)
+	h.Add(oldest, model.SeverityError)
+	h.Add(now, model.SeverityInfo)
+
+	h.Remove(oldest, model.SeverityError)
+	got := h.Bins(10)
+	want := []HistogramBin{
+		{Start: now, End: now.Add(time.Minute), SeverityCounts: SeverityCounts{Others: 1}},
+	}
+	if !equalBins(got, want) {
+		t.Errorf("Bins() after Remove =\n%+v\nwant\n%+v", got, want)
+	}
+
+	h.Reset()
+	if bins := h.Bins(10); bins != nil {
+		t.Errorf("Bins() after Reset = %+v, want nil", bins)
+	}
+}
+
+// equalBins compares bins regardless of the location of their times
+func equalBins(got, want []HistogramBin) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) ||
+			!reflect.DeepEqual(got[i].SeverityCounts, want[i].SeverityCounts) {
+			return false
+		}
+	}
+	return true
+}
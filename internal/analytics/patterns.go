@@ -0,0 +1,112 @@
+package analytics
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Placeholders replacing the variable parts of a message in its template
+const (
+	MaskUUID   = "<uuid>"
+	MaskHex    = "<hex>"
+	MaskNumber = "<num>"
+)
+
+var (
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexPattern    = regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`)
+	numberPattern = regexp.MustCompile(`\d+(?:\.\d+)?`)
+	spacePattern  = regexp.MustCompile(`\s+`)
+)
+
+// Template returns the template of a log message: UUIDs, hexadecimal values
+// and numbers are masked so that messages differing only by them share a template
+func Template(message string) string {
+	template := uuidPattern.ReplaceAllString(message, MaskUUID)
+	template = hexPattern.ReplaceAllStringFunc(template, func(s string) string {
+		// Long runs of digits are numbers, hex values have letters or a 0x prefix
+		if strings.HasPrefix(strings.ToLower(s), "0x") || strings.IndexFunc(s, isHexLetter) >= 0 && strings.IndexFunc(s, isDigit) >= 0 {
+			return MaskHex
+		}
+		return s
+	})
+	template = numberPattern.ReplaceAllString(template, MaskNumber)
+	return spacePattern.ReplaceAllString(strings.TrimSpace(template), " ")
+}
+
+func isHexLetter(r rune) bool {
+	return r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// Pattern is a group of log messages sharing a template
+type Pattern struct {
+	Template string
+	Count    int
+	// Timestamps of the oldest and newest messages of the pattern
+	FirstSeen time.Time
+	LastSeen  time.Time
+	// Highest severity of the messages of the pattern
+	Severity string
+	// Latest message of the pattern
+	Example string
+}
+
+// PatternSet clusters log messages by template
+type PatternSet struct {
+	patterns map[string]*Pattern
+}
+
+// NewPatternSet creates an empty pattern set
+func NewPatternSet() *PatternSet {
+	return &PatternSet{patterns: make(map[string]*Pattern)}
+}
+
+// Add adds a message with its severity and timestamp to the pattern of its template
+func (s *PatternSet) Add(message, severity string, timestamp time.Time) {
+	template := Template(message)
+	p, ok := s.patterns[template]
+	if !ok {
+		p = &Pattern{Template: template, FirstSeen: timestamp, LastSeen: timestamp, Severity: severity, Example: message}
+		s.patterns[template] = p
+	}
+
+	p.Count++
+	if timestamp.Before(p.FirstSeen) {
+		p.FirstSeen = timestamp
+	}
+	if !timestamp.Before(p.LastSeen) {
+		p.LastSeen = timestamp
+		p.Example = message
+	}
+	if model.SeverityRank(severity) > model.SeverityRank(p.Severity) {
+		p.Severity = severity
+	}
+}
+
+// Len returns the number of patterns
+func (s *PatternSet) Len() int {
+	return len(s.patterns)
+}
+
+// Patterns returns the patterns, most frequent first, ties ordered by template
+func (s *PatternSet) Patterns() []Pattern {
+	patterns := make([]Pattern, 0, len(s.patterns))
+	for _, p := range s.patterns {
+		patterns = append(patterns, *p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		return patterns[i].Template < patterns[j].Template
+	})
+	return patterns
+}
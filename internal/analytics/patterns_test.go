@@ -0,0 +1,61 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestTemplate(t *testing.T) {
+	tests := map[string]string{
+		"GET /users/42 took 12.5ms":                            "GET /users/<num> took <num>ms",
+		"order 3f2b8c1e-9a7d-4e5f-8b6a-1c2d3e4f5a6b not found": "order <uuid> not found",
+		"pointer 0xc000123abc freed":                           "pointer <hex> freed",
+		"commit a1b2c3d4e5f6 deployed":                         "commit <hex> deployed",
+		"request id 1234567890 accepted":                       "request id <num> accepted",
+		"  retrying   in 5s  ":                                 "retrying in <num>s",
+		"connection refused":                                   "connection refused",
+		"user deadbeefcafe logged in":                          "user deadbeefcafe logged in",
+	}
+	for message, want := range tests {
+		if got := Template(message); got != want {
+			t.Errorf("Template(%q) = %q, want %q", message, got, want)
+		}
+	}
+}
+
+func TestPatternSet(t *testing.T) {
+	set := NewPatternSet()
+	set.Add("GET /users/1 took 10ms", model.SeverityInfo, now.Add(-3*time.Minute))
+	set.Add("database timeout after 30s", model.SeverityError, now.Add(-2*time.Minute))
+	set.Add("GET /users/2 took 20ms", model.SeverityWarning, now.Add(-time.Minute))
+	set.Add("GET /users/3 took 5ms", model.SeverityInfo, now.Add(-4*time.Minute))
+
+	if set.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", set.Len())
+	}
+
+	want := []Pattern{
+		{
+			Template:  "GET /users/<num> took <num>ms",
+			Count:     3,
+			FirstSeen: now.Add(-4 * time.Minute),
+			LastSeen:  now.Add(-time.Minute),
+			Severity:  model.SeverityWarning,
+			Example:   "GET /users/2 took 20ms",
+		},
+		{
+			Template:  "database timeout after <num>s",
+			Count:     1,
+			FirstSeen: now.Add(-2 * time.Minute),
+			LastSeen:  now.Add(-2 * time.Minute),
+			Severity:  model.SeverityError,
+			Example:   "database timeout after 30s",
+		},
+	}
+	if got := set.Patterns(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Patterns() =\n%+v\nwant\n%+v", got, want)
+	}
+}
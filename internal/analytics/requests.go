@@ -0,0 +1,166 @@
+// Package analytics aggregates log entries into health indicators: latency
+// percentiles, status classes, throughput and failing paths of requests, the
+// cold starts of instances and the patterns of log messages. It has no dependency on the UI or on a cloud provider.
+package analytics
+
+import (
+	"math"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Status classes of HTTP responses
+const (
+	Class2xx = "2xx"
+	Class3xx = "3xx"
+	Class4xx = "4xx"
+	Class5xx = "5xx"
+	// ClassOther groups informational responses and requests without a status
+	ClassOther = "other"
+)
+
+// StatusClasses lists the status classes in display order
+var StatusClasses = []string{Class2xx, Class3xx, Class4xx, Class5xx, ClassOther}
+
+// PathCount is the number of failed requests for a path
+type PathCount struct {
+	Path  string
+	Count int
+}
+
+// RequestSummary describes the requests served during a window
+type RequestSummary struct {
+	// Bounds of the window the summary covers
+	From time.Time
+	To   time.Time
+	// Number of requests in the window
+	Total int
+	// Latency percentiles, using the nearest-rank method
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+	// Number of requests by status class
+	StatusClasses map[string]int
+	// Average number of requests per minute over the window
+	RequestsPerMinute float64
+	// Paths with the most 5xx responses, most failing first
+	TopFailingPaths []PathCount
+}
+
+// ErrorRate returns the fraction of requests answered with a 5xx status
+func (s RequestSummary) ErrorRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.StatusClasses[Class5xx]) / float64(s.Total)
+}
+
+// SummarizeRequests summarizes the requests received during the window ending
+// at now. A zero window covers all the entries, from the oldest to now.
+// Entries without an HTTP request are ignored. At most topN failing paths are kept.
+func SummarizeRequests(entries []model.LogEntry, window time.Duration, now time.Time, topN int) RequestSummary {
+	summary := RequestSummary{
+		To:            now,
+		StatusClasses: make(map[string]int, len(StatusClasses)),
+	}
+	if window > 0 {
+		summary.From = now.Add(-window)
+	}
+
+	var latencies []time.Duration
+	failures := make(map[string]int)
+	for _, entry := range entries {
+		req := entry.HTTPRequest
+		if req == nil || entry.Timestamp.After(now) {
+			continue
+		}
+		if window > 0 && !entry.Timestamp.After(summary.From) {
+			continue
+		}
+		if window == 0 && (summary.From.IsZero() || entry.Timestamp.Before(summary.From)) {
+			summary.From = entry.Timestamp
+		}
+
+		summary.Total++
+		latencies = append(latencies, req.Latency)
+		class := StatusClass(req.Status)
+		summary.StatusClasses[class]++
+		if class == Class5xx {
+			failures[RequestPath(req.URL)]++
+		}
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	summary.P50 = Percentile(latencies, 50)
+	summary.P95 = Percentile(latencies, 95)
+	summary.P99 = Percentile(latencies, 99)
+
+	if minutes := summary.To.Sub(summary.From).Minutes(); summary.Total > 0 && minutes > 0 {
+		summary.RequestsPerMinute = float64(summary.Total) / minutes
+	}
+
+	summary.TopFailingPaths = topPaths(failures, topN)
+	return summary
+}
+
+// Percentile returns the pth percentile (0-100) of latencies sorted in
+// ascending order, using the nearest-rank method. It returns zero when there
+// are no latencies.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// StatusClass returns the class of an HTTP status code
+func StatusClass(status int) string {
+	switch {
+	case status >= 200 && status < 300:
+		return Class2xx
+	case status >= 300 && status < 400:
+		return Class3xx
+	case status >= 400 && status < 500:
+		return Class4xx
+	case status >= 500 && status < 600:
+		return Class5xx
+	}
+	return ClassOther
+}
+
+// RequestPath returns the path of a request URL, or the URL itself when it has no path
+func RequestPath(requestURL string) string {
+	u, err := url.Parse(requestURL)
+	if err != nil || u.Path == "" {
+		return requestURL
+	}
+	return u.Path
+}
+
+// topPaths returns the n paths with the highest counts, ties ordered by path
+func topPaths(counts map[string]int, n int) []PathCount {
+	paths := make([]PathCount, 0, len(counts))
+	for path, count := range counts {
+		paths = append(paths, PathCount{Path: path, Count: count})
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if paths[i].Count != paths[j].Count {
+			return paths[i].Count > paths[j].Count
+		}
+		return paths[i].Path < paths[j].Path
+	})
+	if n >= 0 && len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
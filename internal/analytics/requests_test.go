@@ -0,0 +1,168 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func request(ago time.Duration, status int, latency time.Duration, url string) model.LogEntry {
+	return model.LogEntry{
+		Timestamp: now.Add(-ago),
+		HTTPRequest: &model.HTTPRequest{
+			Method:  "GET",
+			URL:     url,
+			Status:  status,
+			Latency: latency,
+		},
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, v := range values {
+			durations[i] = time.Duration(v) * time.Millisecond
+		}
+		return durations
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{"empty", nil, 50, 0},
+		{"single", ms(7), 99, 7 * time.Millisecond},
+		{"median of four", ms(1, 2, 3, 4), 50, 2 * time.Millisecond},
+		{"p95 of twenty", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20), 95, 19 * time.Millisecond},
+		{"p99 of ten", ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10), 99, 10 * time.Millisecond},
+		{"p0 is the minimum", ms(3, 5), 0, 3 * time.Millisecond},
+		{"p100 is the maximum", ms(3, 5), 100, 5 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Percentile(tt.sorted, tt.p); got != tt.want {
+				t.Errorf("Percentile(%v, %v) = %v, want %v", tt.sorted, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{
+		0:   ClassOther,
+		101: ClassOther,
+		200: Class2xx,
+		204: Class2xx,
+		301: Class3xx,
+		404: Class4xx,
+		499: Class4xx,
+		500: Class5xx,
+		503: Class5xx,
+		600: ClassOther,
+	}
+	for status, want := range tests {
+		if got := StatusClass(status); got != want {
+			t.Errorf("StatusClass(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestRequestPath(t *testing.T) {
+	tests := map[string]string{
+		"https://api.example.com/v1/users?id=3": "/v1/users",
+		"https://api.example.com":               "https://api.example.com",
+		"/health":                               "/health",
+		"":                                      "",
+	}
+	for url, want := range tests {
+		if got := RequestPath(url); got != want {
+			t.Errorf("RequestPath(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestSummarizeRequests(t *testing.T) {
+	entries := []model.LogEntry{
+		request(1*time.Minute, 200, 10*time.Millisecond, "https://svc/a"),
+		request(2*time.Minute, 200, 20*time.Millisecond, "https://svc/a"),
+		request(3*time.Minute, 302, 30*time.Millisecond, "https://svc/login"),
+		request(4*time.Minute, 404, 40*time.Millisecond, "https://svc/missing"),
+		request(5*time.Minute, 500, 500*time.Millisecond, "https://svc/pay"),
+		request(6*time.Minute, 503, 900*time.Millisecond, "https://svc/pay"),
+		request(7*time.Minute, 500, 700*time.Millisecond, "https://svc/cart"),
+		request(8*time.Minute, 200, 15*time.Millisecond, "https://svc/a"),
+		// Outside a 10 minute window
+		request(30*time.Minute, 500, time.Second, "https://svc/old"),
+		// Not a request
+		{Timestamp: now.Add(-time.Minute), Message: "hello"},
+		// In the future of the window
+		request(-time.Minute, 500, time.Second, "https://svc/future"),
+	}
+
+	got := SummarizeRequests(entries, 10*time.Minute, now, 2)
+
+	want := RequestSummary{
+		From:  now.Add(-10 * time.Minute),
+		To:    now,
+		Total: 8,
+		P50:   30 * time.Millisecond,
+		P95:   900 * time.Millisecond,
+		P99:   900 * time.Millisecond,
+		StatusClasses: map[string]int{
+			Class2xx: 3,
+			Class3xx: 1,
+			Class4xx: 1,
+			Class5xx: 3,
+		},
+		RequestsPerMinute: 0.8,
+		TopFailingPaths: []PathCount{
+			{Path: "/pay", Count: 2},
+			{Path: "/cart", Count: 1},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SummarizeRequests() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if rate := got.ErrorRate(); rate != 3.0/8.0 {
+		t.Errorf("ErrorRate() = %v, want %v", rate, 3.0/8.0)
+	}
+}
+
+func TestSummarizeRequestsAllEntries(t *testing.T) {
+	entries := []model.LogEntry{
+		request(2*time.Minute, 200, 10*time.Millisecond, "https://svc/a"),
+		request(30*time.Minute, 500, 20*time.Millisecond, "https://svc/b"),
+	}
+
+	got := SummarizeRequests(entries, 0, now, 5)
+
+	if got.Total != 2 {
+		t.Errorf("Total = %d, want 2", got.Total)
+	}
+	if want := now.Add(-30 * time.Minute); !got.From.Equal(want) {
+		t.Errorf("From = %v, want the oldest entry %v", got.From, want)
+	}
+	if want := 2.0 / 30.0; got.RequestsPerMinute != want {
+		t.Errorf("RequestsPerMinute = %v, want %v", got.RequestsPerMinute, want)
+	}
+}
+
+func TestSummarizeRequestsEmpty(t *testing.T) {
+	got := SummarizeRequests(nil, time.Hour, now, 5)
+
+	if got.Total != 0 || got.P50 != 0 || got.RequestsPerMinute != 0 || len(got.TopFailingPaths) != 0 {
+		t.Errorf("SummarizeRequests(nil) = %+v, want an empty summary", got)
+	}
+	if got.ErrorRate() != 0 {
+		t.Errorf("ErrorRate() = %v, want 0", got.ErrorRate())
+	}
+}
@@ -0,0 +1,65 @@
+package analytics
+
+import "strings"
+
+// SystemEvent is a kind of Cloud Run platform event found in the system logs
+type SystemEvent int
+
+const (
+	NoSystemEvent SystemEvent = iota
+	// An instance started, or passed its startup probe
+	InstanceStart
+	// An instance stopped: its container exited, was terminated or shut down
+	InstanceStop
+	// A startup or liveness probe failed
+	ProbeFailure
+	// A container exceeded its memory limit and was killed
+	OutOfMemory
+	// An instance was started or stopped by the autoscaler
+	Scaling
+)
+
+// String returns the short label of an event
+func (e SystemEvent) String() string {
+	switch e {
+	case InstanceStart:
+		return "START"
+	case InstanceStop:
+		return "STOP"
+	case ProbeFailure:
+		return "PROBE"
+	case OutOfMemory:
+		return "OOM"
+	case Scaling:
+		return "SCALE"
+	}
+	return ""
+}
+
+// systemEventPatterns are lowercase fragments of the system log messages of
+// each event, by decreasing importance: a scaling start is a scaling event,
+// a probe failure stopping an instance is a probe failure
+var systemEventPatterns = []struct {
+	event     SystemEvent
+	fragments []string
+}{
+	{OutOfMemory, []string{"memory limit of", "out of memory", "outofmemory", "oomkilled", "oom kill"}},
+	{ProbeFailure, []string{"probe failed", "probe timed out"}},
+	{Scaling, []string{"reason: autoscaling", "autoscal", "scaled down", "scaled up", "scaling"}},
+	{InstanceStop, []string{"container called exit", "container terminated", "shutting down", "instance was shut down", "terminating"}},
+	{InstanceStart, []string{"starting new instance", "probe succeeded", "container started", "ready condition status changed to true"}},
+}
+
+// ClassifySystemMessage returns the event a Cloud Run system log message
+// reports, or NoSystemEvent
+func ClassifySystemMessage(message string) SystemEvent {
+	lower := strings.ToLower(message)
+	for _, p := range systemEventPatterns {
+		for _, fragment := range p.fragments {
+			if strings.Contains(lower, fragment) {
+				return p.event
+			}
+		}
+	}
+	return NoSystemEvent
+}
@@ -0,0 +1,23 @@
+package analytics
+
+import "testing"
+
+func TestClassifySystemMessage(t *testing.T) {
+	tests := map[string]SystemEvent{
+		"Starting new instance. Reason: DEPLOYMENT - Instance started due to traffic shifting between revisions.": InstanceStart,
+		"Starting new instance. Reason: AUTOSCALING - Instance started due to configured scaling factors.":        Scaling,
+		`Default STARTUP TCP probe succeeded after 1 attempt for container "app" on port 8080.`:                   InstanceStart,
+		`Default STARTUP TCP probe failed 1 time consecutively for container "app" on port 8080.`:                 ProbeFailure,
+		"LIVENESS HTTP probe failed 3 times consecutively for container \"app\" on path \"/healthz\".":            ProbeFailure,
+		"Memory limit of 512 MiB exceeded with 530 MiB used. Consider increasing the memory limit.":               OutOfMemory,
+		"Container called exit(1).":            InstanceStop,
+		"Container terminated on signal 9.":    InstanceStop,
+		"Shutting down user disabled instance": InstanceStop,
+		"Application exec likely failed":       NoSystemEvent,
+	}
+	for message, want := range tests {
+		if got := ClassifySystemMessage(message); got != want {
+			t.Errorf("ClassifySystemMessage(%q) = %v, want %v", message, got, want)
+		}
+	}
+}
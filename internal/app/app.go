@@ -2,8 +2,12 @@ package app
 
 import (
 	"log"
+	"os"
 
+	"github.com/lpmourato/c9s/internal/alerts"
+	"github.com/lpmourato/c9s/internal/buildinfo"
 	"github.com/lpmourato/c9s/internal/cli"
+	"github.com/lpmourato/c9s/internal/completion"
 	"github.com/lpmourato/c9s/internal/config"
 	"github.com/lpmourato/c9s/internal/datasource"
 	"github.com/lpmourato/c9s/internal/mock"
@@ -12,7 +16,8 @@ import (
 )
 
 type App struct {
-	cli *cli.CLI
+	cli   *cli.CLI
+	build buildinfo.Info
 }
 
 func New() *App {
@@ -21,39 +26,64 @@ func New() *App {
 	}
 }
 
+// WithVersion sets the build information reported by the version command and the UI
+func (a *App) WithVersion(build buildinfo.Info) *App {
+	a.build = build
+	return a
+}
+
 func (a *App) Run() error {
+	// Shell completion scripts call c9s back with the words to complete
+	if len(os.Args) > 1 && os.Args[1] == completion.Command {
+		return a.runComplete(os.Stdout, os.Args[2:])
+	}
+
 	ctx, err := a.cli.Parse()
 	if err != nil {
 		return err
 	}
 
-	dsFlag := ctx.Command()
+	command := cli.CommandName(ctx.Command())
 
-	app := ui.NewApp()
-	cfg := &config.CloudRunConfig{
-		ProjectID: a.cli.Project,
-		Region:    a.cli.Region,
+	// Subcommands printing to stdout run without the UI
+	switch command {
+	case cli.LogsCommand:
+		return a.runLogs()
+	case cli.ListCommand:
+		return a.runList()
+	case cli.DescribeCommand:
+		return a.runDescribe()
+	case cli.VersionCommand:
+		return a.runVersion()
+	case cli.DoctorCommand:
+		return a.runDoctor()
+	case cli.CompletionCommand:
+		return a.runCompletion()
 	}
-	dsConfig := &datasource.Config{
-		ProjectID:  cfg.ProjectID,
-		Region:     cfg.Region,
-		MockedData: mock.GetDefaultServices(),
+
+	dsName := a.cli.DatasourceName(command)
+	ds, err := a.newDataSource(dsName)
+	if err != nil {
+		log.Fatalf("Error creating data source: %v", err)
 	}
 
-	// Map flag to datasource.Type
-	switch dsFlag {
-	case "mock":
-		dsConfig.Type = datasource.Mock
-	default:
-		dsConfig.Type = datasource.GCP
+	app := ui.NewApp()
+	cfg := &config.CloudRunConfig{
+		ProjectID:     a.cli.Project,
+		Region:        a.cli.Region,
+		LogSince:      a.cli.Since,
+		LogUntil:      a.cli.Until,
+		LogBufferSize: a.cli.LogBuffer,
+		Version:       a.build.Version,
+		CacheServices: dsName != string(datasource.Mock),
 	}
 
-	ds, err := datasource.Factory(dsConfig)
+	rules, err := a.alertRules()
 	if err != nil {
-		log.Fatalf("Error creating data source: %v", err)
+		log.Fatalf("Error loading alert rules: %v", err)
 	}
 
-	views.NewCloudRunView(app, cfg, ds)
+	views.NewCloudRunView(app, cfg, ds).SetAlertRules(rules)
 
 	if err := app.Run(); err != nil {
 		log.Fatalf("Error running application: %v", err)
@@ -61,3 +91,44 @@ func (a *App) Run() error {
 
 	return nil
 }
+
+// configFile loads the configuration file. Without --config a missing
+// file in the default location is an empty configuration.
+func (a *App) configFile() (*config.File, error) {
+	path := a.cli.Config
+	if path == "" {
+		path = config.DefaultPath()
+		if _, err := os.Stat(path); path == "" || os.IsNotExist(err) {
+			return &config.File{}, nil
+		}
+	}
+	return config.LoadFile(path)
+}
+
+// alertRules compiles the alert rules of the configuration file
+func (a *App) alertRules() ([]*alerts.Rule, error) {
+	file, err := a.configFile()
+	if err != nil {
+		return nil, err
+	}
+	return alerts.NewRules(file.Alerts)
+}
+
+// newDataSource creates the named datasource
+func (a *App) newDataSource(name string) (datasource.DataSource, error) {
+	dsConfig := &datasource.Config{
+		ProjectID:  a.cli.Project,
+		Region:     a.cli.Region,
+		MockedData: mock.GetDefaultServices(),
+	}
+
+	// Map flag to datasource.Type
+	switch name {
+	case "mock":
+		dsConfig.Type = datasource.Mock
+	default:
+		dsConfig.Type = datasource.GCP
+	}
+
+	return datasource.Factory(dsConfig)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lpmourato/c9s/internal/cache"
+	"github.com/lpmourato/c9s/internal/completion"
+	"github.com/lpmourato/c9s/internal/datasource"
+)
+
+// runCompletion prints the completion script of a shell to stdout
+func (a *App) runCompletion() error {
+	script, err := completion.Script(a.cli.Completion.Shell, "c9s")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(os.Stdout, script)
+	return err
+}
+
+// runComplete prints the candidates of the last word of a command line, one
+// per line. Projects, regions and services come from the services cache, so
+// completing never calls the Cloud Run API.
+func (a *App) runComplete(out io.Writer, words []string) error {
+	model, err := a.cli.Model()
+	if err != nil {
+		return err
+	}
+	services, err := cache.Load(cache.DefaultPath())
+	if err != nil {
+		// Completion goes on without the cached services
+		services = &cache.Services{}
+	}
+
+	c := &completion.Completer{
+		App:            model,
+		Source:         services,
+		DefaultProject: os.Getenv("GOOGLE_CLOUD_PROJECT"),
+		Regions:        datasource.Regions,
+		Datasources:    a.cli.ValidCommands(),
+	}
+	for _, candidate := range c.Complete(words) {
+		if _, err := fmt.Fprintln(out, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/cli"
+	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// runDescribe prints the details of a service to stdout
+func (a *App) runDescribe() error {
+	ds, err := a.newDataSource(a.cli.DatasourceName(cli.DescribeCommand))
+	if err != nil {
+		return fmt.Errorf("failed to create data source: %v", err)
+	}
+	cmd := a.cli.Describe
+	return describeService(os.Stdout, ds, cmd.Service, a.cli.Region, output.Format(cmd.Output), cmd.ShowSecrets, time.Local)
+}
+
+// describeService writes the details of a service to out
+func describeService(out io.Writer, ds datasource.DataSource, name, region string, format output.Format, showSecrets bool, loc *time.Location) error {
+	details, err := ds.GetServiceDetails(name, region)
+	if err != nil {
+		return fmt.Errorf("failed to get details of %s in %s: %v", name, region, err)
+	}
+	return output.WriteDetails(out, details, format, showSecrets, loc)
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/mock"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// detailsDataSource returns the sample details of the mock package, with
+// fixed times so outputs are stable
+type detailsDataSource struct {
+	failingDataSource
+}
+
+func (ds detailsDataSource) GetServiceDetails(name, region string) (*model.ServiceDetails, error) {
+	details := mock.MockServiceDetails()
+	details.LastUpdated = now.Add(-2 * time.Hour)
+	details.CreationTime = now.Add(-24 * time.Hour)
+	details.RevisionCreationTime = now.Add(-time.Hour)
+	details.RevisionConditions[0].LastTransitionTime = now.Add(-time.Hour)
+	return details, nil
+}
+
+func TestDescribeServiceGolden(t *testing.T) {
+	tests := []struct {
+		golden      string
+		format      output.Format
+		showSecrets bool
+	}{
+		{"describe/text.golden", output.Text, false},
+		{"describe/json.golden", output.JSON, false},
+		{"describe/yaml.golden", output.YAML, false},
+		{"describe/secrets.golden", output.Text, true},
+	}
+	ds := detailsDataSource{failingDataSource{mockDataSource(t)}}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := describeService(&out, ds, "sample-service", "us-central1", tt.format, tt.showSecrets, time.UTC); err != nil {
+				t.Fatalf("describeService() error = %v", err)
+			}
+			if masked := strings.Contains(out.String(), output.SecretMask); masked == tt.showSecrets {
+				t.Errorf("secrets masked = %v, want %v", masked, !tt.showSecrets)
+			}
+			assertGolden(t, tt.golden, out.Bytes())
+		})
+	}
+}
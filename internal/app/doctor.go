@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lpmourato/c9s/internal/cli"
+	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/doctor"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// runDoctor prints the results of the environment checks to stdout
+func (a *App) runDoctor() error {
+	// The gcp datasource cannot be created without credentials, which the
+	// doctor checks itself
+	env := doctor.NewGCPEnvironment()
+	if name := a.cli.DatasourceName(cli.DoctorCommand); name != string(datasource.GCP) {
+		ds, err := a.newDataSource(name)
+		if err != nil {
+			return fmt.Errorf("failed to create data source: %v", err)
+		}
+		env = ds.NewDiagnostics()
+	}
+	regions := datasource.Regions
+	if a.cli.Region != "" {
+		regions = []string{a.cli.Region}
+	}
+	return runChecks(context.Background(), os.Stdout, env, a.cli.Project, regions, output.Format(a.cli.Doctor.Output))
+}
+
+// runChecks writes the results of the checks to out, and fails when a check fails
+func runChecks(ctx context.Context, out io.Writer, env doctor.Environment, projectID string, regions []string, format output.Format) error {
+	results := doctor.Run(ctx, env, projectID, regions)
+
+	var err error
+	switch format {
+	case output.Text, "":
+		err = doctor.WriteText(out, results)
+	case output.JSON:
+		err = doctor.WriteJSON(out, results)
+	default:
+		err = fmt.Errorf("unknown output format %q", format)
+	}
+	if err != nil {
+		return err
+	}
+	if failed := doctor.Failed(results); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/cache"
+	"github.com/lpmourato/c9s/internal/cli"
+	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// runList prints the services of the datasource to stdout
+func (a *App) runList() error {
+	name := a.cli.DatasourceName(cli.ListCommand)
+	ds, err := a.newDataSource(name)
+	if err != nil {
+		return fmt.Errorf("failed to create data source: %v", err)
+	}
+	// Only the services of real projects are cached for completion
+	cacheProject := a.cli.Project
+	if name == string(datasource.Mock) {
+		cacheProject = ""
+	}
+	cmd := a.cli.List
+	return listServices(os.Stdout, ds, cacheProject, a.cli.Region, cmd.Filter, output.Format(cmd.Output), time.Local)
+}
+
+// listServices writes the services of a region, or of all regions, matching
+// filter to out. The services of the regions that could be listed are
+// written even when others fail, and the failures are returned. Unless
+// cacheProject is empty, the listed services are recorded in the completion
+// cache under it.
+func listServices(out io.Writer, ds datasource.DataSource, cacheProject, region, filter string, format output.Format, loc *time.Location) error {
+	f, err := output.ParseServiceFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	var services []model.Service
+	if region != "" {
+		services, err = ds.GetServicesByRegion(region)
+	} else {
+		services, err = ds.GetServices()
+	}
+	var regionErr *datasource.RegionError
+	if err != nil && !errors.As(err, &regionErr) {
+		return err
+	}
+	if cacheProject != "" {
+		// The cache only serves shell completion, so failing to update it is ignored
+		_ = cache.Record(cacheProject, datasource.ListedRegions(region, err), services)
+	}
+
+	w := output.ServiceWriter{
+		Format:   format,
+		Location: loc,
+		Details: func(svc model.Service) (*model.ServiceDetails, error) {
+			return ds.GetServiceDetails(svc.GetName(), svc.GetRegion())
+		},
+	}
+	return errors.Join(err, w.Write(out, f.Filter(services)))
+}
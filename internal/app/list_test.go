@@ -0,0 +1,120 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// testServices are services with fixed deploy times, so outputs are stable
+func testServices() []model.Service {
+	return []model.Service{
+		&model.CloudRunService{Name: "frontend-service", Region: "us-central1", URL: "https://frontend-service-hash.run.app",
+			Status: "Ready", LastDeploy: now.Add(-24 * time.Hour), Traffic: "100%"},
+		&model.CloudRunService{Name: "backend-api", Region: "us-central1", URL: "https://backend-api-hash.run.app",
+			Status: "Ready", LastDeploy: now.Add(-48 * time.Hour), Traffic: "100%"},
+		&model.CloudRunService{Name: "auth-service", Region: "us-central1", URL: "https://auth-service-hash.run.app",
+			Status: "Failed", LastDeploy: now.Add(-12 * time.Hour), Traffic: "No traffic (failed)"},
+		&model.CloudRunService{Name: "worker-service", Region: "us-east1", URL: "https://worker-service-hash.run.app",
+			Status: "Updating", LastDeploy: now.Add(-time.Hour), Traffic: "v1 (90%), v2 (10%)"},
+	}
+}
+
+func mockDataSource(t *testing.T) datasource.DataSource {
+	t.Helper()
+	ds, err := datasource.Factory(&datasource.Config{Type: datasource.Mock, MockedData: testServices()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ds
+}
+
+// assertGolden compares got with testdata/name, rewriting it with -update
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file, run go test with -update: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
+
+func TestListServicesGolden(t *testing.T) {
+	tests := []struct {
+		golden string
+		region string
+		filter string
+		format output.Format
+	}{
+		{"list/table.golden", "", "", output.Table},
+		{"list/wide.golden", "", "", output.Wide},
+		{"list/json.golden", "", "", output.JSON},
+		{"list/yaml.golden", "", "", output.YAML},
+		{"list/name.golden", "", "", output.Name},
+		{"list/region.golden", "us-east1", "", output.Table},
+		{"list/filter.golden", "", "status!=Ready,region=us-*", output.Name},
+		{"list/filter_name.golden", "", "SERVICE", output.Name},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := listServices(&out, mockDataSource(t), "", tt.region, tt.filter, tt.format, time.UTC); err != nil {
+				t.Fatalf("listServices() error = %v", err)
+			}
+			assertGolden(t, tt.golden, out.Bytes())
+		})
+	}
+}
+
+// failingDataSource fails to list the services of some regions
+type failingDataSource struct {
+	datasource.DataSource
+}
+
+func (ds failingDataSource) GetServices() ([]model.Service, error) {
+	services, _ := ds.DataSource.GetServices()
+	return services[:1], &datasource.RegionError{Errors: map[string]error{
+		"europe-west1": errors.New("permission denied"),
+	}}
+}
+
+func TestListServicesRegionError(t *testing.T) {
+	var out bytes.Buffer
+	err := listServices(&out, failingDataSource{mockDataSource(t)}, "", "", "", output.Name, time.UTC)
+
+	var regionErr *datasource.RegionError
+	if !errors.As(err, &regionErr) || !strings.Contains(err.Error(), "europe-west1: permission denied") {
+		t.Errorf("listServices() error = %v, want the failed region", err)
+	}
+	if out.String() != "frontend-service\n" {
+		t.Errorf("listServices() printed %q, want the services of the other regions", out.String())
+	}
+}
+
+func TestListServicesInvalidFilter(t *testing.T) {
+	if err := listServices(&bytes.Buffer{}, mockDataSource(t), "", "", "owner=me", output.Table, time.UTC); err == nil {
+		t.Error("listServices() with an unknown filter field succeeded, want an error")
+	}
+}
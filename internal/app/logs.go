@@ -0,0 +1,123 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/cli"
+	"github.com/lpmourato/c9s/internal/export"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// defaultLogsSince is the time range printed by the logs command without --since,
+// --follow or --file
+const defaultLogsSince = time.Hour
+
+// logFormats maps the --format values of the logs command to export formats
+var logFormats = map[string]export.Format{
+	"text": export.Text,
+	"json": export.JSONL,
+}
+
+// runLogs prints the logs of a service or file to stdout until they are all
+// printed or, when following, until interrupted
+func (a *App) runLogs() error {
+	cmd := a.cli.Logs
+	provider, err := a.logProvider(cmd.File)
+	if err != nil {
+		return fmt.Errorf("failed to create log provider: %v", err)
+	}
+
+	opts := model.CloudProviderOptions{
+		ProjectID:   a.cli.Project,
+		ServiceName: cmd.Service,
+		Region:      a.cli.Region,
+		MinSeverity: cmd.Severity,
+	}
+	now := time.Now()
+	if opts.Since, opts.Until, err = logging.ParseTimeBounds(a.cli.Since, a.cli.Until, now); err != nil {
+		return err
+	}
+	// Without --since, the hour before --until or now is printed
+	if opts.Since.IsZero() && !cmd.Follow && cmd.File == "" {
+		end := now
+		if !opts.Until.IsZero() {
+			end = opts.Until
+		}
+		opts.Since = end.Add(-defaultLogsSince)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return printLogs(ctx, os.Stdout, os.Stderr, provider, opts, cmd.Follow, logFormats[cmd.Format])
+}
+
+// logProvider returns the provider of the logs command: the entries of a
+// file when one is given, the datasource otherwise
+func (a *App) logProvider(file string) (model.LogProvider, error) {
+	if file != "" {
+		return logging.NewFileLogProvider(file)
+	}
+	ds, err := a.newDataSource(a.cli.DatasourceName(cli.LogsCommand))
+	if err != nil {
+		return nil, err
+	}
+	return ds.NewLogProvider()
+}
+
+// printLogs writes the entries matching opts to out, oldest first. With a
+// start time the entries since then are printed first; when following, new
+// entries are then printed until ctx is cancelled. Errors reported by the
+// stream go to errOut.
+func printLogs(ctx context.Context, out, errOut io.Writer, provider model.LogProvider, opts model.CloudProviderOptions, follow bool, format export.Format) error {
+	buf := bufio.NewWriter(out)
+	w := export.NewWriter(buf, format, nil)
+	write := func(entry model.LogEntry) error {
+		if err := w.Write(entry); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+
+	var last time.Time
+	if !opts.Since.IsZero() || !follow {
+		err := logging.ReadLogs(ctx, provider, opts, func(entry model.LogEntry) error {
+			last = entry.Timestamp
+			return write(entry)
+		})
+		if err != nil && ctx.Err() == nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil || !follow {
+			return err
+		}
+	}
+
+	// Follow from the last entry printed so the stream does not repeat it
+	if !last.IsZero() {
+		opts.Since = last.Add(time.Nanosecond)
+	}
+	for entry := range logging.NewLogService(provider, opts).StreamLogs(ctx) {
+		if logging.IsStatus(entry) {
+			if model.SeverityAtLeast(entry.Severity, model.SeverityError) {
+				fmt.Fprintf(errOut, "c9s: %s\n", entry.Message)
+			}
+			continue
+		}
+		if err := write(entry); err != nil {
+			return err
+		}
+		if err := buf.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/export"
+	"github.com/lpmourato/c9s/internal/mock"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func mockProvider() *mock.LogProvider {
+	provider := mock.NewLogProvider()
+	provider.Now = func() time.Time { return now }
+	return provider
+}
+
+func TestPrintLogsJSON(t *testing.T) {
+	opts := model.CloudProviderOptions{
+		ServiceName: "backend-api",
+		Since:       now.Add(-time.Minute),
+	}
+
+	var out, errOut bytes.Buffer
+	if err := printLogs(context.Background(), &out, &errOut, mockProvider(), opts, false, export.JSONL); err != nil {
+		t.Fatalf("printLogs() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// One entry every two seconds, both bounds included
+	if want := int(time.Minute/mock.DefaultLogInterval) + 1; len(lines) != want {
+		t.Fatalf("printed %d entries, want %d", len(lines), want)
+	}
+
+	var previous time.Time
+	for i, line := range lines {
+		var entry model.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if entry.ResourceLabels["service_name"] != "backend-api" {
+			t.Errorf("line %d: service = %q, want backend-api", i, entry.ResourceLabels["service_name"])
+		}
+		if entry.Timestamp.Before(opts.Since) || entry.Timestamp.After(now) {
+			t.Errorf("line %d: timestamp %v outside of the range", i, entry.Timestamp)
+		}
+		if !entry.Timestamp.After(previous) {
+			t.Errorf("line %d: timestamp %v not after %v", i, entry.Timestamp, previous)
+		}
+		previous = entry.Timestamp
+	}
+	if errOut.Len() > 0 {
+		t.Errorf("unexpected errors: %s", errOut.String())
+	}
+}
+
+func TestPrintLogsSeverity(t *testing.T) {
+	opts := model.CloudProviderOptions{
+		ServiceName: "backend-api",
+		MinSeverity: model.SeverityWarning,
+		Since:       now.Add(-10 * time.Minute),
+		Until:       now.Add(-5 * time.Minute),
+	}
+
+	var out, errOut bytes.Buffer
+	if err := printLogs(context.Background(), &out, &errOut, mockProvider(), opts, false, export.Text); err != nil {
+		t.Fatalf("printLogs() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		t.Fatal("no entries printed")
+	}
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			t.Fatalf("malformed line %q", line)
+		}
+		if !model.SeverityAtLeast(fields[1], model.SeverityWarning) {
+			t.Errorf("entry below WARNING printed: %q", line)
+		}
+		ts, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			t.Fatalf("invalid timestamp in %q: %v", line, err)
+		}
+		if ts.Before(opts.Since) || ts.After(opts.Until) {
+			t.Errorf("entry outside of the range printed: %q", line)
+		}
+	}
+}
+
+func TestPrintLogsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out, errOut bytes.Buffer
+	opts := model.CloudProviderOptions{ServiceName: "backend-api"}
+	if err := printLogs(ctx, &out, &errOut, mockProvider(), opts, true, export.Text); err != nil {
+		t.Fatalf("printLogs() error = %v, want a clean exit", err)
+	}
+}
+
+// filterRecorder records the filters sent to a log provider
+type filterRecorder struct {
+	*mock.LogProvider
+	filters []string
+}
+
+func (r *filterRecorder) FetchPage(ctx context.Context, req model.LogPageRequest) (model.LogPage, error) {
+	r.filters = append(r.filters, req.Filter)
+	return r.LogProvider.FetchPage(ctx, req)
+}
+
+func TestPrintLogsSeverityAlias(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{"WARN", "severity>=WARNING"},
+		{"warn", "severity>=WARNING"},
+		{"FATAL", "severity>=CRITICAL"},
+		{"TRACE", "severity>=DEBUG"},
+		{"error", "severity>=ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			provider := &filterRecorder{LogProvider: mockProvider()}
+			opts := model.CloudProviderOptions{
+				ServiceName: "backend-api",
+				MinSeverity: tt.severity,
+				Since:       now.Add(-time.Minute),
+				Until:       now,
+			}
+
+			var out, errOut bytes.Buffer
+			if err := printLogs(context.Background(), &out, &errOut, provider, opts, false, export.Text); err != nil {
+				t.Fatalf("printLogs() error = %v", err)
+			}
+			if len(provider.filters) == 0 {
+				t.Fatal("no logs fetched")
+			}
+			for _, filter := range provider.filters {
+				if !strings.Contains(filter, tt.want) {
+					t.Errorf("filter %q does not contain %q", filter, tt.want)
+				}
+			}
+		})
+	}
+}
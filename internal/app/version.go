@@ -0,0 +1,44 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lpmourato/c9s/internal/buildinfo"
+	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// runVersion prints the build information to stdout
+func (a *App) runVersion() error {
+	return writeVersion(os.Stdout, a.build, output.Format(a.cli.Version.Output))
+}
+
+// writeVersion writes the build information and the registered datasources to out
+func writeVersion(out io.Writer, build buildinfo.Info, format output.Format) error {
+	build.Datasources = nil
+	for _, t := range datasource.Types() {
+		build.Datasources = append(build.Datasources, string(t))
+	}
+
+	switch format {
+	case output.Text, "":
+		return build.Write(out)
+	case output.JSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(build)
+	case output.YAML:
+		enc := yaml.NewEncoder(out)
+		enc.SetIndent(2)
+		if err := enc.Encode(build); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lpmourato/c9s/internal/buildinfo"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+func TestWriteVersion(t *testing.T) {
+	build := buildinfo.Info{Version: "v1.2.0", Commit: "abc123", Date: "2024-03-01T12:00:00Z", GoVersion: "go1.22.0"}
+	var out bytes.Buffer
+	if err := writeVersion(&out, build, output.Text); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Version:     v1.2.0", "Commit:      abc123", "Go version:  go1.22.0", "Datasources: gcp, mock"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeVersion() = %q, want %q", out.String(), want)
+		}
+	}
+
+	out.Reset()
+	if err := writeVersion(&out, build, output.JSON); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"datasources": [
+    "gcp",
+    "mock"
+  ]`) {
+		t.Errorf("writeVersion() JSON = %s, want the datasources", out.String())
+	}
+}
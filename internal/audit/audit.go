@@ -0,0 +1,261 @@
+// Package audit reads the Cloud Audit Logs of Cloud Run services: who called
+// which Admin API method, the revision it created and what its request changed.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// RunServiceName is the service name of the Cloud Run Admin API in audit logs
+const RunServiceName = "run.googleapis.com"
+
+// Event is a call to the Cloud Run Admin API recorded in the audit logs
+type Event struct {
+	Timestamp time.Time
+	// Email of the user or service account that made the call
+	Principal string
+	// Short name of the called method, e.g. ReplaceService
+	Method string
+	// Full name of the called method, e.g. google.cloud.run.v1.Services.ReplaceService
+	MethodName   string
+	ResourceName string
+	Service      string
+	// Revision created by the call, when known
+	Revision string
+	// Error of a failed call; empty when the call succeeded
+	Error string
+	// Request of the call, when the audit log contains it
+	Request map[string]interface{}
+	Entry   model.LogEntry
+}
+
+// Failed reports whether the call failed
+func (e Event) Failed() bool {
+	return e.Error != ""
+}
+
+// Filter returns the Logging query of the Admin Activity audit logs of a
+// Cloud Run service since a time. An empty region covers all regions.
+func Filter(serviceName, region string, since time.Time) string {
+	return logging.ComposeFilter("", model.CloudProviderOptions{
+		Region: region,
+		LogIDs: []string{logging.AuditActivityLogID},
+		Since:  since,
+		Query: fmt.Sprintf(`protoPayload.serviceName="%s" AND (resource.labels.service_name="%s" OR protoPayload.resourceName:"services/%s")`,
+			RunServiceName, serviceName, serviceName),
+	})
+}
+
+// FromEntry reads the Cloud Run Admin API call of an audit log entry. It
+// reports false when the entry is not such an audit log entry.
+func FromEntry(entry model.LogEntry) (Event, bool) {
+	payload := entry.Payload
+	if payload == nil || stringAt(payload, "serviceName") != RunServiceName {
+		return Event{}, false
+	}
+
+	e := Event{
+		Timestamp:    entry.Timestamp,
+		Principal:    stringAt(payload, "authenticationInfo", "principalEmail"),
+		MethodName:   stringAt(payload, "methodName"),
+		ResourceName: stringAt(payload, "resourceName"),
+		Service:      entry.ResourceLabels["service_name"],
+		Entry:        entry,
+	}
+	e.Method = e.MethodName[strings.LastIndex(e.MethodName, ".")+1:]
+	if e.Service == "" {
+		e.Service = serviceOf(e.ResourceName)
+	}
+	if request, ok := payload["request"].(map[string]interface{}); ok {
+		e.Request = request
+	}
+	if status, ok := payload["status"].(map[string]interface{}); ok {
+		if code, _ := status["code"].(float64); code != 0 {
+			e.Error = stringAt(status, "message")
+			if e.Error == "" {
+				e.Error = fmt.Sprintf("code %.0f", code)
+			}
+		}
+	}
+	e.Revision = revisionOf(entry, payload)
+	return e, true
+}
+
+// Events returns the Cloud Run Admin API calls on a service recorded in
+// audit log entries, newest first. Entries about other services, which a
+// resource name filter may include, are skipped.
+func Events(entries []model.LogEntry, serviceName string) []Event {
+	var events []Event
+	for _, entry := range entries {
+		if e, ok := FromEntry(entry); ok && (serviceName == "" || e.Service == serviceName) {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp.After(events[j].Timestamp) })
+	return events
+}
+
+// Previous returns the index of the event preceding events[i] whose request
+// can be compared with its own, or -1. Events are sorted newest first.
+func Previous(events []Event, i int) int {
+	if events[i].Request == nil {
+		return -1
+	}
+	for j := i + 1; j < len(events); j++ {
+		if events[j].Request != nil && !events[j].Failed() && comparable(events[i].Method, events[j].Method) {
+			return j
+		}
+	}
+	return -1
+}
+
+// specMethods are the methods whose request holds the whole service
+var specMethods = map[string]bool{
+	"CreateService":  true,
+	"ReplaceService": true,
+	"UpdateService":  true,
+}
+
+// comparable reports whether the requests of two methods describe the same thing
+func comparable(a, b string) bool {
+	return a == b || (specMethods[a] && specMethods[b])
+}
+
+// serviceOf returns the service of a resource name, e.g.
+// namespaces/my-project/services/api or projects/p/locations/l/services/api
+func serviceOf(resourceName string) string {
+	parts := strings.Split(resourceName, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "services" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// revisionOf returns the revision created by a call: the revision of the
+// entry resource, or the latest created revision of the response, or the
+// revision named by the request
+func revisionOf(entry model.LogEntry, payload map[string]interface{}) string {
+	if revision := entry.ResourceLabels["revision_name"]; revision != "" {
+		return revision
+	}
+	candidates := []string{
+		stringAt(payload, "response", "status", "latestCreatedRevisionName"),
+		stringAt(payload, "response", "latestCreatedRevision"),
+		stringAt(payload, "response", "metadata", "latestCreatedRevision"),
+		stringAt(payload, "request", "service", "spec", "template", "metadata", "name"),
+		stringAt(payload, "request", "service", "template", "revision"),
+	}
+	for _, candidate := range candidates {
+		if candidate != "" {
+			return candidate[strings.LastIndex(candidate, "/")+1:]
+		}
+	}
+	return ""
+}
+
+// stringAt returns the string at a path of nested objects, or ""
+func stringAt(object map[string]interface{}, path ...string) string {
+	for i, key := range path {
+		value, ok := object[key]
+		if !ok {
+			return ""
+		}
+		if i == len(path)-1 {
+			s, _ := value.(string)
+			return s
+		}
+		if object, ok = value.(map[string]interface{}); !ok {
+			return ""
+		}
+	}
+	return ""
+}
+
+// Change is a difference between two requests
+type Change struct {
+	// Path of the changed value, e.g. service.spec.template.spec.containers[0].image
+	Path string
+	// Old and new values as JSON; empty when the value was added or removed
+	Old string
+	New string
+}
+
+// ignoredPaths are request fields set by the API rather than by the caller,
+// left out of diffs
+var ignoredPaths = []string{
+	"@type",
+	"service.metadata.resourceVersion",
+	"service.metadata.generation",
+	"service.metadata.uid",
+	"service.metadata.creationTimestamp",
+	"service.metadata.selfLink",
+	"service.status",
+	"service.etag",
+	"service.uid",
+	"service.generation",
+	"service.createTime",
+	"service.updateTime",
+}
+
+// Diff returns the changes from an old request to a new one, by path. A nil
+// old request reports every value of the new one as added.
+func Diff(old, new map[string]interface{}) []Change {
+	oldValues := make(map[string]string)
+	newValues := make(map[string]string)
+	flatten("", old, oldValues)
+	flatten("", new, newValues)
+
+	var changes []Change
+	for path, value := range newValues {
+		if oldValues[path] != value {
+			changes = append(changes, Change{Path: path, Old: oldValues[path], New: value})
+		}
+	}
+	for path, value := range oldValues {
+		if _, ok := newValues[path]; !ok {
+			changes = append(changes, Change{Path: path, Old: value})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// flatten stores the scalar values of a JSON value by path, skipping the ignored paths
+func flatten(path string, value interface{}, values map[string]string) {
+	for _, ignored := range ignoredPaths {
+		if path == ignored || strings.HasPrefix(path, ignored+".") || strings.HasPrefix(path, ignored+"[") {
+			return
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if path != "" {
+				key = path + "." + key
+			}
+			flatten(key, item, values)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flatten(fmt.Sprintf("%s[%d]", path, i), item, values)
+		}
+	case nil:
+		if path != "" {
+			values[path] = "null"
+		}
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			values[path] = string(data)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+var now = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// auditEntry returns an audit log entry with a JSON protoPayload
+func auditEntry(t *testing.T, ago time.Duration, payload string) model.LogEntry {
+	t.Helper()
+	var p map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		t.Fatal(err)
+	}
+	return model.LogEntry{
+		Timestamp:      now.Add(-ago),
+		LogName:        "projects/p/logs/cloudaudit.googleapis.com%2Factivity",
+		ResourceLabels: map[string]string{"location": "us-central1"},
+		Payload:        p,
+	}
+}
+
+func replaceService(image string) string {
+	return `{
+		"@type": "type.googleapis.com/google.cloud.audit.AuditLog",
+		"serviceName": "run.googleapis.com",
+		"methodName": "google.cloud.run.v1.Services.ReplaceService",
+		"resourceName": "namespaces/p/services/api",
+		"authenticationInfo": {"principalEmail": "dev@example.com"},
+		"request": {
+			"@type": "type.googleapis.com/google.cloud.run.v1.ReplaceServiceRequest",
+			"service": {
+				"metadata": {"name": "api", "resourceVersion": "` + image + `"},
+				"spec": {"template": {"spec": {"containers": [{"image": "` + image + `"}]}}}
+			}
+		},
+		"response": {"status": {"latestCreatedRevisionName": "api-` + image + `"}}
+	}`
+}
+
+func TestEvents(t *testing.T) {
+	entries := []model.LogEntry{
+		auditEntry(t, 2*time.Hour, replaceService("v1")),
+		auditEntry(t, time.Hour, replaceService("v2")),
+		auditEntry(t, 30*time.Minute, `{
+			"serviceName": "run.googleapis.com",
+			"methodName": "google.cloud.run.v1.Services.SetIamPolicy",
+			"resourceName": "projects/p/locations/us-central1/services/api",
+			"authenticationInfo": {"principalEmail": "ops@example.com"},
+			"status": {"code": 7, "message": "Permission denied"}
+		}`),
+		// Another service matched by the resource name filter
+		auditEntry(t, 10*time.Minute, `{
+			"serviceName": "run.googleapis.com",
+			"methodName": "google.cloud.run.v1.Services.DeleteService",
+			"resourceName": "namespaces/p/services/api-v2"
+		}`),
+		// Not an audit log of Cloud Run
+		auditEntry(t, 5*time.Minute, `{"serviceName": "iam.googleapis.com", "methodName": "SetIamPolicy"}`),
+	}
+
+	events := Events(entries, "api")
+	if len(events) != 3 {
+		t.Fatalf("Events() returned %d events, want 3: %+v", len(events), events)
+	}
+
+	iam := events[0]
+	if iam.Method != "SetIamPolicy" || iam.Principal != "ops@example.com" || !iam.Failed() || iam.Error != "Permission denied" {
+		t.Errorf("newest event = %+v", iam)
+	}
+	replace := events[1]
+	if replace.Method != "ReplaceService" || replace.Principal != "dev@example.com" || replace.Revision != "api-v2" || replace.Failed() {
+		t.Errorf("replace event = %+v", replace)
+	}
+
+	if got := Previous(events, 1); got != 2 {
+		t.Errorf("Previous() of the replace event = %d, want 2", got)
+	}
+	if got := Previous(events, 0); got != -1 {
+		t.Errorf("Previous() of an event without request = %d, want -1", got)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	decode := func(payload string) map[string]interface{} {
+		var p map[string]interface{}
+		if err := json.Unmarshal([]byte(payload), &p); err != nil {
+			t.Fatal(err)
+		}
+		return p["request"].(map[string]interface{})
+	}
+	old := decode(replaceService("v1"))
+	new := decode(replaceService("v2"))
+	new["service"].(map[string]interface{})["metadata"].(map[string]interface{})["labels"] = map[string]interface{}{"team": "web"}
+
+	want := []Change{
+		{Path: "service.metadata.labels.team", New: `"web"`},
+		{Path: "service.spec.template.spec.containers[0].image", Old: `"v1"`, New: `"v2"`},
+	}
+	if got := Diff(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %+v, want %+v", got, want)
+	}
+
+	added := Diff(nil, new)
+	for _, change := range added {
+		if change.Old != "" || strings.HasPrefix(change.Path, "@type") {
+			t.Errorf("Diff(nil) change = %+v, want only additions of caller fields", change)
+		}
+	}
+	if len(added) != 3 {
+		t.Errorf("Diff(nil) = %+v, want 3 additions", added)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	filter := Filter("api", "us-central1", now)
+	for _, clause := range []string{
+		`log_id("cloudaudit.googleapis.com/activity")`,
+		`resource.labels.location="us-central1"`,
+		`protoPayload.serviceName="run.googleapis.com"`,
+		`protoPayload.resourceName:"services/api"`,
+	} {
+		if !strings.Contains(filter, clause) {
+			t.Errorf("Filter() = %s, want %s", filter, clause)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Package buildinfo describes the build of c9s. The version, commit and build
+// date are set by the linker in package main; builds without them, e.g. with
+// go install, fall back to the information embedded by the go command.
+package buildinfo
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Unknown is reported for the parts of the build information that are not set
+const Unknown = "unknown"
+
+// Info describes a build of c9s
+type Info struct {
+	Version   string `json:"version" yaml:"version"`
+	Commit    string `json:"commit" yaml:"commit"`
+	Date      string `json:"date" yaml:"date"`
+	GoVersion string `json:"goVersion" yaml:"goVersion"`
+	// Datasources compiled into the build
+	Datasources []string `json:"datasources" yaml:"datasources"`
+}
+
+// New returns the information of the running build from the values set by
+// the linker, completed with the build information of the go command
+func New(version, commit, date string) Info {
+	info := Info{Version: version, Commit: commit, Date: date, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.fill(bi)
+	}
+	for _, s := range []*string{&info.Version, &info.Commit, &info.Date} {
+		if *s == "" {
+			*s = Unknown
+		}
+	}
+	return info
+}
+
+// fill sets the parts missing from the linker flags from the build information
+func (i *Info) fill(bi *debug.BuildInfo) {
+	if i.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		i.Version = bi.Main.Version
+	}
+	modified := false
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if i.Commit == "" {
+				i.Commit = s.Value
+			}
+		case "vcs.time":
+			if i.Date == "" {
+				i.Date = s.Value
+			}
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if i.Version == "" && i.Commit != "" {
+		i.Version = "dev-" + shortCommit(i.Commit)
+		if modified {
+			i.Version += "-dirty"
+		}
+	}
+}
+
+// Write writes the build information as text, one part per line
+func (i Info) Write(w io.Writer) error {
+	datasources := strings.Join(i.Datasources, ", ")
+	if datasources == "" {
+		datasources = "none"
+	}
+	_, err := fmt.Fprintf(w, "Version:     %s\nCommit:      %s\nBuild date:  %s\nGo version:  %s\nDatasources: %s\n",
+		i.Version, i.Commit, i.Date, i.GoVersion, datasources)
+	return err
+}
+
+// shortCommit abbreviates a commit hash
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
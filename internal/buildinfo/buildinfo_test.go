@@ -0,0 +1,28 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestFill(t *testing.T) {
+	bi := &debug.BuildInfo{Settings: []debug.BuildSetting{
+		{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+		{Key: "vcs.time", Value: "2024-03-01T12:00:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}}
+	bi.Main.Version = "(devel)"
+
+	var info Info
+	info.fill(bi)
+	if info.Version != "dev-0123456789ab-dirty" || info.Commit != "0123456789abcdef0123" || info.Date != "2024-03-01T12:00:00Z" {
+		t.Errorf("fill() = %+v, want the version derived from the revision", info)
+	}
+
+	// Values set by the linker win
+	info = Info{Version: "v1.2.0", Commit: "abc", Date: "today"}
+	info.fill(bi)
+	if info.Version != "v1.2.0" || info.Commit != "abc" || info.Date != "today" {
+		t.Errorf("fill() = %+v, want the linker values kept", info)
+	}
+}
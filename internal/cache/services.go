@@ -0,0 +1,181 @@
+// Package cache keeps the services seen by c9s in the user cache directory,
+// so that shell completion can suggest projects, regions and service names
+// without calling the Cloud Run API.
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Service is a cached service
+type Service struct {
+	Name   string `json:"name"`
+	Region string `json:"region"`
+}
+
+// Project holds the cached services of a project
+type Project struct {
+	Updated  time.Time `json:"updated"`
+	Services []Service `json:"services"`
+}
+
+// Services is the cache of the services of each project
+type Services struct {
+	Projects map[string]*Project `json:"projects"`
+}
+
+// DefaultPath returns the path of the services cache, c9s/services.json in
+// the user cache directory, or "" when there is none
+func DefaultPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "c9s", "services.json")
+}
+
+// Load reads the cache at path; a missing file is an empty cache
+func Load(path string) (*Services, error) {
+	c := &Services{Projects: make(map[string]*Project)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read services cache: %v", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse services cache %s: %v", path, err)
+	}
+	if c.Projects == nil {
+		c.Projects = make(map[string]*Project)
+	}
+	return c, nil
+}
+
+// Save writes the cache to path, replacing the file at once so that
+// concurrent readers never see a partial cache
+func (c *Services) Save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".services-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write services cache: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write services cache: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write services cache: %v", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Update replaces the cached services of the listed regions of a project
+func (c *Services) Update(projectID string, regions []string, services []model.Service, now time.Time) {
+	p, ok := c.Projects[projectID]
+	if !ok {
+		p = &Project{}
+		c.Projects[projectID] = p
+	}
+
+	kept := p.Services[:0]
+	for _, svc := range p.Services {
+		if !slices.Contains(regions, svc.Region) {
+			kept = append(kept, svc)
+		}
+	}
+	for _, svc := range services {
+		kept = append(kept, Service{Name: svc.GetName(), Region: svc.GetRegion()})
+	}
+	sort.Slice(kept, func(i, j int) bool {
+		if kept[i].Name != kept[j].Name {
+			return kept[i].Name < kept[j].Name
+		}
+		return kept[i].Region < kept[j].Region
+	})
+	p.Services = kept
+	p.Updated = now
+}
+
+// ProjectIDs returns the cached projects, sorted
+func (c *Services) ProjectIDs() []string {
+	ids := make([]string, 0, len(c.Projects))
+	for id := range c.Projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Regions returns the regions with cached services in a project, or in all
+// projects when projectID is empty, sorted
+func (c *Services) Regions(projectID string) []string {
+	var regions []string
+	for _, svc := range c.services(projectID) {
+		if !slices.Contains(regions, svc.Region) {
+			regions = append(regions, svc.Region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Names returns the names of the cached services of a project and region,
+// where empty values match all, sorted
+func (c *Services) Names(projectID, region string) []string {
+	var names []string
+	for _, svc := range c.services(projectID) {
+		if (region == "" || svc.Region == region) && !slices.Contains(names, svc.Name) {
+			names = append(names, svc.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// services returns the cached services of a project, or of all projects
+func (c *Services) services(projectID string) []Service {
+	if projectID != "" {
+		if p, ok := c.Projects[projectID]; ok {
+			return p.Services
+		}
+		return nil
+	}
+	var all []Service
+	for _, p := range c.Projects {
+		all = append(all, p.Services...)
+	}
+	return all
+}
+
+// Record updates the cache at the default path with the services listed in
+// the regions of a project
+func Record(projectID string, regions []string, services []model.Service) error {
+	path := DefaultPath()
+	if path == "" || projectID == "" {
+		return nil
+	}
+	c, err := Load(path)
+	if err != nil {
+		// A corrupt cache is rebuilt
+		c = &Services{Projects: make(map[string]*Project)}
+	}
+	c.Update(projectID, regions, services, time.Now())
+	return c.Save(path)
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func services(names ...string) []model.Service {
+	var out []model.Service
+	for i := 0; i < len(names); i += 2 {
+		out = append(out, &model.CloudRunService{Name: names[i], Region: names[i+1]})
+	}
+	return out
+}
+
+func TestUpdateKeepsOtherRegions(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := &Services{Projects: make(map[string]*Project)}
+	c.Update("demo", []string{"us-central1", "us-east1"}, services("web", "us-central1", "api", "us-east1"), now)
+
+	// Listing a region replaces its services only
+	c.Update("demo", []string{"us-east1"}, services("worker", "us-east1"), now)
+	if got, want := c.Names("demo", ""), []string{"web", "worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %q, want %q", got, want)
+	}
+	if got, want := c.Names("demo", "us-east1"), []string{"worker"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names(us-east1) = %q, want %q", got, want)
+	}
+
+	c.Update("other", []string{"europe-west1"}, services("billing", "europe-west1"), now)
+	if got, want := c.Regions(""), []string{"europe-west1", "us-central1", "us-east1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Regions() = %q, want %q", got, want)
+	}
+	if got, want := c.ProjectIDs(), []string{"demo", "other"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ProjectIDs() = %q, want %q", got, want)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c9s", "services.json")
+	empty, err := Load(path)
+	if err != nil || len(empty.Projects) != 0 {
+		t.Fatalf("Load() of a missing cache = %+v, %v, want an empty cache", empty, err)
+	}
+
+	c := &Services{Projects: make(map[string]*Project)}
+	c.Update("demo", []string{"us-central1"}, services("web", "us-central1"), time.Now())
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Names("demo", "us-central1"); !reflect.DeepEqual(got, []string{"web"}) {
+		t.Errorf("loaded Names() = %q, want [web]", got)
+	}
+}
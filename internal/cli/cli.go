@@ -1,43 +1,195 @@
 package cli
 
 import (
+	"fmt"
 	"slices"
+	"strings"
+	"time"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
+)
+
+// Commands printing to stdout without starting the UI
+const (
+	// LogsCommand prints logs
+	LogsCommand = "logs"
+	// ListCommand prints the services
+	ListCommand = "list"
+	// DescribeCommand prints the details of a service
+	DescribeCommand = "describe"
+	// VersionCommand prints the build information
+	VersionCommand = "version"
+	// DoctorCommand checks the credentials, project, APIs, permissions and regions
+	DoctorCommand = "doctor"
+	// CompletionCommand prints a shell completion script
+	CompletionCommand = "completion"
 )
 
 type CLI struct {
 	Datasource string `kong:"help='Data source to use',default='gcp'"`
 	Project    string `kong:"help='GCP project ID',env='GOOGLE_CLOUD_PROJECT'"`
 	Region     string `kong:"help='Cloud Run region (e.g., us-central1)'"`
+	Since      string `kong:"help='Load logs from this time (e.g., 1h, yesterday 14:02, 2024-05-01T14:02:00Z)'"`
+	Until      string `kong:"help='Load logs up to this time instead of following new entries'"`
+	LogBuffer  int    `kong:"help='Maximum number of log entries kept by the log view',default='50000'"`
+	Config     string `kong:"help='Configuration file (default: c9s/config.yaml in the user config directory)',env='C9S_CONFIG',type='path'"`
 
-	Mock MockCmd `kong:"cmd,help='Run in mock mode'"`
-	Gcp  GcpCmd  `kong:"cmd,help='Run normally',default='1'"`
+	Mock       MockCmd       `kong:"cmd,help='Run in mock mode'"`
+	Gcp        GcpCmd        `kong:"cmd,help='Run normally',default='1'"`
+	Logs       LogsCmd       `kong:"cmd,help='Print the logs of a service or of a log file to stdout'"`
+	List       ListCmd       `kong:"cmd,help='Print the services to stdout'"`
+	Describe   DescribeCmd   `kong:"cmd,help='Print the details of a service to stdout'"`
+	Version    VersionCmd    `kong:"cmd,help='Print the version and build information'"`
+	Doctor     DoctorCmd     `kong:"cmd,help='Check the credentials, project, APIs, permissions and regions used by c9s'"`
+	Completion CompletionCmd `kong:"cmd,help='Print a shell completion script'"`
 }
 
 func (c *CLI) ValidCommands() []string {
 	return []string{"mock", "gcp"}
 }
 
-func (c *CLI) Parse() (*kong.Context, error) {
-	ctx := kong.Parse(c,
+// options are the kong options of the c9s command line
+func (c *CLI) options() []kong.Option {
+	return []kong.Option{
 		kong.Name("c9s"),
 		kong.Description("Cloud Run status UI"),
-	)
+	}
+}
+
+// Model returns the commands and flags of the command line, for completion
+func (c *CLI) Model() (*kong.Application, error) {
+	parser, err := kong.New(c, c.options()...)
+	if err != nil {
+		return nil, err
+	}
+	return parser.Model, nil
+}
+
+func (c *CLI) Parse() (*kong.Context, error) {
+	ctx := kong.Parse(c, c.options()...)
 
-	dsFlag := ctx.Command()
+	command := CommandName(ctx.Command())
+	dsFlag := c.DatasourceName(command)
 
 	if !slices.Contains(c.ValidCommands(), dsFlag) {
 		ctx.Fatalf("unsupported datasource %q (allowed: mock,gcp)", dsFlag)
 	}
 
-	// Validate project required for GCP
-	if dsFlag == "gcp" && c.Project == "" {
+	// Validate project required for GCP; logs read from a file, the version
+	// and completion scripts need none, and the doctor reports a missing
+	// project itself
+	offline := command == VersionCommand || command == DoctorCommand || command == CompletionCommand ||
+		(command == LogsCommand && c.Logs.File != "")
+	if dsFlag == "gcp" && c.Project == "" && !offline {
 		ctx.Fatalf("project is required for datasource=gcp; set --project or GOOGLE_CLOUD_PROJECT")
 	}
 
+	// Validate the log time range up front so the log view can rely on it
+	if c.Since != "" || c.Until != "" {
+		if err := c.validateTimeRange(); err != nil {
+			ctx.Fatalf("%v", err)
+		}
+	}
+
+	if command == LogsCommand {
+		if err := c.Logs.validate(c.Until); err != nil {
+			ctx.Fatalf("%v", err)
+		}
+	}
+	if command == ListCommand {
+		if _, err := output.ParseServiceFilter(c.List.Filter); err != nil {
+			ctx.Fatalf("%v", err)
+		}
+	}
+	if command == DescribeCommand && c.Region == "" {
+		ctx.Fatalf("--region is required to describe a service")
+	}
+
 	return ctx, nil
 }
 
+// CommandName returns the name of a command as reported by kong, without its arguments
+func CommandName(command string) string {
+	name, _, _ := strings.Cut(command, " ")
+	return name
+}
+
+// DatasourceName returns the datasource used by a command: the mock and gcp
+// commands select their own, the other commands use --datasource
+func (c *CLI) DatasourceName(command string) string {
+	if slices.Contains(c.ValidCommands(), command) {
+		return command
+	}
+	return c.Datasource
+}
+
+// validateTimeRange checks that --since and --until parse and form a valid range
+func (c *CLI) validateTimeRange() error {
+	_, _, err := logging.ParseTimeBounds(c.Since, c.Until, time.Now())
+	return err
+}
+
+// ListCmd prints the services of the datasource without starting the UI
+type ListCmd struct {
+	Filter string `kong:"help='Only print the services matching this expression: a name substring, or comma separated field=pattern / field!=pattern terms on name, region, url, status and traffic (e.g., status!=Ready,region=us-*)'"`
+	Output string `kong:"short='o',help='Output format: table, wide, json, yaml or name',enum='table,wide,json,yaml,name',default='table'"`
+}
+
+// DescribeCmd prints the details of a service without starting the UI
+type DescribeCmd struct {
+	Service     string `kong:"arg,help='Name of the service'"`
+	Output      string `kong:"short='o',help='Output format: text, json or yaml',enum='text,json,yaml',default='text'"`
+	ShowSecrets bool   `kong:"help='Print the values of sensitive environment variables instead of masking them'"`
+}
+
+// VersionCmd prints the version, commit, build date, Go version and datasources
+type VersionCmd struct {
+	Output string `kong:"short='o',help='Output format: text, json or yaml',enum='text,json,yaml',default='text'"`
+}
+
+// DoctorCmd checks the environment of c9s and suggests a fix for each failed check
+type DoctorCmd struct {
+	Output string `kong:"short='o',help='Output format: text or json',enum='text,json',default='text'"`
+}
+
+// CompletionCmd prints the completion script of a shell
+type CompletionCmd struct {
+	Shell string `kong:"arg,help='Shell: bash, zsh or fish',enum='bash,zsh,fish'"`
+}
+
 type MockCmd struct{}
 type GcpCmd struct{}
+
+// LogsCmd prints the logs of a service, or of exported log files, without starting the UI
+type LogsCmd struct {
+	Service  string `kong:"arg,optional,help='Name of the service; optional with --file'"`
+	File     string `kong:"type='path',help='Read the logs from a JSON or JSON lines file, or a directory of them, instead of the datasource'"`
+	Follow   bool   `kong:"short='f',help='Keep printing new entries until interrupted'"`
+	Severity string `kong:"help='Only print entries of this severity or above (e.g., WARNING)'"`
+	Format   string `kong:"help='Output format: text or json (one object per line)',enum='text,json',default='text'"`
+}
+
+// validate checks the logs options and normalizes the severity
+func (l *LogsCmd) validate(until string) error {
+	if l.Service == "" && l.File == "" {
+		return fmt.Errorf("a service name is required unless --file is given")
+	}
+	if l.Follow && until != "" {
+		return fmt.Errorf("--follow cannot be used with --until")
+	}
+	if l.Follow && l.File != "" {
+		return fmt.Errorf("--follow cannot be used with --file")
+	}
+	if l.Severity != "" {
+		severity, ok := model.NormalizeSeverity(l.Severity)
+		if !ok {
+			return fmt.Errorf("unknown severity %q", l.Severity)
+		}
+		l.Severity = severity
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// Package completion completes c9s command lines for shells. The scripts
+// generated for bash, zsh and fish call c9s back with the words of the command
+// line, and c9s answers from its kong model and from the cached services.
+package completion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/kong"
+)
+
+// Command is the hidden command the shell scripts call to complete a command
+// line: c9s __complete WORD... prints the candidates of the last word
+const Command = "__complete"
+
+// Shells are the shells completion scripts are generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Source provides the dynamic values of the command line
+type Source interface {
+	// ProjectIDs returns the known projects
+	ProjectIDs() []string
+	// Regions returns the known regions of a project, or of all projects
+	Regions(projectID string) []string
+	// Names returns the known services of a project and region, where empty
+	// values match all
+	Names(projectID, region string) []string
+}
+
+// Completer completes the words of a command line
+type Completer struct {
+	App    *kong.Application
+	Source Source
+	// DefaultProject is used when the command line has no --project
+	DefaultProject string
+	// Regions are always suggested for --region, besides the known ones
+	Regions []string
+	// Datasources are suggested for --datasource
+	Datasources []string
+}
+
+// commandLine is the state of a command line up to the word being completed
+type commandLine struct {
+	node       *kong.Node
+	positional int
+	flags      map[string]string
+	// pending is a flag whose value is the word being completed
+	pending *kong.Flag
+}
+
+// Complete returns the candidates of the last word of a command line, without
+// the program name. Flags are completed with their values after "=".
+func (c *Completer) Complete(words []string) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	line := c.parse(words[:len(words)-1])
+
+	if line.pending != nil {
+		return filter(c.flagValues(line.pending, line), cur)
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		if name, prefix, ok := strings.Cut(cur, "="); ok {
+			flag := findFlag(line.node, name)
+			if flag == nil {
+				return nil
+			}
+			var candidates []string
+			for _, value := range filter(c.flagValues(flag, line), prefix) {
+				candidates = append(candidates, name+"="+value)
+			}
+			return candidates
+		}
+		return filter(flagNames(line.node), cur)
+	}
+
+	var candidates []string
+	for _, child := range line.node.Children {
+		if !child.Hidden && child.Type == kong.CommandNode {
+			candidates = append(candidates, child.Name)
+		}
+	}
+	if line.positional < len(line.node.Positional) {
+		candidates = append(candidates, c.argValues(line.node.Positional[line.positional], line)...)
+	}
+	return filter(candidates, cur)
+}
+
+// parse walks the complete words of a command line through the commands and flags
+func (c *Completer) parse(words []string) *commandLine {
+	line := &commandLine{node: c.App.Node, flags: make(map[string]string)}
+	for _, word := range words {
+		if line.pending != nil {
+			line.flags[line.pending.Name] = word
+			line.pending = nil
+			continue
+		}
+		if strings.HasPrefix(word, "-") && word != "-" {
+			name, value, hasValue := strings.Cut(word, "=")
+			flag := findFlag(line.node, name)
+			switch {
+			case flag == nil:
+			case hasValue:
+				line.flags[flag.Name] = value
+			case !flag.IsBool():
+				line.pending = flag
+			}
+			continue
+		}
+		if child := findChild(line.node, word); child != nil {
+			line.node = child
+			line.positional = 0
+			continue
+		}
+		line.positional++
+	}
+	return line
+}
+
+// project returns the project of the command line
+func (c *Completer) project(line *commandLine) string {
+	if project := line.flags["project"]; project != "" {
+		return project
+	}
+	return c.DefaultProject
+}
+
+// flagValues returns the values suggested for a flag
+func (c *Completer) flagValues(flag *kong.Flag, line *commandLine) []string {
+	switch {
+	case flag.Enum != "":
+		return flag.EnumSlice()
+	case flag.Name == "project":
+		return c.Source.ProjectIDs()
+	case flag.Name == "region":
+		return union(c.Regions, c.Source.Regions(c.project(line)))
+	case flag.Name == "datasource":
+		return c.Datasources
+	}
+	return nil
+}
+
+// argValues returns the values suggested for a positional argument
+func (c *Completer) argValues(arg *kong.Positional, line *commandLine) []string {
+	switch {
+	case arg.Enum != "":
+		return arg.EnumSlice()
+	case arg.Name == "service":
+		return c.Source.Names(c.project(line), line.flags["region"])
+	}
+	return nil
+}
+
+// findFlag returns the flag of a command or of its parents named by a word
+// such as --region or -o
+func findFlag(node *kong.Node, word string) *kong.Flag {
+	for _, group := range node.AllFlags(false) {
+		for _, flag := range group {
+			if word == "--"+flag.Name || (flag.Short != 0 && word == "-"+string(flag.Short)) {
+				return flag
+			}
+		}
+	}
+	return nil
+}
+
+// flagNames returns the visible flags of a command and of its parents
+func flagNames(node *kong.Node) []string {
+	var names []string
+	for _, group := range node.AllFlags(true) {
+		for _, flag := range group {
+			names = append(names, "--"+flag.Name)
+		}
+	}
+	return names
+}
+
+// findChild returns the subcommand of a command named by a word
+func findChild(node *kong.Node, word string) *kong.Node {
+	for _, child := range node.Children {
+		if child.Type != kong.CommandNode {
+			continue
+		}
+		if child.Name == word {
+			return child
+		}
+		for _, alias := range child.Aliases {
+			if alias == word {
+				return child
+			}
+		}
+	}
+	return nil
+}
+
+// filter returns the candidates starting with prefix
+func filter(candidates []string, prefix string) []string {
+	var matched []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matched = append(matched, candidate)
+		}
+	}
+	return matched
+}
+
+// union returns the values of a followed by the values of b missing from a
+func union(a, b []string) []string {
+	out := append([]string(nil), a...)
+	for _, v := range b {
+		found := false
+		for _, w := range out {
+			found = found || v == w
+		}
+		if !found {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Script returns the completion script of a shell for a program
+func Script(shell, program string) (string, error) {
+	var script string
+	switch shell {
+	case "bash":
+		script = bashScript
+	case "zsh":
+		script = zshScript
+	case "fish":
+		script = fishScript
+	default:
+		return "", fmt.Errorf("unsupported shell %q (allowed: %s)", shell, strings.Join(Shells, ", "))
+	}
+	r := strings.NewReplacer("{{program}}", program, "{{command}}", Command)
+	return r.Replace(script), nil
+}
+
+const bashScript = `# bash completion for {{program}}
+# Load it with: source <({{program}} completion bash)
+_{{program}}_complete() {
+    local line="${COMP_LINE:0:COMP_POINT}"
+    local -a words
+    read -r -a words <<< "$line"
+    if [[ "$line" =~ [[:space:]]$ ]]; then
+        words+=("")
+    fi
+
+    local IFS=$'\n'
+    COMPREPLY=($({{program}} {{command}} "${words[@]:1}" 2>/dev/null))
+
+    # bash completes the text after = on its own
+    local cur="${words[${#words[@]}-1]}"
+    if [[ "$cur" == -*=* ]]; then
+        COMPREPLY=("${COMPREPLY[@]#*=}")
+    fi
+}
+complete -o default -F _{{program}}_complete {{program}}
+`
+
+const zshScript = `#compdef {{program}}
+# zsh completion for {{program}}
+# Load it with: source <({{program}} completion zsh), or save it as _{{program}} in $fpath
+_{{program}}() {
+    local -a candidates
+    candidates=("${(@f)$({{program}} {{command}} "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    if [[ -n "${candidates[1]}" ]]; then
+        compadd -- "${candidates[@]}"
+    else
+        _files
+    fi
+}
+
+if [[ "${funcstack[1]}" == "_{{program}}" ]]; then
+    _{{program}} "$@"
+else
+    compdef _{{program}} {{program}}
+fi
+`
+
+const fishScript = `# fish completion for {{program}}
+# Load it with: {{program}} completion fish | source
+function __{{program}}_complete
+    set -l words (commandline -opc) (commandline -ct)
+    {{program}} {{command}} $words[2..-1] 2>/dev/null
+end
+complete -c {{program}} -f -a '(__{{program}}_complete)'
+`
@@ -0,0 +1,86 @@
+package completion
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lpmourato/c9s/internal/cli"
+)
+
+// fakeSource knows the services of two projects
+type fakeSource struct{}
+
+func (fakeSource) ProjectIDs() []string { return []string{"demo", "other"} }
+
+func (fakeSource) Regions(projectID string) []string {
+	if projectID == "demo" {
+		return []string{"me-west1", "us-central1"}
+	}
+	return []string{"europe-west1", "me-west1", "us-central1"}
+}
+
+func (fakeSource) Names(projectID, region string) []string {
+	switch {
+	case projectID == "demo" && region == "me-west1":
+		return []string{"worker"}
+	case projectID == "demo":
+		return []string{"backend-api", "worker"}
+	}
+	return []string{"backend-api", "billing", "worker"}
+}
+
+func TestComplete(t *testing.T) {
+	model, err := (&cli.CLI{}).Model()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Completer{
+		App:         model,
+		Source:      fakeSource{},
+		Regions:     []string{"us-central1", "us-east1"},
+		Datasources: []string{"mock", "gcp"},
+	}
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"d", []string{"describe", "doctor"}},
+		{"--data", []string{"--datasource"}},
+		{"--datasource ", []string{"mock", "gcp"}},
+		{"--project ", []string{"demo", "other"}},
+		{"--project demo --region ", []string{"us-central1", "us-east1", "me-west1"}},
+		{"list --region=us-e", []string{"--region=us-east1"}},
+		{"list -o y", []string{"yaml"}},
+		{"list --output=j", []string{"--output=json"}},
+		{"logs b", []string{"backend-api", "billing"}},
+		{"describe --project demo ", []string{"backend-api", "worker"}},
+		{"logs --project=demo --region me-west1 --follow ", []string{"worker"}},
+		{"logs backend-api ", nil},
+		{"describe --show", []string{"--show-secrets"}},
+		{"completion ", []string{"bash", "zsh", "fish"}},
+		{"list --nope=", nil},
+	}
+	for _, tt := range tests {
+		words := strings.Split(tt.line, " ")
+		if got := c.Complete(words); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestScript(t *testing.T) {
+	for _, shell := range Shells {
+		script, err := Script(shell, "c9s")
+		if err != nil {
+			t.Fatalf("Script(%q) error = %v", shell, err)
+		}
+		if !strings.Contains(script, "c9s __complete") || strings.Contains(script, "{{") {
+			t.Errorf("Script(%q) does not call c9s __complete:\n%s", shell, script)
+		}
+	}
+	if _, err := Script("powershell", "c9s"); err == nil {
+		t.Error("Script(powershell) succeeded, want an error")
+	}
+}
@@ -7,6 +7,16 @@ package config
 type CloudRunConfig struct {
 	ProjectID string
 	Region    string
+	// Time range opened by the log view, as accepted by logging.ParseTime
+	LogSince string
+	LogUntil string
+	// Maximum number of entries kept by the log view; zero uses the default
+	LogBufferSize int
+	// Version of c9s shown in the header
+	Version string
+	// CacheServices records the listed services in the completion cache,
+	// for the data sources of real projects
+	CacheServices bool
 }
 
 // NewCloudRunConfig creates a new configuration with default values
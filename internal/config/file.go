@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the content of the c9s configuration file
+type File struct {
+	// Rules raising alerts while logs are tailed
+	Alerts []AlertRule `yaml:"alerts"`
+}
+
+// AlertRule describes a log-based alert. An entry matches the rule when it
+// matches any of Pattern, Severity and Query; the rule fires when more than
+// Threshold entries match within Window.
+type AlertRule struct {
+	Name string `yaml:"name"`
+	// Regular expression matched against the message
+	Pattern string `yaml:"pattern"`
+	// Minimum severity, e.g. ERROR
+	Severity string `yaml:"severity"`
+	// Logging query language expression, evaluated locally
+	Query string `yaml:"query"`
+
+	// Number of matches within Window the rule tolerates before firing
+	Threshold int `yaml:"threshold"`
+	// Time window of the threshold; one minute by default
+	Window time.Duration `yaml:"window"`
+	// Time during which the rule does not fire again; Window by default
+	Cooldown time.Duration `yaml:"cooldown"`
+
+	// Shell command run when the rule fires, reading the alert as JSON on stdin
+	Command string `yaml:"command"`
+	// URL the alert is POSTed to as JSON when the rule fires
+	Webhook string `yaml:"webhook"`
+}
+
+// DefaultPath returns the path of the configuration file in the user
+// configuration directory, e.g. ~/.config/c9s/config.yaml
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "c9s", "config.yaml")
+}
+
+// LoadFile reads a configuration file. Unknown keys are rejected so that
+// typos do not silently disable a setting.
+func LoadFile(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var file File
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return &file, nil
+}
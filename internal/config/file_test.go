@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeConfig(t, `
+alerts:
+  - name: oom
+    pattern: OutOfMemory
+    severity: ERROR
+    threshold: 5
+    window: 1m
+    webhook: http://localhost:9000/hook
+`)
+	file, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if len(file.Alerts) != 1 {
+		t.Fatalf("LoadFile() alerts = %+v, want one rule", file.Alerts)
+	}
+	rule := file.Alerts[0]
+	if rule.Name != "oom" || rule.Pattern != "OutOfMemory" || rule.Threshold != 5 || rule.Window != time.Minute || rule.Webhook == "" {
+		t.Errorf("LoadFile() rule = %+v", rule)
+	}
+}
+
+func TestLoadFileEmpty(t *testing.T) {
+	file, err := LoadFile(writeConfig(t, ""))
+	if err != nil || len(file.Alerts) != 0 {
+		t.Errorf("LoadFile() of an empty file = %+v, %v", file, err)
+	}
+}
+
+func TestLoadFileUnknownKey(t *testing.T) {
+	if _, err := LoadFile(writeConfig(t, "alerts:\n  - name: x\n    treshold: 5\n")); err == nil {
+		t.Error("LoadFile() with a misspelled key succeeded, want an error")
+	}
+}
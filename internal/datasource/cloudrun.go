@@ -7,11 +7,26 @@ import (
 	"google.golang.org/api/option"
 	run "google.golang.org/api/run/v1"
 
+	"github.com/lpmourato/c9s/internal/doctor"
 	"github.com/lpmourato/c9s/internal/domain/cloudrun"
 	"github.com/lpmourato/c9s/internal/infrastructure/gcp"
+	"github.com/lpmourato/c9s/internal/logging"
 	"github.com/lpmourato/c9s/internal/model"
 )
 
+// Regions are the regions whose services GetServices lists
+var Regions = []string{
+	"asia-east1",
+	"europe-west1",
+	"europe-west2",
+	"europe-west3",
+	"europe-west4",
+	"us-central1",
+	"us-east1",
+	"us-east4",
+	"us-west1",
+}
+
 type cloudRunDataSource struct {
 	projectID string
 	client    *run.ProjectsLocationsServicesService
@@ -49,29 +64,21 @@ func (ds *cloudRunDataSource) GetServices() ([]model.Service, error) {
 		return nil, fmt.Errorf("project ID is required")
 	}
 
-	// List services in all regions
-	regions := []string{
-		"asia-east1",
-		"europe-west1",
-		"europe-west2",
-		"europe-west3",
-		"europe-west4",
-		"us-central1",
-		"us-east1",
-		"us-east4",
-		"us-west1",
-	}
-
 	var allServices []model.Service
-	for _, region := range regions {
-		services, err := ds.GetServicesByRegion(region)
+	regionErr := &RegionError{Errors: make(map[string]error)}
+	for _, region := range Regions {
+		services, err := ds.listServices(region)
 		if err != nil {
 			// Skip regions that fail but continue with others
+			regionErr.Errors[region] = err
 			continue
 		}
 		allServices = append(allServices, services...)
 	}
 
+	if len(regionErr.Errors) > 0 {
+		return allServices, regionErr
+	}
 	return allServices, nil
 }
 
@@ -87,6 +94,11 @@ func (ds *cloudRunDataSource) GetServicesByRegion(region string) ([]model.Servic
 		return nil, fmt.Errorf("region is required")
 	}
 
+	return ds.listServices(region)
+}
+
+// listServices lists the services of a region
+func (ds *cloudRunDataSource) listServices(region string) ([]model.Service, error) {
 	parent := fmt.Sprintf("projects/%s/locations/%s", ds.projectID, region)
 	resp, err := ds.client.List(parent).Do()
 	if err != nil {
@@ -116,6 +128,17 @@ func (ds *cloudRunDataSource) GetServiceDetails(name, region string) (*model.Ser
 	return ds.provider.GetServiceDetails(ctx, name, region)
 }
 
+func (ds *cloudRunDataSource) NewLogProvider() (model.LogProvider, error) {
+	if ds.projectID == "" {
+		return nil, fmt.Errorf("project ID is required")
+	}
+	return logging.NewGCPLogService(ds.projectID, "", "")
+}
+
+func (ds *cloudRunDataSource) NewDiagnostics() doctor.Environment {
+	return doctor.NewGCPEnvironment()
+}
+
 // init registers the GCP data source provider for Cloud Run with the global registry.
 // It associates the GCP identifier with a constructor function that creates a new Cloud Run data source
 // using the provided project ID from the configuration. This enables dynamic selection of the data source
@@ -1,8 +1,12 @@
 package datasource
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/lpmourato/c9s/internal/doctor"
 	"github.com/lpmourato/c9s/internal/model"
 )
 
@@ -26,7 +30,8 @@ type Config struct {
 
 // DataSource defines the interface for getting data
 type DataSource interface {
-	// GetServices returns all services
+	// GetServices returns all services. When some regions fail, the services
+	// of the others are returned with a *RegionError.
 	GetServices() ([]model.Service, error)
 	// GetServicesByRegion returns services filtered by region
 	GetServicesByRegion(region string) ([]model.Service, error)
@@ -34,6 +39,59 @@ type DataSource interface {
 	GetProvider() model.CloudRunProvider
 	// GetServiceDetails returns detailed information about a specific service
 	GetServiceDetails(name, region string) (*model.ServiceDetails, error)
+	// NewLogProvider returns a provider of the logs of the services
+	NewLogProvider() (model.LogProvider, error)
+	// NewDiagnostics returns the environment checked by the doctor
+	NewDiagnostics() doctor.Environment
+}
+
+// RegionError reports the regions whose services could not be listed. It is
+// returned by GetServices along with the services of the other regions.
+type RegionError struct {
+	// Errors by region
+	Errors map[string]error
+}
+
+// Regions returns the failed regions, sorted
+func (e *RegionError) Regions() []string {
+	regions := make([]string, 0, len(e.Errors))
+	for region := range e.Errors {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// Error lists the failed regions and their errors
+func (e *RegionError) Error() string {
+	regions := e.Regions()
+	messages := make([]string, len(regions))
+	for i, region := range regions {
+		messages[i] = fmt.Sprintf("%s: %v", region, e.Errors[region])
+	}
+	return fmt.Sprintf("failed to list services in %d regions: %s", len(regions), strings.Join(messages, "; "))
+}
+
+// ListedRegions returns the regions whose services were returned by
+// GetServicesByRegion(region), or by GetServices when region is empty,
+// given the error of the call
+func ListedRegions(region string, err error) []string {
+	var regionErr *RegionError
+	switch {
+	case err == nil && region != "":
+		return []string{region}
+	case err == nil:
+		return Regions
+	case region == "" && errors.As(err, &regionErr):
+		var listed []string
+		for _, r := range Regions {
+			if _, failed := regionErr.Errors[r]; !failed {
+				listed = append(listed, r)
+			}
+		}
+		return listed
+	}
+	return nil
 }
 
 // Factory creates and returns a DataSource based on config
@@ -54,3 +112,13 @@ var registry = make(map[Type]Constructor)
 func Register(t Type, c Constructor) {
 	registry[t] = c
 }
+
+// Types returns the registered data source types, sorted
+func Types() []Type {
+	types := make([]Type, 0, len(registry))
+	for t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
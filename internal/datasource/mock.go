@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/lpmourato/c9s/internal/doctor"
 	"github.com/lpmourato/c9s/internal/mock"
 	"github.com/lpmourato/c9s/internal/model"
 )
@@ -60,6 +61,14 @@ func (ds *mockDataSource) GetServiceDetails(name, region string) (*model.Service
 	return ds.provider.GetServiceDetails(ctx, name, region)
 }
 
+func (ds *mockDataSource) NewLogProvider() (model.LogProvider, error) {
+	return mock.NewLogProvider(), nil
+}
+
+func (ds *mockDataSource) NewDiagnostics() doctor.Environment {
+	return mock.NewDiagnostics(ds.data)
+}
+
 // mockProvider implements model.CloudRunProvider for testing
 type mockProvider struct {
 	serviceName string
@@ -0,0 +1,254 @@
+// Package doctor diagnoses why c9s cannot list services or read logs: it
+// checks the credentials, the project, the enabled APIs, the IAM permissions
+// and each region, and suggests a fix for each failed check.
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	Pass Status = "PASS"
+	Fail Status = "FAIL"
+	// Skip is reported for checks that depend on a failed check, or that do
+	// not apply to the environment
+	Skip Status = "SKIP"
+)
+
+// APIs that c9s calls
+const (
+	RunAPI     = "run.googleapis.com"
+	LoggingAPI = "logging.googleapis.com"
+)
+
+// Permissions are the IAM permissions c9s needs on the project
+var Permissions = []string{
+	"run.services.list",
+	"run.services.get",
+	"logging.logEntries.list",
+}
+
+// Environment is the cloud environment the checks run against
+type Environment interface {
+	// Credentials resolves the Application Default Credentials and returns
+	// the principal they authenticate
+	Credentials(ctx context.Context) (principal string, err error)
+	// Project returns the name of a project
+	Project(ctx context.Context, projectID string) (name string, err error)
+	// ServiceEnabled reports whether an API is enabled in a project
+	ServiceEnabled(ctx context.Context, projectID, service string) (bool, error)
+	// TestPermissions returns the permissions granted to the principal on a project
+	TestPermissions(ctx context.Context, projectID string, permissions []string) ([]string, error)
+	// ListServices returns the number of services of a region
+	ListServices(ctx context.Context, projectID, region string) (int, error)
+}
+
+// ProjectOptional is implemented by environments that work without a
+// project, such as the mock data source
+type ProjectOptional interface {
+	// ProjectOptional reports whether the checks can run without a project
+	ProjectOptional() bool
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail"`
+	// Hint suggests how to fix a failed check
+	Hint string `json:"hint,omitempty"`
+}
+
+// Run runs the checks of a project and its regions in order. Checks that
+// depend on the credentials or on the project are skipped when those fail.
+func Run(ctx context.Context, env Environment, projectID string, regions []string) []Result {
+	var results []Result
+
+	principal, err := env.Credentials(ctx)
+	if err != nil {
+		results = append(results, Result{
+			Check:  "Credentials",
+			Status: Fail,
+			Detail: err.Error(),
+			Hint:   "run 'gcloud auth application-default login', or set GOOGLE_APPLICATION_CREDENTIALS to a service account key file",
+		})
+		return append(results, skipped("requires credentials", projectChecks(regions)...)...)
+	}
+	results = append(results, Result{Check: "Credentials", Status: Pass, Detail: "authenticated as " + principal})
+
+	results = append(results, checkProject(ctx, env, projectID, principal))
+	if results[len(results)-1].Status == Fail {
+		return append(results, skipped("requires access to the project", projectChecks(regions)[1:]...)...)
+	}
+
+	results = append(results,
+		checkAPI(ctx, env, projectID, "Cloud Run API", RunAPI),
+		checkAPI(ctx, env, projectID, "Cloud Logging API", LoggingAPI),
+		checkPermissions(ctx, env, projectID, principal),
+	)
+	return append(results, checkRegions(ctx, env, projectID, regions)...)
+}
+
+// projectChecks returns the names of the checks run after the credentials
+func projectChecks(regions []string) []string {
+	checks := []string{"Project", "Cloud Run API", "Cloud Logging API", "IAM permissions"}
+	for _, region := range regions {
+		checks = append(checks, regionCheck(region))
+	}
+	return checks
+}
+
+// skipped returns the results of skipped checks
+func skipped(reason string, checks ...string) []Result {
+	results := make([]Result, len(checks))
+	for i, check := range checks {
+		results[i] = Result{Check: check, Status: Skip, Detail: reason}
+	}
+	return results
+}
+
+func checkProject(ctx context.Context, env Environment, projectID, principal string) Result {
+	result := Result{Check: "Project"}
+	if optional, ok := env.(ProjectOptional); ok && projectID == "" && optional.ProjectOptional() {
+		result.Status = Skip
+		result.Detail = "not applicable, no project is needed"
+		return result
+	}
+	if projectID == "" {
+		result.Status = Fail
+		result.Detail = "no project is set"
+		result.Hint = "set --project or GOOGLE_CLOUD_PROJECT"
+		return result
+	}
+	name, err := env.Project(ctx, projectID)
+	if err != nil {
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("cannot access %s: %v", projectID, err)
+		result.Hint = fmt.Sprintf("check the project ID, and grant %s a role on it such as roles/viewer", principal)
+		return result
+	}
+	result.Status = Pass
+	result.Detail = projectID
+	if name != "" && name != projectID {
+		result.Detail = fmt.Sprintf("%s (%s)", projectID, name)
+	}
+	return result
+}
+
+func checkAPI(ctx context.Context, env Environment, projectID, check, service string) Result {
+	result := Result{Check: check}
+	enabled, err := env.ServiceEnabled(ctx, projectID, service)
+	switch {
+	case err != nil:
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("cannot check %s: %v", service, err)
+		result.Hint = "grant roles/serviceusage.serviceUsageViewer to check the enabled APIs"
+	case !enabled:
+		result.Status = Fail
+		result.Detail = service + " is disabled"
+		result.Hint = fmt.Sprintf("gcloud services enable %s --project %s", service, projectID)
+	default:
+		result.Status = Pass
+		result.Detail = service + " is enabled"
+	}
+	return result
+}
+
+func checkPermissions(ctx context.Context, env Environment, projectID, principal string) Result {
+	result := Result{Check: "IAM permissions"}
+	granted, err := env.TestPermissions(ctx, projectID, Permissions)
+	if err != nil {
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("cannot test permissions: %v", err)
+		result.Hint = "check that the Cloud Resource Manager API is reachable"
+		return result
+	}
+
+	has := make(map[string]bool, len(granted))
+	for _, p := range granted {
+		has[p] = true
+	}
+	var missing []string
+	for _, p := range Permissions {
+		if !has[p] {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		result.Status = Fail
+		result.Detail = "missing " + strings.Join(missing, ", ")
+		result.Hint = fmt.Sprintf("grant roles/run.viewer and roles/logging.viewer to %s on %s", principal, projectID)
+		return result
+	}
+	result.Status = Pass
+	result.Detail = "granted " + strings.Join(Permissions, ", ")
+	return result
+}
+
+// checkRegions lists the services of each region concurrently
+func checkRegions(ctx context.Context, env Environment, projectID string, regions []string) []Result {
+	results := make([]Result, len(regions))
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			result := Result{Check: regionCheck(region)}
+			count, err := env.ListServices(ctx, projectID, region)
+			if err != nil {
+				result.Status = Fail
+				result.Detail = err.Error()
+				result.Hint = "check the region name, the Cloud Run API and the network access to " + RunAPI
+			} else {
+				result.Status = Pass
+				result.Detail = fmt.Sprintf("%d services", count)
+			}
+			results[i] = result
+		}(i, region)
+	}
+	wg.Wait()
+	return results
+}
+
+func regionCheck(region string) string {
+	return "Region " + region
+}
+
+// Failed returns the number of failed checks
+func Failed(results []Result) int {
+	failed := 0
+	for _, r := range results {
+		if r.Status == Fail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// WriteText writes the results as a table, with the fix hint of each failed check
+func WriteText(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Status, r.Check, r.Detail)
+		if r.Hint != "" {
+			fmt.Fprintf(tw, "\t\tfix: %s\n", r.Hint)
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the results as a JSON array
+func WriteJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
@@ -0,0 +1,152 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeEnvironment fails the checks it has errors for
+type fakeEnvironment struct {
+	credsErr   error
+	projectErr error
+	disabled   map[string]bool
+	granted    []string
+	regionErrs map[string]error
+}
+
+func (e *fakeEnvironment) Credentials(ctx context.Context) (string, error) {
+	return "alice@example.com", e.credsErr
+}
+
+func (e *fakeEnvironment) Project(ctx context.Context, projectID string) (string, error) {
+	return "Demo", e.projectErr
+}
+
+func (e *fakeEnvironment) ServiceEnabled(ctx context.Context, projectID, service string) (bool, error) {
+	return !e.disabled[service], nil
+}
+
+func (e *fakeEnvironment) TestPermissions(ctx context.Context, projectID string, permissions []string) ([]string, error) {
+	return e.granted, nil
+}
+
+func (e *fakeEnvironment) ListServices(ctx context.Context, projectID, region string) (int, error) {
+	return 2, e.regionErrs[region]
+}
+
+// optionalProjectEnvironment is a fake environment that needs no project
+type optionalProjectEnvironment struct {
+	fakeEnvironment
+}
+
+func (e *optionalProjectEnvironment) ProjectOptional() bool {
+	return true
+}
+
+// statuses returns the status of each check by name
+func statuses(results []Result) map[string]Status {
+	m := make(map[string]Status, len(results))
+	for _, r := range results {
+		m[r.Check] = r.Status
+	}
+	return m
+}
+
+func TestRunAllPass(t *testing.T) {
+	results := Run(context.Background(), &fakeEnvironment{granted: Permissions}, "demo", []string{"us-central1"})
+	if len(results) != 6 || Failed(results) != 0 {
+		t.Fatalf("Run() = %+v, want 6 passed checks", results)
+	}
+	if results[1].Detail != "demo (Demo)" {
+		t.Errorf("project detail = %q", results[1].Detail)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	env := &fakeEnvironment{
+		disabled:   map[string]bool{LoggingAPI: true},
+		granted:    []string{"run.services.list"},
+		regionErrs: map[string]error{"us-east1": errors.New("permission denied")},
+	}
+	results := Run(context.Background(), env, "demo", []string{"us-central1", "us-east1"})
+	got := statuses(results)
+	want := map[string]Status{
+		"Credentials":        Pass,
+		"Project":            Pass,
+		"Cloud Run API":      Pass,
+		"Cloud Logging API":  Fail,
+		"IAM permissions":    Fail,
+		"Region us-central1": Pass,
+		"Region us-east1":    Fail,
+	}
+	for check, status := range want {
+		if got[check] != status {
+			t.Errorf("%s = %s, want %s", check, got[check], status)
+		}
+	}
+	for _, r := range results {
+		if r.Status == Fail && r.Hint == "" {
+			t.Errorf("failed check %s has no fix hint", r.Check)
+		}
+	}
+	if !strings.Contains(results[4].Detail, "run.services.get, logging.logEntries.list") {
+		t.Errorf("permissions detail = %q, want the missing permissions", results[4].Detail)
+	}
+	if !strings.Contains(results[3].Hint, "gcloud services enable logging.googleapis.com --project demo") {
+		t.Errorf("logging API hint = %q", results[3].Hint)
+	}
+}
+
+func TestRunSkipsDependentChecks(t *testing.T) {
+	results := Run(context.Background(), &fakeEnvironment{credsErr: errors.New("no credentials")}, "demo", []string{"us-central1"})
+	if len(results) != 6 || results[0].Status != Fail {
+		t.Fatalf("Run() = %+v, want the credentials check to fail", results)
+	}
+	for _, r := range results[1:] {
+		if r.Status != Skip {
+			t.Errorf("%s = %s, want %s", r.Check, r.Status, Skip)
+		}
+	}
+
+	results = Run(context.Background(), &fakeEnvironment{}, "", []string{"us-central1"})
+	if got := statuses(results); got["Project"] != Fail || got["Cloud Run API"] != Skip || got["Region us-central1"] != Skip {
+		t.Errorf("Run() without a project = %+v", results)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	var out bytes.Buffer
+	err := WriteText(&out, []Result{
+		{Check: "Credentials", Status: Pass, Detail: "authenticated as alice@example.com"},
+		{Check: "Project", Status: Fail, Detail: "no project is set", Hint: "set --project"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "PASS  Credentials  authenticated as alice@example.com\n" +
+		"FAIL  Project      no project is set\n" +
+		"                   fix: set --project\n"
+	if out.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRunProjectOptional(t *testing.T) {
+	env := &optionalProjectEnvironment{fakeEnvironment{granted: Permissions}}
+	results := Run(context.Background(), env, "", []string{"us-central1"})
+	if Failed(results) != 0 {
+		t.Fatalf("Run() = %+v, want no failed checks", results)
+	}
+	if got := statuses(results); got["Project"] != Skip || got["Cloud Run API"] != Pass || got["Region us-central1"] != Pass {
+		t.Errorf("Run() without a project = %+v", results)
+	}
+
+	// A project that is set is still checked
+	results = Run(context.Background(), env, "demo", []string{"us-central1"})
+	if got := statuses(results); got["Project"] != Pass {
+		t.Errorf("Project = %s, want %s", got["Project"], Pass)
+	}
+}
@@ -0,0 +1,127 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/cloudresourcemanager/v1"
+	oauth2api "google.golang.org/api/oauth2/v2"
+	"google.golang.org/api/option"
+	run "google.golang.org/api/run/v1"
+	"google.golang.org/api/serviceusage/v1"
+)
+
+// gcpEnvironment runs the checks against Google Cloud with the Application
+// Default Credentials
+type gcpEnvironment struct {
+	creds *google.Credentials
+}
+
+// NewGCPEnvironment returns the Google Cloud environment. The credentials are
+// resolved by its first check, so that a failure is reported as a check.
+func NewGCPEnvironment() Environment {
+	return &gcpEnvironment{}
+}
+
+// Credentials implements Environment. It fetches a token to verify that the
+// credentials work, and returns the email of the service account or user.
+func (e *gcpEnvironment) Credentials(ctx context.Context) (string, error) {
+	creds, err := google.FindDefaultCredentials(ctx, run.CloudPlatformScope)
+	if err != nil {
+		return "", fmt.Errorf("no Application Default Credentials: %v", err)
+	}
+	token, err := creds.TokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to get a token from the Application Default Credentials: %v", err)
+	}
+	e.creds = creds
+
+	// Service account keys name their account
+	var key struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+	}
+	if len(creds.JSON) > 0 && json.Unmarshal(creds.JSON, &key) == nil && key.ClientEmail != "" {
+		return key.ClientEmail, nil
+	}
+
+	// Users and the metadata server are identified by the token
+	svc, err := oauth2api.NewService(ctx, option.WithCredentials(creds))
+	if err == nil {
+		if info, err := svc.Tokeninfo().AccessToken(token.AccessToken).Context(ctx).Do(); err == nil && info.Email != "" {
+			return info.Email, nil
+		}
+	}
+	if key.Type != "" {
+		return "unknown " + key.Type, nil
+	}
+	return "unknown principal", nil
+}
+
+// Project implements Environment
+func (e *gcpEnvironment) Project(ctx context.Context, projectID string) (string, error) {
+	svc, err := cloudresourcemanager.NewService(ctx, e.options()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Resource Manager client: %v", err)
+	}
+	project, err := svc.Projects.Get(projectID).Context(ctx).Do()
+	if err != nil {
+		return "", err
+	}
+	if project.LifecycleState != "" && project.LifecycleState != "ACTIVE" {
+		return "", fmt.Errorf("project is %s", project.LifecycleState)
+	}
+	return project.Name, nil
+}
+
+// ServiceEnabled implements Environment
+func (e *gcpEnvironment) ServiceEnabled(ctx context.Context, projectID, service string) (bool, error) {
+	svc, err := serviceusage.NewService(ctx, e.options()...)
+	if err != nil {
+		return false, fmt.Errorf("failed to create Service Usage client: %v", err)
+	}
+	state, err := svc.Services.Get(fmt.Sprintf("projects/%s/services/%s", projectID, service)).Context(ctx).Do()
+	if err != nil {
+		return false, err
+	}
+	return state.State == "ENABLED", nil
+}
+
+// TestPermissions implements Environment
+func (e *gcpEnvironment) TestPermissions(ctx context.Context, projectID string, permissions []string) ([]string, error) {
+	svc, err := cloudresourcemanager.NewService(ctx, e.options()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Manager client: %v", err)
+	}
+	resp, err := svc.Projects.TestIamPermissions(projectID, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: permissions,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Permissions, nil
+}
+
+// ListServices implements Environment
+func (e *gcpEnvironment) ListServices(ctx context.Context, projectID, region string) (int, error) {
+	svc, err := run.NewService(ctx, e.options()...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Cloud Run client: %v", err)
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", projectID, region)
+	resp, err := run.NewProjectsLocationsServicesService(svc).List(parent).Context(ctx).Do()
+	if err != nil {
+		return 0, err
+	}
+	return len(resp.Items), nil
+}
+
+// options returns the client options using the resolved credentials
+func (e *gcpEnvironment) options() []option.ClientOption {
+	if e.creds == nil {
+		return []option.ClientOption{option.WithScopes(run.CloudPlatformScope)}
+	}
+	return []option.ClientOption{option.WithCredentials(e.creds)}
+}
@@ -0,0 +1,184 @@
+// Package export writes log entries to files so they can be attached to
+// postmortems or processed by other tools. Entries can be written once as a
+// snapshot or appended continuously through a Tee with size-based rotation.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Format represents an export file format
+type Format string
+
+const (
+	// JSONL writes one JSON object per line with the full entry payload
+	JSONL Format = "jsonl"
+	// CSV writes the selected columns with a header row
+	CSV Format = "csv"
+	// Text writes plain "timestamp severity message" lines
+	Text Format = "text"
+)
+
+// DefaultColumns are the CSV columns used when none are chosen
+var DefaultColumns = []string{"timestamp", "severity", "message"}
+
+// columns maps the CSV column names to their value in an entry
+var columns = map[string]func(model.LogEntry) string{
+	"timestamp": func(e model.LogEntry) string { return e.Timestamp.Format(time.RFC3339Nano) },
+	"severity":  func(e model.LogEntry) string { return e.Severity },
+	"message":   func(e model.LogEntry) string { return e.Message },
+	"insertId":  func(e model.LogEntry) string { return e.InsertID },
+	"logName":   func(e model.LogEntry) string { return e.LogName },
+	"trace":     func(e model.LogEntry) string { return e.Trace },
+	"spanId":    func(e model.LogEntry) string { return e.SpanID },
+	"revision":  func(e model.LogEntry) string { return e.ResourceLabels["revision_name"] },
+	"service":   func(e model.LogEntry) string { return e.ResourceLabels["service_name"] },
+	"source":    func(e model.LogEntry) string { return e.Source },
+	"status": func(e model.LogEntry) string {
+		if e.HTTPRequest == nil {
+			return ""
+		}
+		return fmt.Sprintf("%d", e.HTTPRequest.Status)
+	},
+	"url": func(e model.LogEntry) string {
+		if e.HTTPRequest == nil {
+			return ""
+		}
+		return e.HTTPRequest.URL
+	},
+	"latency": func(e model.LogEntry) string {
+		if e.HTTPRequest == nil {
+			return ""
+		}
+		return e.HTTPRequest.Latency.String()
+	},
+}
+
+// FormatForPath infers the export format from a file extension, defaulting to Text
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".json", ".ndjson":
+		return JSONL
+	case ".csv":
+		return CSV
+	default:
+		return Text
+	}
+}
+
+// ParseColumns parses a comma-separated list of CSV columns
+func ParseColumns(text string) ([]string, error) {
+	var result []string
+	for _, name := range strings.Split(text, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		result = append(result, name)
+	}
+	if len(result) == 0 {
+		return DefaultColumns, nil
+	}
+	return result, nil
+}
+
+// Writer writes log entries to an io.Writer in one of the export formats
+type Writer struct {
+	w             io.Writer
+	format        Format
+	columns       []string
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+// NewWriter creates a writer for the given format. columns only apply to CSV
+// and default to DefaultColumns.
+func NewWriter(w io.Writer, format Format, columns []string) *Writer {
+	if len(columns) == 0 {
+		columns = DefaultColumns
+	}
+	writer := &Writer{
+		w:       w,
+		format:  format,
+		columns: columns,
+	}
+	if format == CSV {
+		writer.csv = csv.NewWriter(w)
+	}
+	return writer
+}
+
+// Write writes a single entry
+func (w *Writer) Write(entry model.LogEntry) error {
+	switch w.format {
+	case JSONL:
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		_, err = w.w.Write(append(data, '\n'))
+		return err
+	case CSV:
+		if !w.headerWritten {
+			if err := w.csv.Write(w.columns); err != nil {
+				return err
+			}
+			w.headerWritten = true
+		}
+		record := make([]string, len(w.columns))
+		for i, name := range w.columns {
+			record[i] = columns[name](entry)
+		}
+		return w.csv.Write(record)
+	default:
+		_, err := fmt.Fprintf(w.w, "%s %-8s %s\n",
+			entry.Timestamp.Format(time.RFC3339Nano), entry.Severity, entry.Message)
+		return err
+	}
+}
+
+// Flush flushes any buffered data to the underlying writer
+func (w *Writer) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return nil
+}
+
+// WriteFile writes entries to the file at path, replacing it, and returns its absolute path
+func WriteFile(path string, format Format, columns []string, entries []model.LogEntry) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %v", abs, err)
+	}
+	defer f.Close()
+
+	w := NewWriter(f, format, columns)
+	for _, entry := range entries {
+		if err := w.Write(entry); err != nil {
+			return "", fmt.Errorf("failed to write %s: %v", abs, err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %v", abs, err)
+	}
+	return abs, f.Close()
+}
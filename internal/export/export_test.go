@@ -0,0 +1,160 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+var ts = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestWriterCSV(t *testing.T) {
+	entries := []model.LogEntry{
+		{
+			Timestamp:      ts,
+			Severity:       "ERROR",
+			Message:        `payment failed: "card declined", retrying`,
+			ResourceLabels: map[string]string{"service_name": "checkout"},
+			HTTPRequest:    &model.HTTPRequest{Status: 502, Latency: 1500 * time.Millisecond},
+		},
+		{
+			Timestamp: ts.Add(time.Second),
+			Severity:  "INFO",
+			Message:   "line one\nline two",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		columns string
+		want    string
+	}{
+		{
+			name:    "default columns",
+			columns: "",
+			want: "timestamp,severity,message\n" +
+				`2024-05-01T12:00:00Z,ERROR,"payment failed: ""card declined"", retrying"` + "\n" +
+				"2024-05-01T12:00:01Z,INFO,\"line one\nline two\"\n",
+		},
+		{
+			name:    "selected columns in order",
+			columns: "service, status,latency,severity",
+			want: "service,status,latency,severity\n" +
+				"checkout,502,1.5s,ERROR\n" +
+				",,,INFO\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := ParseColumns(tt.columns)
+			if err != nil {
+				t.Fatalf("ParseColumns(%q) error = %v", tt.columns, err)
+			}
+
+			var buf bytes.Buffer
+			w := NewWriter(&buf, CSV, columns)
+			for _, entry := range entries {
+				if err := w.Write(entry); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("CSV =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseColumnsUnknown(t *testing.T) {
+	if _, err := ParseColumns("timestamp,body"); err == nil {
+		t.Error("ParseColumns() accepted an unknown column")
+	}
+}
+
+// teeEntry returns an entry written as a 39 byte text line
+func teeEntry(i int) model.LogEntry {
+	return model.LogEntry{Timestamp: ts, Severity: "INFO", Message: fmt.Sprintf("entry %02d", i)}
+}
+
+// readMessages returns the messages of the text lines of a file
+func readMessages(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", filepath.Base(path), err)
+	}
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if fields := strings.Fields(line); len(fields) == 4 {
+			msgs = append(msgs, fields[2]+" "+fields[3])
+		}
+	}
+	return msgs
+}
+
+func TestTeeRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.txt")
+	// Three lines exceed MaxSize, so each file holds three entries
+	tee, err := NewTee(path, Text, nil, 100)
+	if err != nil {
+		t.Fatalf("NewTee() error = %v", err)
+	}
+	tee.MaxBackups = 2
+	defer tee.Close()
+
+	for i := 1; i <= 10; i++ {
+		if err := tee.Write(teeEntry(i)); err != nil {
+			t.Fatalf("Write(%d) error = %v", i, err)
+		}
+	}
+
+	files := map[string][]string{
+		path:        {"entry 10"},
+		path + ".1": {"entry 07", "entry 08", "entry 09"},
+		path + ".2": {"entry 04", "entry 05", "entry 06"},
+	}
+	for file, want := range files {
+		if got := readMessages(t, file); fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("%s holds %v, want %v", filepath.Base(file), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("found more than %d backups", tee.MaxBackups)
+	}
+}
+
+func TestTeeRotationError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.txt")
+	tee, err := NewTee(path, Text, nil, 100)
+	if err != nil {
+		t.Fatalf("NewTee() error = %v", err)
+	}
+	tee.MaxBackups = 2
+	defer tee.Close()
+
+	// A directory in the way of the oldest backup cannot be replaced
+	if err := os.MkdirAll(filepath.Join(path+".2", "keep"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var werr error
+	for i := 1; i <= 7 && werr == nil; i++ {
+		werr = tee.Write(teeEntry(i))
+	}
+	if werr == nil {
+		t.Fatal("Write() succeeded although the backups could not be shifted")
+	}
+	if got := readMessages(t, path+".1"); len(got) != 3 {
+		t.Errorf("backup 1 holds %v, want it kept", got)
+	}
+}
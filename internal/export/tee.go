@@ -0,0 +1,139 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+const (
+	// DefaultMaxSize is the size at which a tee file is rotated
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxBackups is the number of rotated files kept next to the active one
+	DefaultMaxBackups = 5
+)
+
+// Tee keeps appending entries to a file as they arrive. When the file grows
+// past MaxSize it is renamed to path.1 (shifting older backups) and a new
+// file is started.
+type Tee struct {
+	Path       string
+	MaxSize    int64
+	MaxBackups int
+
+	mu      sync.Mutex
+	format  Format
+	columns []string
+	file    *os.File
+	counter *countingWriter
+	writer  *Writer
+}
+
+// NewTee opens path for appending in the given format
+func NewTee(path string, format Format, columns []string, maxSize int64) (*Tee, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	t := &Tee{
+		Path:       abs,
+		MaxSize:    maxSize,
+		MaxBackups: DefaultMaxBackups,
+		format:     format,
+		columns:    columns,
+	}
+	if err := t.open(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Write appends an entry, rotating the file first if it is full
+func (t *Tee) Write(entry model.LogEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return fmt.Errorf("tee %s is closed", t.Path)
+	}
+	if t.counter.n >= t.MaxSize {
+		if err := t.rotate(); err != nil {
+			return err
+		}
+	}
+	if err := t.writer.Write(entry); err != nil {
+		return err
+	}
+	return t.writer.Flush()
+}
+
+// Close closes the active file
+func (t *Tee) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
+
+// open opens the active file for appending
+func (t *Tee) open() error {
+	f, err := os.OpenFile(t.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", t.Path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	t.file = f
+	t.counter = &countingWriter{w: f, n: info.Size()}
+	t.writer = NewWriter(t.counter, t.format, t.columns)
+	// Appending CSV to an existing file must not repeat the header
+	t.writer.headerWritten = info.Size() > 0
+	return nil
+}
+
+// rotate shifts the backups, moves the active file to path.1 and reopens it
+func (t *Tee) rotate() error {
+	if err := t.file.Close(); err != nil {
+		return err
+	}
+	t.file = nil
+
+	for i := t.MaxBackups - 1; i >= 1; i-- {
+		src := fmt.Sprintf("%s.%d", t.Path, i)
+		if err := os.Rename(src, fmt.Sprintf("%s.%d", t.Path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate %s: %v", src, err)
+		}
+	}
+	if err := os.Rename(t.Path, t.Path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate %s: %v", t.Path, err)
+	}
+	return t.open()
+}
+
+// countingWriter counts the bytes written to the underlying file
+type countingWriter struct {
+	w *os.File
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"strconv"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// recentKeys is a set of the most recently added keys. Once full, adding a key
+// forgets the oldest one, so memory stays bounded however long a stream runs.
+type recentKeys struct {
+	keys  map[string]struct{}
+	order []string
+	next  int
+}
+
+// newRecentKeys creates a set remembering up to capacity keys
+func newRecentKeys(capacity int) *recentKeys {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &recentKeys{
+		keys:  make(map[string]struct{}, capacity),
+		order: make([]string, 0, capacity),
+	}
+}
+
+// Add adds a key and reports whether it was not already in the set
+func (r *recentKeys) Add(key string) bool {
+	if _, ok := r.keys[key]; ok {
+		return false
+	}
+
+	if len(r.order) < cap(r.order) {
+		r.order = append(r.order, key)
+	} else {
+		delete(r.keys, r.order[r.next])
+		r.order[r.next] = key
+		r.next = (r.next + 1) % len(r.order)
+	}
+	r.keys[key] = struct{}{}
+	return true
+}
+
+// entryKey identifies a log entry by its insertId. Entries without one, such
+// as those of providers that do not assign them, fall back to their content.
+func entryKey(entry model.LogEntry) string {
+	if entry.InsertID != "" {
+		return entry.LogName + "/" + entry.InsertID
+	}
+	return strconv.FormatInt(entry.Timestamp.UnixNano(), 10) + "/" + entry.LogName + "/" + entry.Message
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestRecentKeys(t *testing.T) {
+	keys := newRecentKeys(3)
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{"a", true},
+		{"b", true},
+		{"a", false},
+		{"c", true},
+		// Full: d evicts a, the oldest key
+		{"d", true},
+		{"b", false},
+		{"a", true},
+		// a evicted b, c and d are still known
+		{"c", false},
+		{"d", false},
+		{"b", true},
+	}
+	for i, tt := range tests {
+		if got := keys.Add(tt.key); got != tt.want {
+			t.Errorf("step %d: Add(%q) = %v, want %v", i, tt.key, got, tt.want)
+		}
+	}
+	if len(keys.keys) != 3 {
+		t.Errorf("set holds %d keys, want 3", len(keys.keys))
+	}
+}
+
+func TestEntryKey(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(logName, insertID, msg string) model.LogEntry {
+		return model.LogEntry{Timestamp: ts, LogName: logName, InsertID: insertID, Message: msg}
+	}
+
+	tests := []struct {
+		name string
+		a, b model.LogEntry
+		same bool
+	}{
+		{"same insertId", entry("stdout", "1", "a"), entry("stdout", "1", "b"), true},
+		{"same timestamp, different insertIds", entry("stdout", "1", "a"), entry("stdout", "2", "a"), false},
+		{"same insertId in different logs", entry("stdout", "1", "a"), entry("stderr", "1", "a"), false},
+		{"no insertId, same content", entry("stdout", "", "a"), entry("stdout", "", "a"), true},
+		{"no insertId, different messages", entry("stdout", "", "a"), entry("stdout", "", "b"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entryKey(tt.a) == entryKey(tt.b); got != tt.same {
+				t.Errorf("entryKey(a) == entryKey(b) is %v, want %v", got, tt.same)
+			}
+		})
+	}
+}
+
+func TestLogServiceNewEntries(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// Entries 2i and 2i+1 share a timestamp
+	entries := func(from, to int) []model.LogEntry {
+		var batch []model.LogEntry
+		for i := from; i <= to; i++ {
+			batch = append(batch, model.LogEntry{
+				Timestamp: base.Add(time.Duration(i/2) * time.Second),
+				LogName:   "stdout",
+				InsertID:  fmt.Sprintf("id-%d", i),
+				Message:   "request served",
+			})
+		}
+		return batch
+	}
+
+	s := NewLogService(nil, model.CloudProviderOptions{}).(*LogService)
+
+	tests := []struct {
+		name  string
+		batch []model.LogEntry
+		want  []string
+		last  time.Time
+	}{
+		{"first batch", entries(0, 5), []string{"id-0", "id-1", "id-2", "id-3", "id-4", "id-5"}, base.Add(2 * time.Second)},
+		{"overlapping batch", entries(3, 8), []string{"id-6", "id-7", "id-8"}, base.Add(4 * time.Second)},
+		{"repeated batch", entries(0, 8), nil, base.Add(4 * time.Second)},
+	}
+	for _, tt := range tests {
+		got := s.newEntries(tt.batch)
+		var ids []string
+		for _, entry := range got {
+			ids = append(ids, entry.InsertID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: newEntries() = %v, want %v", tt.name, ids, tt.want)
+		}
+		if !s.lastTimestamp.Equal(tt.last) {
+			t.Errorf("%s: lastTimestamp = %v, want %v", tt.name, s.lastTimestamp, tt.last)
+		}
+	}
+}
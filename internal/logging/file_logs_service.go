@@ -0,0 +1,395 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Ensure FileLogProvider can back a log service
+var _ model.LogProvider = (*FileLogProvider)(nil)
+
+// FileLogProvider implements model.LogProvider over log entries loaded from
+// files, so exported logs can be analyzed offline with the same views.
+// Filters are evaluated locally with ParseQuery.
+type FileLogProvider struct {
+	path string
+	// Entries of all the files, oldest first
+	entries []model.LogEntry
+
+	// Last parsed filter, reused while paging and polling
+	mu     sync.Mutex
+	filter string
+	query  *Query
+}
+
+// NewFileLogProvider loads the log entries of a file, or of the .json, .jsonl
+// and .ndjson files of a directory. Files hold either a JSON array of entries,
+// as written by gcloud logging read --format=json, or one entry per line, as
+// written by log sinks and by the c9s JSON export.
+func NewFileLogProvider(path string) (*FileLogProvider, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logs: %v", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		if files, err = logFiles(path); err != nil {
+			return nil, fmt.Errorf("failed to list logs in %s: %v", path, err)
+		}
+	}
+
+	p := &FileLogProvider{path: path}
+	for _, file := range files {
+		entries, err := readLogFile(file)
+		if err != nil {
+			return nil, err
+		}
+		p.entries = append(p.entries, entries...)
+	}
+	sort.SliceStable(p.entries, func(i, j int) bool {
+		return p.entries[i].Timestamp.Before(p.entries[j].Timestamp)
+	})
+	return p, nil
+}
+
+// Path returns the file or directory the entries were loaded from
+func (p *FileLogProvider) Path() string {
+	return p.path
+}
+
+// Len returns the number of loaded entries
+func (p *FileLogProvider) Len() int {
+	return len(p.entries)
+}
+
+// GetBaseFilter implements model.LogProvider using the Cloud Run filter format
+func (p *FileLogProvider) GetBaseFilter(serviceName string) string {
+	return fmt.Sprintf(`resource.type="cloud_run_revision" resource.labels.service_name="%s"`, serviceName)
+}
+
+// BuildFilter implements model.LogProvider
+func (p *FileLogProvider) BuildFilter(baseFilter string, timestamp time.Time) string {
+	if timestamp.IsZero() {
+		return baseFilter
+	}
+	clause := fmt.Sprintf(`timestamp >= "%s"`, timestamp.Format(time.RFC3339Nano))
+	if baseFilter == "" {
+		return clause
+	}
+	return baseFilter + " AND " + clause
+}
+
+// FetchLogs implements model.LogProvider, returning the oldest entries matching filter
+func (p *FileLogProvider) FetchLogs(ctx context.Context, filter string, pageSize int) ([]model.LogEntry, error) {
+	page, err := p.FetchPage(ctx, model.LogPageRequest{Filter: filter, PageSize: pageSize})
+	return page.Entries, err
+}
+
+// FetchPage implements model.LogProvider. Page tokens are the index of the
+// entry the next page starts scanning from.
+func (p *FileLogProvider) FetchPage(ctx context.Context, req model.LogPageRequest) (model.LogPage, error) {
+	if err := ctx.Err(); err != nil {
+		return model.LogPage{}, err
+	}
+	query, err := p.parse(req.Filter)
+	if err != nil {
+		return model.LogPage{}, err
+	}
+
+	step, index := 1, 0
+	if req.NewestFirst {
+		step, index = -1, len(p.entries)-1
+	}
+	if req.PageToken != "" {
+		if index, err = strconv.Atoi(req.PageToken); err != nil {
+			return model.LogPage{}, fmt.Errorf("invalid page token %q: %v", req.PageToken, err)
+		}
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	var page model.LogPage
+	for ; index >= 0 && index < len(p.entries); index += step {
+		if len(page.Entries) == pageSize {
+			page.NextPageToken = strconv.Itoa(index)
+			break
+		}
+		if query.Match(p.entries[index]) {
+			page.Entries = append(page.Entries, p.entries[index])
+		}
+	}
+	return page, nil
+}
+
+// parse returns the query of a filter, reusing the previous one when unchanged
+func (p *FileLogProvider) parse(filter string) (*Query, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.query != nil && p.filter == filter {
+		return p.query, nil
+	}
+	query, err := ParseQuery(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %v", err)
+	}
+	p.filter, p.query = filter, query
+	return query, nil
+}
+
+// logFiles returns the log files of a directory and its subdirectories
+func logFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".json", ".jsonl", ".ndjson":
+			if !d.IsDir() {
+				files = append(files, path)
+			}
+		}
+		return nil
+	})
+	return files, err
+}
+
+// readLogFile reads the entries of a JSON array or JSON lines file
+func readLogFile(path string) ([]model.LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open logs: %v", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	array, err := startsWithArray(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+
+	var entries []model.LogEntry
+	dec := json.NewDecoder(r)
+	if array {
+		var records []fileEntry
+		if err := dec.Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+		for _, record := range records {
+			entries = append(entries, record.toLogEntry())
+		}
+		return entries, nil
+	}
+
+	for {
+		var record fileEntry
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse entry %d of %s: %v", len(entries)+1, path, err)
+		}
+		entries = append(entries, record.toLogEntry())
+	}
+}
+
+// startsWithArray reports whether the first non-blank character of r opens a JSON array
+func startsWithArray(r *bufio.Reader) (bool, error) {
+	for {
+		b, err := r.Peek(1)
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if !bytes.ContainsAny(b, " \t\r\n") {
+			return b[0] == '[', nil
+		}
+		r.ReadByte()
+	}
+}
+
+// fileEntry is a log entry as written by the Logging API, gcloud and log
+// sinks, or by the c9s JSON export
+type fileEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Severity  string    `json:"severity"`
+
+	// Logging API payloads
+	TextPayload  string                 `json:"textPayload"`
+	JSONPayload  map[string]interface{} `json:"jsonPayload"`
+	ProtoPayload map[string]interface{} `json:"protoPayload"`
+	// c9s export payload
+	Message string                 `json:"message"`
+	Payload map[string]interface{} `json:"payload"`
+
+	InsertID string `json:"insertId"`
+	LogName  string `json:"logName"`
+	Resource *struct {
+		Type   string            `json:"type"`
+		Labels map[string]string `json:"labels"`
+	} `json:"resource"`
+	ResourceType   string            `json:"resourceType"`
+	ResourceLabels map[string]string `json:"resourceLabels"`
+	Labels         map[string]string `json:"labels"`
+
+	HTTPRequest    *fileHTTPRequest `json:"httpRequest"`
+	Trace          string           `json:"trace"`
+	SpanID         string           `json:"spanId"`
+	SourceLocation *struct {
+		File     string  `json:"file"`
+		Line     flexInt `json:"line"`
+		Function string  `json:"function"`
+	} `json:"sourceLocation"`
+
+	Source string `json:"source"`
+}
+
+// fileHTTPRequest is an HTTP request with the field names of either format
+type fileHTTPRequest struct {
+	RequestMethod string       `json:"requestMethod"`
+	Method        string       `json:"method"`
+	RequestURL    string       `json:"requestUrl"`
+	URL           string       `json:"url"`
+	Status        flexInt      `json:"status"`
+	RequestSize   flexInt      `json:"requestSize"`
+	ResponseSize  flexInt      `json:"responseSize"`
+	UserAgent     string       `json:"userAgent"`
+	RemoteIP      string       `json:"remoteIp"`
+	Protocol      string       `json:"protocol"`
+	Latency       flexDuration `json:"latency"`
+}
+
+// toLogEntry converts a file entry to our model.LogEntry
+func (e fileEntry) toLogEntry() model.LogEntry {
+	result := model.LogEntry{
+		Timestamp:      e.Timestamp,
+		Severity:       strings.ToUpper(e.Severity),
+		Message:        e.Message,
+		Payload:        e.Payload,
+		InsertID:       e.InsertID,
+		LogName:        e.LogName,
+		ResourceType:   e.ResourceType,
+		ResourceLabels: e.ResourceLabels,
+		Labels:         e.Labels,
+		Trace:          e.Trace,
+		SpanID:         e.SpanID,
+		Source:         e.Source,
+	}
+	if result.Severity == "" {
+		result.Severity = model.SeverityDefault
+	}
+	if e.Resource != nil {
+		result.ResourceType = e.Resource.Type
+		result.ResourceLabels = e.Resource.Labels
+	}
+
+	switch {
+	case e.TextPayload != "":
+		result.Message = e.TextPayload
+	case e.JSONPayload != nil:
+		result.Payload = e.JSONPayload
+	case e.ProtoPayload != nil:
+		result.Payload = e.ProtoPayload
+	}
+	if result.Message == "" {
+		result.Message = payloadMessage(result.Payload)
+	}
+
+	if req := e.HTTPRequest; req != nil {
+		result.HTTPRequest = &model.HTTPRequest{
+			Method:       firstNonEmpty(req.RequestMethod, req.Method),
+			URL:          firstNonEmpty(req.RequestURL, req.URL),
+			Status:       int(req.Status),
+			RequestSize:  int64(req.RequestSize),
+			ResponseSize: int64(req.ResponseSize),
+			UserAgent:    req.UserAgent,
+			RemoteIP:     req.RemoteIP,
+			Protocol:     req.Protocol,
+			Latency:      time.Duration(req.Latency),
+		}
+	}
+
+	if loc := e.SourceLocation; loc != nil {
+		result.SourceLocation = &model.SourceLocation{
+			File:     loc.File,
+			Line:     int64(loc.Line),
+			Function: loc.Function,
+		}
+	}
+	return result
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// flexInt is an integer written as a JSON number or, for 64-bit fields of
+// the Logging API, as a string
+type flexInt int64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *flexInt) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		return nil
+	}
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", data)
+	}
+	*n = flexInt(value)
+	return nil
+}
+
+// flexDuration is a duration written as a Logging API string such as
+// "0.25s" or as a number of nanoseconds by the c9s export
+type flexDuration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *flexDuration) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if strings.HasPrefix(text, `"`) {
+		value, err := time.ParseDuration(strings.Trim(text, `"`))
+		if err != nil {
+			return fmt.Errorf("invalid duration %s", data)
+		}
+		*d = flexDuration(value)
+		return nil
+	}
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	*d = flexDuration(value)
+	return nil
+}
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// gcloudDump is the output of gcloud logging read --format=json, newest first
+const gcloudDump = `[
+  {
+    "httpRequest": {"requestMethod": "GET", "requestUrl": "https://checkout/pay", "status": 503, "responseSize": "120", "latency": "0.250s"},
+    "insertId": "b",
+    "logName": "projects/p/logs/run.googleapis.com%2Frequests",
+    "resource": {"type": "cloud_run_revision", "labels": {"service_name": "checkout"}},
+    "severity": "ERROR",
+    "timestamp": "2024-05-01T12:00:02Z"
+  },
+  {
+    "insertId": "a",
+    "jsonPayload": {"message": "starting", "port": 8080},
+    "logName": "projects/p/logs/run.googleapis.com%2Fstdout",
+    "resource": {"type": "cloud_run_revision", "labels": {"service_name": "checkout"}},
+    "severity": "INFO",
+    "sourceLocation": {"file": "main.go", "line": "12"},
+    "timestamp": "2024-05-01T12:00:01Z"
+  }
+]`
+
+// exportDump is the c9s JSON export of a text entry
+const exportDump = `{"timestamp":"2024-05-01T12:00:03Z","severity":"WARNING","message":"slow query","resourceType":"cloud_run_revision","resourceLabels":{"service_name":"cart"},"httpRequest":{"method":"POST","status":200,"latency":2000000000}}
+`
+
+func TestFileLogProvider(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "gcloud.json"), []byte(gcloudDump), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "export.jsonl"), []byte(exportDump), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not logs"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewFileLogProvider(dir)
+	if err != nil {
+		t.Fatalf("NewFileLogProvider() failed: %v", err)
+	}
+	if p.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", p.Len())
+	}
+
+	ctx := context.Background()
+	all, err := p.FetchLogs(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("FetchLogs() failed: %v", err)
+	}
+	var messages []string
+	for _, entry := range all {
+		messages = append(messages, entry.Message)
+	}
+	if want := []string{"starting", "", "slow query"}; len(messages) != len(want) || messages[0] != want[0] || messages[2] != want[2] {
+		t.Errorf("FetchLogs() messages = %q, want %q", messages, want)
+	}
+
+	req := all[1].HTTPRequest
+	if req == nil || req.Method != "GET" || req.Status != 503 || req.ResponseSize != 120 || req.Latency != 250*time.Millisecond {
+		t.Errorf("gcloud HTTP request = %+v", req)
+	}
+	if loc := all[0].SourceLocation; loc == nil || loc.Line != 12 {
+		t.Errorf("gcloud source location = %+v", loc)
+	}
+	if req := all[2].HTTPRequest; req == nil || req.Method != "POST" || req.Latency != 2*time.Second {
+		t.Errorf("export HTTP request = %+v", req)
+	}
+
+	filter := QueryFilter(p, model.CloudProviderOptions{ServiceName: "checkout", MinSeverity: "WARNING"})
+	page, err := p.FetchPage(ctx, model.LogPageRequest{Filter: filter, PageSize: 10})
+	if err != nil {
+		t.Fatalf("FetchPage(%q) failed: %v", filter, err)
+	}
+	if len(page.Entries) != 1 || page.Entries[0].InsertID != "b" {
+		t.Errorf("FetchPage(%q) = %+v, want entry b", filter, page.Entries)
+	}
+}
+
+func TestFileLogProviderPaging(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.jsonl")
+	if err := os.WriteFile(path, []byte(gcloudDump), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewFileLogProvider(path)
+	if err != nil {
+		t.Fatalf("NewFileLogProvider() failed: %v", err)
+	}
+
+	ctx := context.Background()
+	var got []string
+	token := ""
+	for {
+		page, err := p.FetchPage(ctx, model.LogPageRequest{PageSize: 1, PageToken: token, NewestFirst: true})
+		if err != nil {
+			t.Fatalf("FetchPage() failed: %v", err)
+		}
+		for _, entry := range page.Entries {
+			got = append(got, entry.InsertID)
+		}
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+	if len(got) != 2 || got[0] != "b" || got[1] != "a" {
+		t.Errorf("newest first pages = %q, want [b a]", got)
+	}
+
+	if _, err := p.FetchPage(ctx, model.LogPageRequest{Filter: "(severity"}); err == nil {
+		t.Error("FetchPage() with an invalid filter succeeded, want an error")
+	}
+}
@@ -0,0 +1,143 @@
+package logging
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Logs written by Cloud Run
+const (
+	// RequestsLogID is the log Cloud Run writes a request log entry to for every HTTP request
+	RequestsLogID = "run.googleapis.com/requests"
+	// StdoutLogID and StderrLogID receive the output of the containers
+	StdoutLogID = "run.googleapis.com/stdout"
+	StderrLogID = "run.googleapis.com/stderr"
+	// SystemLogID receives the platform events of the instances: starts and
+	// stops, probe results, memory limits and scaling
+	SystemLogID = "run.googleapis.com/varlog/system"
+)
+
+// AuditActivityLogID is the log of the Admin Activity audit logs, recording
+// the calls that change the configuration of resources
+const AuditActivityLogID = "cloudaudit.googleapis.com/activity"
+
+// LogKind is a family of Cloud Run logs the log view can be restricted to
+type LogKind int
+
+const (
+	AllLogs LogKind = iota
+	// ApplicationLogs are the stdout and stderr of the containers
+	ApplicationLogs
+	RequestLogs
+	SystemLogs
+)
+
+// String returns the name of a log kind
+func (k LogKind) String() string {
+	switch k {
+	case ApplicationLogs:
+		return "application"
+	case RequestLogs:
+		return "requests"
+	case SystemLogs:
+		return "system"
+	}
+	return "all"
+}
+
+// LogIDs returns the logs of a log kind; nil means all logs
+func (k LogKind) LogIDs() []string {
+	switch k {
+	case ApplicationLogs:
+		return []string{StdoutLogID, StderrLogID}
+	case RequestLogs:
+		return []string{RequestsLogID}
+	case SystemLogs:
+		return []string{SystemLogID}
+	}
+	return nil
+}
+
+// Next returns the log kind following k, back to AllLogs after the last one
+func (k LogKind) Next() LogKind {
+	return (k + 1) % (SystemLogs + 1)
+}
+
+// LogIDOf returns the log ID of an entry, e.g. run.googleapis.com/stdout
+func LogIDOf(entry model.LogEntry) string {
+	i := strings.LastIndex(entry.LogName, "/logs/")
+	if i < 0 {
+		return ""
+	}
+	id, err := url.PathUnescape(entry.LogName[i+len("/logs/"):])
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// QueryFilter returns the full query for opts. The provider base filter
+// restricts it to opts.ServiceName; without a service name the query covers
+// the logs of all services.
+func QueryFilter(provider model.LogProvider, opts model.CloudProviderOptions) string {
+	baseFilter := ""
+	if opts.ServiceName != "" {
+		baseFilter = provider.GetBaseFilter(opts.ServiceName)
+	}
+	return ComposeFilter(baseFilter, opts)
+}
+
+// ComposeFilter combines a provider base filter with the location, revision,
+// log, trace, severity floor, time range and user expression from opts into a single Logging query.
+// Values are quoted with their quotes and backslashes escaped. The user
+// expression is wrapped in parentheses so that its OR terms cannot widen the
+// restrictions placed before it.
+func ComposeFilter(baseFilter string, opts model.CloudProviderOptions) string {
+	var clauses []string
+	if baseFilter != "" {
+		clauses = append(clauses, baseFilter)
+	}
+	if opts.Region != "" {
+		clauses = append(clauses, "resource.labels.location="+strconv.Quote(opts.Region))
+	}
+	if opts.Revision != "" {
+		clauses = append(clauses, "resource.labels.revision_name="+strconv.Quote(opts.Revision))
+	}
+	if len(opts.LogIDs) > 0 {
+		logs := make([]string, len(opts.LogIDs))
+		for i, id := range opts.LogIDs {
+			logs[i] = "log_id(" + strconv.Quote(id) + ")"
+		}
+		clause := strings.Join(logs, " OR ")
+		if len(logs) > 1 {
+			clause = "(" + clause + ")"
+		}
+		clauses = append(clauses, clause)
+	}
+	if opts.Trace != "" {
+		clauses = append(clauses, "trace="+strconv.Quote(opts.Trace))
+	}
+	if opts.MinSeverity != "" {
+		// Cloud Logging rejects aliases such as WARN
+		severity, ok := model.NormalizeSeverity(opts.MinSeverity)
+		if !ok {
+			severity = strings.ToUpper(opts.MinSeverity)
+		}
+		clauses = append(clauses, fmt.Sprintf("severity>=%s", severity))
+	}
+	if !opts.Since.IsZero() {
+		clauses = append(clauses, fmt.Sprintf(`timestamp>="%s"`, opts.Since.Format(time.RFC3339Nano)))
+	}
+	if !opts.Until.IsZero() {
+		clauses = append(clauses, fmt.Sprintf(`timestamp<="%s"`, opts.Until.Format(time.RFC3339Nano)))
+	}
+	if query := strings.TrimSpace(opts.Query); query != "" {
+		clauses = append(clauses, "("+query+")")
+	}
+	return strings.Join(clauses, " AND ")
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestComposeFilter(t *testing.T) {
+	base := `resource.type="cloud_run_revision" resource.labels.service_name="checkout"`
+	since := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	tests := []struct {
+		name string
+		base string
+		opts model.CloudProviderOptions
+		want string
+	}{
+		{
+			name: "base filter only",
+			base: base,
+			want: base,
+		},
+		{
+			name: "empty",
+			want: ``,
+		},
+		{
+			name: "region and revision",
+			base: base,
+			opts: model.CloudProviderOptions{Region: "europe-west1", Revision: "checkout-00042-abc"},
+			want: base + ` AND resource.labels.location="europe-west1" AND resource.labels.revision_name="checkout-00042-abc"`,
+		},
+		{
+			name: "one log ID",
+			base: base,
+			opts: model.CloudProviderOptions{LogIDs: []string{RequestsLogID}},
+			want: base + ` AND log_id("run.googleapis.com/requests")`,
+		},
+		{
+			name: "several log IDs",
+			base: base,
+			opts: model.CloudProviderOptions{LogIDs: ApplicationLogs.LogIDs()},
+			want: base + ` AND (log_id("run.googleapis.com/stdout") OR log_id("run.googleapis.com/stderr"))`,
+		},
+		{
+			name: "trace",
+			opts: model.CloudProviderOptions{Trace: "projects/p/traces/abc"},
+			want: `trace="projects/p/traces/abc"`,
+		},
+		{
+			name: "quotes and backslashes are escaped",
+			opts: model.CloudProviderOptions{
+				Region:   `us" OR "x`,
+				Revision: `rev\"`,
+				LogIDs:   []string{`a"b`},
+				Trace:    `t") OR trace=("`,
+			},
+			want: `resource.labels.location="us\" OR \"x" AND resource.labels.revision_name="rev\\\"" AND log_id("a\"b") AND trace="t\") OR trace=(\""`,
+		},
+		{
+			name: "severity",
+			base: base,
+			opts: model.CloudProviderOptions{MinSeverity: model.SeverityError},
+			want: base + ` AND severity>=ERROR`,
+		},
+		{
+			name: "severity alias",
+			opts: model.CloudProviderOptions{MinSeverity: "warn"},
+			want: `severity>=WARNING`,
+		},
+		{
+			name: "time bounds",
+			base: base,
+			opts: model.CloudProviderOptions{Since: since, Until: until},
+			want: base + ` AND timestamp>="2024-05-01T11:00:00Z" AND timestamp<="2024-05-01T12:00:00.0000005Z"`,
+		},
+		{
+			name: "since only",
+			opts: model.CloudProviderOptions{Since: since},
+			want: `timestamp>="2024-05-01T11:00:00Z"`,
+		},
+		{
+			name: "query is wrapped in parentheses",
+			base: base,
+			opts: model.CloudProviderOptions{Query: ` jsonPayload.order.id=42 `},
+			want: base + ` AND (jsonPayload.order.id=42)`,
+		},
+		{
+			name: "blank query",
+			base: base,
+			opts: model.CloudProviderOptions{Query: "   "},
+			want: base,
+		},
+		{
+			name: "query with OR cannot widen the restrictions",
+			base: base,
+			opts: model.CloudProviderOptions{Region: "us-central1", Query: `severity=ERROR OR textPayload:"timeout"`},
+			want: base + ` AND resource.labels.location="us-central1" AND (severity=ERROR OR textPayload:"timeout")`,
+		},
+		{
+			name: "all options",
+			base: base,
+			opts: model.CloudProviderOptions{
+				Region:      "europe-west1",
+				Revision:    "checkout-00042-abc",
+				LogIDs:      []string{StderrLogID},
+				Trace:       "projects/p/traces/abc",
+				MinSeverity: model.SeverityWarning,
+				Since:       since,
+				Until:       until,
+				Query:       "payment",
+			},
+			want: base +
+				` AND resource.labels.location="europe-west1"` +
+				` AND resource.labels.revision_name="checkout-00042-abc"` +
+				` AND log_id("run.googleapis.com/stderr")` +
+				` AND trace="projects/p/traces/abc"` +
+				` AND severity>=WARNING` +
+				` AND timestamp>="2024-05-01T11:00:00Z"` +
+				` AND timestamp<="2024-05-01T12:00:00.0000005Z"` +
+				` AND (payment)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ComposeFilter(tt.base, tt.opts); got != tt.want {
+				t.Errorf("ComposeFilter() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
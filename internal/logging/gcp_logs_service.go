@@ -2,6 +2,7 @@ package logging
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,6 +10,11 @@ import (
 	"cloud.google.com/go/logging/apiv2/loggingpb"
 	"github.com/lpmourato/c9s/internal/model"
 	"google.golang.org/api/iterator"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	// Registers the AuditLog type, so the protoPayload of audit logs can be
+	// converted to JSON
+	_ "google.golang.org/genproto/googleapis/cloud/audit"
 )
 
 // GCPLogProvider handles log streaming for GCP Cloud Run
@@ -63,16 +69,114 @@ func (p *GCPLogProvider) FetchLogs(ctx context.Context, filter string, pageSize
 			return nil, err
 		}
 
-		logs = append(logs, model.LogEntry{
-			Timestamp: entry.GetTimestamp().AsTime(),
-			Severity:  entry.GetSeverity().String(),
-			Message:   entry.GetTextPayload(),
-		})
+		logs = append(logs, toLogEntry(entry))
 	}
 
 	return logs, nil
 }
 
+// FetchPage implements LogProvider.FetchPage using the page tokens of the Logging API
+func (p *GCPLogProvider) FetchPage(ctx context.Context, req model.LogPageRequest) (model.LogPage, error) {
+	orderBy := "timestamp asc"
+	if req.NewestFirst {
+		orderBy = "timestamp desc"
+	}
+
+	it := p.client.ListLogEntries(ctx, &loggingpb.ListLogEntriesRequest{
+		ResourceNames: []string{fmt.Sprintf("projects/%s", p.projectID)},
+		Filter:        req.Filter,
+		OrderBy:       orderBy,
+		PageSize:      int32(req.PageSize),
+	})
+
+	var entries []*loggingpb.LogEntry
+	nextPageToken, err := iterator.NewPager(it, req.PageSize, req.PageToken).NextPage(&entries)
+	if err != nil {
+		return model.LogPage{}, err
+	}
+
+	page := model.LogPage{
+		Entries:       make([]model.LogEntry, 0, len(entries)),
+		NextPageToken: nextPageToken,
+	}
+	for _, entry := range entries {
+		page.Entries = append(page.Entries, toLogEntry(entry))
+	}
+	return page, nil
+}
+
+// toLogEntry converts a Logging API entry to our model.LogEntry
+func toLogEntry(entry *loggingpb.LogEntry) model.LogEntry {
+	result := model.LogEntry{
+		Timestamp:      entry.GetTimestamp().AsTime(),
+		Severity:       entry.GetSeverity().String(),
+		Message:        entry.GetTextPayload(),
+		InsertID:       entry.GetInsertId(),
+		LogName:        entry.GetLogName(),
+		ResourceType:   entry.GetResource().GetType(),
+		ResourceLabels: entry.GetResource().GetLabels(),
+		Labels:         entry.GetLabels(),
+		Trace:          entry.GetTrace(),
+		SpanID:         entry.GetSpanId(),
+	}
+
+	// Structured payloads are kept as maps; the message falls back to their
+	// "message" field or to the whole payload as JSON
+	switch {
+	case entry.GetJsonPayload() != nil:
+		result.Payload = entry.GetJsonPayload().AsMap()
+	case entry.GetProtoPayload() != nil:
+		if data, err := protojson.Marshal(entry.GetProtoPayload()); err == nil {
+			var payload map[string]interface{}
+			if json.Unmarshal(data, &payload) == nil {
+				result.Payload = payload
+			}
+		}
+	}
+	if result.Message == "" {
+		result.Message = payloadMessage(result.Payload)
+	}
+
+	if req := entry.GetHttpRequest(); req != nil {
+		result.HTTPRequest = &model.HTTPRequest{
+			Method:       req.GetRequestMethod(),
+			URL:          req.GetRequestUrl(),
+			Status:       int(req.GetStatus()),
+			RequestSize:  req.GetRequestSize(),
+			ResponseSize: req.GetResponseSize(),
+			UserAgent:    req.GetUserAgent(),
+			RemoteIP:     req.GetRemoteIp(),
+			Protocol:     req.GetProtocol(),
+			Latency:      req.GetLatency().AsDuration(),
+		}
+	}
+
+	if loc := entry.GetSourceLocation(); loc != nil {
+		result.SourceLocation = &model.SourceLocation{
+			File:     loc.GetFile(),
+			Line:     loc.GetLine(),
+			Function: loc.GetFunction(),
+		}
+	}
+
+	return result
+}
+
+// payloadMessage returns the message of a structured payload: its "message"
+// field, or the whole payload as JSON
+func payloadMessage(payload map[string]interface{}) string {
+	if payload == nil {
+		return ""
+	}
+	if msg, ok := payload["message"].(string); ok {
+		return msg
+	}
+	if data, err := json.Marshal(payload); err == nil {
+		return string(data)
+	}
+	return ""
+}
+
 // BuildFilter implements LogProvider.BuildFilter
 func (p *GCPLogProvider) BuildFilter(baseFilter string, timestamp time.Time) string {
 	if timestamp.IsZero() {
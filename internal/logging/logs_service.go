@@ -3,7 +3,8 @@ package logging
 import (
 	"context"
 	"fmt"
-	"sort"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/lpmourato/c9s/internal/model"
@@ -12,16 +13,32 @@ import (
 const (
 	defaultFlushTimeout = 500 * time.Millisecond
 	defaultBatchSize    = 100
-	maxLogEntries       = 100
 	minPollInterval     = 3 * time.Second
 	maxPollInterval     = 30 * time.Second
 	pollBackoffFactor   = 1.5
+
+	// dedupeOverlap is how far each poll reaches back before the newest entry
+	// already sent, to catch entries sharing its timestamp or ingested late
+	dedupeOverlap = 10 * time.Second
+	// dedupeCapacity is the number of recent entries remembered to skip duplicates
+	dedupeCapacity = 10000
+
+	// StatusLogName is the log name of the status messages a LogService sends
+	// along with the entries, such as the progress of the initial load
+	StatusLogName = "c9s/status"
 )
 
+// IsStatus reports whether an entry is a status message of a LogService
+func IsStatus(entry model.LogEntry) bool {
+	return entry.LogName == StatusLogName
+}
+
 // LogService provides a generic implementation for log streaming
 type LogService struct {
 	provider model.LogProvider
 	opts     model.CloudProviderOptions
+	// Stages the entries go through before reaching the consumer
+	stages []Stage
 
 	// Buffering and batching
 	buffer       []model.LogEntry
@@ -30,10 +47,25 @@ type LogService struct {
 
 	// Stream state
 	lastTimestamp time.Time
+	seen          *recentKeys
 	initialized   bool
 	pollInterval  time.Duration
+
+	// History paging state, shared with FetchOlder. Once the pages of the
+	// initial filter are exhausted, older pages are queried without its lower
+	// bound, before the oldest entry loaded; the keys of the entries at that
+	// timestamp tell the ones already loaded.
+	mu            sync.Mutex
+	historyFilter string
+	olderToken    string
+	oldest        time.Time
+	oldestKeys    map[string]bool
+	unbounded     bool
 }
 
+// Ensure LogService can page back through history
+var _ model.LogPager = (*LogService)(nil)
+
 // GetInitialTimeWindows returns the standard time windows for initial log fetching
 func (s *LogService) getInitialTimeWindows() []model.TimeWindow {
 	return []model.TimeWindow{
@@ -44,15 +76,17 @@ func (s *LogService) getInitialTimeWindows() []model.TimeWindow {
 	}
 }
 
-// NewLogService creates a new log streaming service
-func NewLogService(provider model.LogProvider, opts model.CloudProviderOptions) model.LogStreamer {
+// NewLogService creates a new log streaming service whose entries go through stages
+func NewLogService(provider model.LogProvider, opts model.CloudProviderOptions, stages ...Stage) model.LogStreamer {
 	return &LogService{
 		provider:     provider,
 		opts:         opts,
+		stages:       stages,
 		buffer:       make([]model.LogEntry, 0, defaultBatchSize),
 		flushTimeout: defaultFlushTimeout,
 		batchTimer:   time.NewTimer(defaultFlushTimeout),
 		pollInterval: minPollInterval,
+		seen:         newRecentKeys(dedupeCapacity),
 	}
 }
 
@@ -107,6 +141,7 @@ func (s *LogService) addStatusMessage(ctx context.Context, ch chan<- model.LogEn
 		Timestamp: time.Now(),
 		Severity:  severity,
 		Message:   message,
+		LogName:   StatusLogName,
 	}
 	return s.addToBuffer(ctx, ch, statusMsg)
 }
@@ -121,8 +156,29 @@ func (s *LogService) processLogs(ctx context.Context, ch chan<- model.LogEntry,
 	return s.flushBuffer(ctx, ch)
 }
 
-// handleInitialLoad handles the initial loading of logs, trying different time windows
+// handleInitialLoad handles the initial loading of logs. With a time range the newest
+// page of the range is loaded; otherwise increasingly wide time windows are tried.
 func (s *LogService) handleInitialLoad(ctx context.Context, ch chan<- model.LogEntry, baseFilter string) (bool, error) {
+	if s.hasTimeRange() {
+		desc := FormatTimeRange(s.opts.Since, s.opts.Until)
+		if !s.addStatusMessage(ctx, ch, "INFO", fmt.Sprintf("Initial load: searching for logs from %s...", desc)) {
+			return false, nil
+		}
+
+		logs, err := s.fetchNewestPage(ctx, baseFilter)
+		if err != nil {
+			if !s.addStatusMessage(ctx, ch, "ERROR", fmt.Sprintf("Error fetching logs: %v", err)) {
+				return false, nil
+			}
+			return false, err
+		}
+		if len(logs) == 0 {
+			s.addStatusMessage(ctx, ch, "WARNING", "No logs found in this time range")
+			return false, nil
+		}
+		return s.processLogs(ctx, ch, logs), nil
+	}
+
 	timeWindows := s.getInitialTimeWindows()
 
 	for _, window := range timeWindows {
@@ -130,7 +186,12 @@ func (s *LogService) handleInitialLoad(ctx context.Context, ch chan<- model.LogE
 			return false, nil
 		}
 
-		logs, err := s.fetchLogs(ctx, baseFilter, window.Duration, true)
+		filter := baseFilter
+		if window.Duration > 0 {
+			filter = s.provider.BuildFilter(baseFilter, time.Now().Add(-window.Duration))
+		}
+
+		logs, err := s.fetchNewestPage(ctx, filter)
 		if err != nil {
 			continue
 		}
@@ -148,23 +209,66 @@ func (s *LogService) handleInitialLoad(ctx context.Context, ch chan<- model.LogE
 	return false, nil
 }
 
-// handleIncrementalUpdate handles fetching and processing new logs
+// hasTimeRange reports whether the stream was configured with a time range
+func (s *LogService) hasTimeRange() bool {
+	return !s.opts.Since.IsZero() || !s.opts.Until.IsZero()
+}
+
+// handleIncrementalUpdate fetches the logs written since the previous poll,
+// page by page until drained. The query overlaps the previous one by
+// dedupeOverlap; entries already sent are recognized by their insertId.
 func (s *LogService) handleIncrementalUpdate(ctx context.Context, ch chan<- model.LogEntry, baseFilter string) (bool, error) {
-	logs, err := s.fetchLogs(ctx, baseFilter, 0, false)
-	if err != nil {
-		if !s.addStatusMessage(ctx, ch, "ERROR", fmt.Sprintf("Error fetching logs: %v", err)) {
-			return false, nil
+	filter := s.provider.BuildFilter(baseFilter, s.lastTimestamp.Add(-dedupeOverlap))
+
+	found := false
+	token := ""
+	for {
+		page, err := s.provider.FetchPage(ctx, model.LogPageRequest{
+			Filter:    filter,
+			PageSize:  defaultBatchSize,
+			PageToken: token,
+		})
+		if err != nil {
+			if !s.addStatusMessage(ctx, ch, "ERROR", fmt.Sprintf("Error fetching logs: %v", err)) {
+				return found, nil
+			}
+			return found, err
+		}
+
+		if logs := s.newEntries(page.Entries); len(logs) > 0 {
+			found = true
+			if !s.processLogs(ctx, ch, logs) {
+				return found, nil
+			}
+		}
+
+		if token = page.NextPageToken; token == "" {
+			return found, nil
 		}
-		return false, err
 	}
+}
 
-	if len(logs) > 0 {
-		if !s.processLogs(ctx, ch, logs) {
-			return false, nil
+// newEntries returns the entries that were not sent yet and advances lastTimestamp
+func (s *LogService) newEntries(entries []model.LogEntry) []model.LogEntry {
+	var fresh []model.LogEntry
+	for _, entry := range entries {
+		if !s.seen.Add(entryKey(entry)) {
+			continue
+		}
+		fresh = append(fresh, entry)
+		if entry.Timestamp.After(s.lastTimestamp) {
+			s.lastTimestamp = entry.Timestamp
 		}
 	}
+	return fresh
+}
 
-	return len(logs) > 0, nil
+// Close releases the client held by the provider, if any
+func (s *LogService) Close() error {
+	if c, ok := s.provider.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
 
 // StreamLogs implements model.LogStreamer interface
@@ -179,7 +283,7 @@ func (s *LogService) StreamLogs(ctx context.Context) chan model.LogEntry {
 		defer flushTicker.Stop()
 		defer pollTicker.Stop()
 
-		baseFilter := s.provider.GetBaseFilter(s.opts.ServiceName)
+		baseFilter := s.baseFilter()
 
 		for {
 			select {
@@ -193,13 +297,21 @@ func (s *LogService) StreamLogs(ctx context.Context) chan model.LogEntry {
 			case <-pollTicker.C:
 				if !s.initialized {
 					foundLogs, err := s.handleInitialLoad(ctx, ch, baseFilter)
-					if err != nil || !foundLogs {
+					if !s.opts.Until.IsZero() {
+						// A closed time range has nothing to follow
+						s.flushBuffer(ctx, ch)
+						return
+					}
+					if (err != nil || !foundLogs) && s.opts.Since.IsZero() {
 						continue
 					}
 
 					s.initialized = true
 					if s.lastTimestamp.IsZero() {
 						s.lastTimestamp = time.Now().Add(-time.Second)
+						if !s.opts.Since.IsZero() {
+							s.lastTimestamp = s.opts.Since.Add(-time.Nanosecond)
+						}
 					}
 					continue
 				}
@@ -211,59 +323,108 @@ func (s *LogService) StreamLogs(ctx context.Context) chan model.LogEntry {
 		}
 	}()
 
-	return ch
+	return Pipe(ctx, ch, s.stages...)
 }
 
-// fetchLogs retrieves logs using the given filter and updates lastTimestamp
-func (s *LogService) fetchLogs(ctx context.Context, baseFilter string, duration time.Duration, isInitialLoad bool) ([]model.LogEntry, error) {
-	var filter string
-	if duration > 0 {
-		timestamp := time.Now().Add(-duration)
-		filter = s.provider.BuildFilter(baseFilter, timestamp)
-	} else if !isInitialLoad {
-		filter = s.provider.BuildFilter(baseFilter, s.lastTimestamp.Add(time.Nanosecond))
-	} else {
-		filter = baseFilter
-	}
+// baseFilter returns the provider base filter composed with the stream options
+func (s *LogService) baseFilter() string {
+	return QueryFilter(s.provider, s.opts)
+}
 
-	logs, err := s.provider.FetchLogs(ctx, filter, defaultBatchSize)
+// fetchNewestPage loads the newest page of logs matching filter, returned oldest first.
+// The filter and the token of the following page are kept for FetchOlder.
+func (s *LogService) fetchNewestPage(ctx context.Context, filter string) ([]model.LogEntry, error) {
+	page, err := s.provider.FetchPage(ctx, model.LogPageRequest{
+		Filter:      filter,
+		PageSize:    defaultBatchSize,
+		NewestFirst: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Process logs and update timestamp
-	var latestTimestamp time.Time
-	var filteredLogs []model.LogEntry
+	s.mu.Lock()
+	s.historyFilter = filter
+	s.olderToken = page.NextPageToken
+	s.unbounded = false
+	s.setOldest(page.Entries)
+	s.mu.Unlock()
 
-	// First collect all valid logs
-	for _, log := range logs {
-		// Track the latest timestamp seen
-		if latestTimestamp.IsZero() || log.Timestamp.After(latestTimestamp) {
-			latestTimestamp = log.Timestamp
-		}
+	return s.newEntries(reverseEntries(page.Entries)), nil
+}
 
-		// Skip if we've already seen this log (can happen due to timestamp granularity)
-		if !isInitialLoad && !log.Timestamp.After(s.lastTimestamp) {
-			continue
-		}
+// setOldest records the oldest of entries sorted newest first, and the keys
+// of the entries sharing its timestamp. It must be called with mu held.
+func (s *LogService) setOldest(entries []model.LogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	oldest := entries[len(entries)-1].Timestamp
+	if !oldest.Equal(s.oldest) {
+		s.oldest = oldest
+		s.oldestKeys = make(map[string]bool)
+	}
+	for i := len(entries) - 1; i >= 0 && entries[i].Timestamp.Equal(oldest); i-- {
+		s.oldestKeys[entryKey(entries[i])] = true
+	}
+}
 
-		filteredLogs = append(filteredLogs, log)
+// olderFilter returns the query of the entries up to before, without the
+// lower bound of the stream options
+func (s *LogService) olderFilter(before time.Time) string {
+	opts := s.opts
+	opts.Since = time.Time{}
+	opts.Until = before
+	return QueryFilter(s.provider, opts)
+}
+
+// FetchOlder implements model.LogPager
+func (s *LogService) FetchOlder(ctx context.Context) ([]model.LogEntry, bool, error) {
+	s.mu.Lock()
+	filter, token, unbounded := s.historyFilter, s.olderToken, s.unbounded
+	oldest := s.oldest
+	s.mu.Unlock()
+
+	if token == "" {
+		if unbounded || oldest.IsZero() {
+			return nil, false, nil
+		}
+		// The initial window is exhausted, keep going back in time
+		filter, unbounded = s.olderFilter(oldest), true
 	}
 
-	// Sort logs by timestamp in ascending order (oldest first)
-	sort.Slice(filteredLogs, func(i, j int) bool {
-		return filteredLogs[i].Timestamp.Before(filteredLogs[j].Timestamp)
+	page, err := s.provider.FetchPage(ctx, model.LogPageRequest{
+		Filter:      filter,
+		PageSize:    defaultBatchSize,
+		PageToken:   token,
+		NewestFirst: true,
 	})
-
-	// Take only the most recent logs up to maxLogEntries
-	if len(filteredLogs) > maxLogEntries {
-		filteredLogs = filteredLogs[:maxLogEntries]
+	if err != nil {
+		return nil, true, err
 	}
 
-	// Update the last timestamp if we found logs
-	if len(filteredLogs) > 0 && !latestTimestamp.IsZero() {
-		s.lastTimestamp = latestTimestamp
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var older []model.LogEntry
+	for _, entry := range page.Entries {
+		if entry.Timestamp.Equal(s.oldest) && s.oldestKeys[entryKey(entry)] {
+			continue
+		}
+		older = append(older, entry)
 	}
+	s.historyFilter = filter
+	s.olderToken = page.NextPageToken
+	s.unbounded = unbounded
+	s.setOldest(page.Entries)
 
-	return filteredLogs, nil
+	return reverseEntries(older), page.NextPageToken != "" || !unbounded, nil
+}
+
+// reverseEntries returns the entries in reverse order
+func reverseEntries(entries []model.LogEntry) []model.LogEntry {
+	reversed := make([]model.LogEntry, len(entries))
+	for i, entry := range entries {
+		reversed[len(entries)-1-i] = entry
+	}
+	return reversed
 }
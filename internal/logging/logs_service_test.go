@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/mock"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestLogServiceFetchOlder(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	provider := mock.NewLogProvider()
+	provider.Now = func() time.Time { return now }
+	provider.History = time.Hour
+
+	// The window holds 6 entries, one every 2 seconds
+	since := now.Add(-10 * time.Second)
+	s := NewLogService(provider, model.CloudProviderOptions{ServiceName: "api", Since: since, Until: now}).(*LogService)
+
+	ctx := context.Background()
+	entries, err := s.fetchNewestPage(ctx, s.baseFilter())
+	if err != nil {
+		t.Fatalf("fetchNewestPage() error = %v", err)
+	}
+	if len(entries) != 6 || !entries[0].Timestamp.Equal(since) {
+		t.Fatalf("got %d entries from %v, want 6 from %v", len(entries), entries[0].Timestamp, since)
+	}
+
+	// Older pages go past the start of the window, without repeating its
+	// oldest entry, and follow each other
+	oldest := since
+	for page := 0; page < 3; page++ {
+		older, more, err := s.FetchOlder(ctx)
+		if err != nil {
+			t.Fatalf("page %d: FetchOlder() error = %v", page, err)
+		}
+		if !more || len(older) == 0 {
+			t.Fatalf("page %d: got %d entries (more: %v), want older entries", page, len(older), more)
+		}
+		for i, entry := range older {
+			if i > 0 && !entry.Timestamp.After(older[i-1].Timestamp) {
+				t.Fatalf("page %d: entries not oldest first at %d", page, i)
+			}
+		}
+		if want := oldest.Add(-provider.Interval); !older[len(older)-1].Timestamp.Equal(want) {
+			t.Errorf("page %d: newest entry at %v, want %v", page, older[len(older)-1].Timestamp, want)
+		}
+		oldest = older[0].Timestamp
+	}
+}
@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"container/heap"
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// DefaultReorderWindow is how long merged entries are held back to be put in timestamp order
+const DefaultReorderWindow = time.Second
+
+// MergeSource is a named log stream combined by a MergedStreamer
+type MergeSource struct {
+	Name     string
+	Streamer model.LogStreamer
+}
+
+// MergedStreamer combines several log streams into one ordered by timestamp.
+// Entries are held back for the reorder window so that entries of slower
+// sources can still be put in order; entries arriving later than that are
+// sent as soon as they arrive. Each entry is tagged with the name of its source.
+type MergedStreamer struct {
+	sources []MergeSource
+	window  time.Duration
+}
+
+// Ensure MergedStreamer can be used wherever a single stream is expected
+var _ model.LogStreamer = (*MergedStreamer)(nil)
+
+// NewMergedStreamer creates a streamer merging the given sources
+func NewMergedStreamer(sources []MergeSource, window time.Duration) *MergedStreamer {
+	if window <= 0 {
+		window = DefaultReorderWindow
+	}
+	return &MergedStreamer{
+		sources: sources,
+		window:  window,
+	}
+}
+
+// Sources returns the names of the merged sources, in order
+func (m *MergedStreamer) Sources() []string {
+	names := make([]string, len(m.sources))
+	for i, src := range m.sources {
+		names[i] = src.Name
+	}
+	return names
+}
+
+// Close releases the clients held by the merged streamers, returning the
+// first error
+func (m *MergedStreamer) Close() error {
+	var first error
+	for _, src := range m.sources {
+		if c, ok := src.Streamer.(io.Closer); ok {
+			if err := c.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// StreamLogs implements model.LogStreamer interface
+func (m *MergedStreamer) StreamLogs(ctx context.Context) chan model.LogEntry {
+	in := make(chan model.LogEntry, defaultBatchSize)
+	out := make(chan model.LogEntry, defaultBatchSize)
+
+	var wg sync.WaitGroup
+	for _, src := range m.sources {
+		wg.Add(1)
+		go func(src MergeSource) {
+			defer wg.Done()
+			for entry := range src.Streamer.StreamLogs(ctx) {
+				entry.Source = src.Name
+				select {
+				case in <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(src)
+	}
+
+	go func() {
+		wg.Wait()
+		close(in)
+	}()
+
+	go m.merge(ctx, in, out)
+
+	return out
+}
+
+// merge reorders the entries received from all sources and sends them to out
+func (m *MergedStreamer) merge(ctx context.Context, in <-chan model.LogEntry, out chan<- model.LogEntry) {
+	defer close(out)
+
+	ticker := time.NewTicker(m.window / 4)
+	defer ticker.Stop()
+
+	var (
+		pending  entryHeap
+		released time.Time
+		seq      int64
+	)
+
+	// release sends the pending entries that are ready, or all of them when flushing
+	release := func(flush bool) bool {
+		cutoff := time.Now().Add(-m.window)
+		for pending.Len() > 0 {
+			top := pending[0]
+			if !flush && top.arrived.After(cutoff) && top.entry.Timestamp.After(released) {
+				break
+			}
+			heap.Pop(&pending)
+			if top.entry.Timestamp.After(released) {
+				released = top.entry.Timestamp
+			}
+			select {
+			case out <- top.entry:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry, ok := <-in:
+			if !ok {
+				release(true)
+				return
+			}
+			heap.Push(&pending, &pendingEntry{entry: entry, arrived: time.Now(), seq: seq})
+			seq++
+		case <-ticker.C:
+			if !release(false) {
+				return
+			}
+		}
+	}
+}
+
+// pendingEntry is an entry waiting in the reorder window
+type pendingEntry struct {
+	entry   model.LogEntry
+	arrived time.Time
+	// Arrival order, keeps entries with equal timestamps stable
+	seq int64
+}
+
+// entryHeap orders pending entries by timestamp, oldest first
+type entryHeap []*pendingEntry
+
+func (h entryHeap) Len() int { return len(h) }
+
+func (h entryHeap) Less(i, j int) bool {
+	if h[i].entry.Timestamp.Equal(h[j].entry.Timestamp) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].entry.Timestamp.Before(h[j].entry.Timestamp)
+}
+
+func (h entryHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *entryHeap) Push(x interface{}) { *h = append(*h, x.(*pendingEntry)) }
+
+func (h *entryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// chanStreamer streams the entries sent to its channel until it is closed
+type chanStreamer struct {
+	ch chan model.LogEntry
+}
+
+func newChanStreamer(entries ...model.LogEntry) *chanStreamer {
+	s := &chanStreamer{ch: make(chan model.LogEntry, len(entries)+1)}
+	for _, entry := range entries {
+		s.ch <- entry
+	}
+	return s
+}
+
+func (s *chanStreamer) StreamLogs(ctx context.Context) chan model.LogEntry {
+	return s.ch
+}
+
+// receive returns the next merged entry, failing when none arrives in time
+func receive(t *testing.T, out chan model.LogEntry) (model.LogEntry, bool) {
+	t.Helper()
+	select {
+	case entry, ok := <-out:
+		return entry, ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the merged stream")
+		return model.LogEntry{}, false
+	}
+}
+
+func TestMergedStreamerOrder(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int, msg string) model.LogEntry {
+		return model.LogEntry{Timestamp: base.Add(time.Duration(seconds) * time.Second), Message: msg}
+	}
+
+	a := newChanStreamer(at(1, "a1"), at(4, "a4"), at(5, "a5"))
+	b := newChanStreamer(at(2, "b2"), at(3, "b3"), at(6, "b6"))
+	close(a.ch)
+	close(b.ch)
+
+	m := NewMergedStreamer([]MergeSource{{Name: "a", Streamer: a}, {Name: "b", Streamer: b}}, 50*time.Millisecond)
+	out := m.StreamLogs(context.Background())
+
+	want := []struct{ msg, source string }{
+		{"a1", "a"}, {"b2", "b"}, {"b3", "b"}, {"a4", "a"}, {"a5", "a"}, {"b6", "b"},
+	}
+	for _, w := range want {
+		entry, ok := receive(t, out)
+		if !ok {
+			t.Fatalf("stream closed before %s", w.msg)
+		}
+		if entry.Message != w.msg || entry.Source != w.source {
+			t.Errorf("got %s from %q, want %s from %q", entry.Message, entry.Source, w.msg, w.source)
+		}
+	}
+	if _, ok := receive(t, out); ok {
+		t.Error("stream not closed after all sources closed")
+	}
+}
+
+func TestMergedStreamerSourceCloses(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	a := newChanStreamer(model.LogEntry{Timestamp: base, Message: "a"})
+	close(a.ch)
+	b := newChanStreamer()
+
+	m := NewMergedStreamer([]MergeSource{{Name: "a", Streamer: a}, {Name: "b", Streamer: b}}, 20*time.Millisecond)
+	out := m.StreamLogs(context.Background())
+
+	// The entries of a closed source are released while the others stream on
+	if entry, ok := receive(t, out); !ok || entry.Message != "a" {
+		t.Fatalf("got %q (open: %v), want the entry of the closed source", entry.Message, ok)
+	}
+
+	b.ch <- model.LogEntry{Timestamp: base.Add(time.Second), Message: "b"}
+	if entry, ok := receive(t, out); !ok || entry.Message != "b" {
+		t.Fatalf("got %q (open: %v), want the entry of the open source", entry.Message, ok)
+	}
+
+	close(b.ch)
+	if _, ok := receive(t, out); ok {
+		t.Error("stream not closed after the last source closed")
+	}
+}
+
+func TestMergedStreamerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMergedStreamer([]MergeSource{{Name: "a", Streamer: newChanStreamer()}}, 20*time.Millisecond)
+	out := m.StreamLogs(ctx)
+
+	cancel()
+	if _, ok := receive(t, out); ok {
+		t.Error("stream not closed after the context was cancelled")
+	}
+}
+
+// closingStreamer records whether it was closed
+type closingStreamer struct {
+	*chanStreamer
+	closed bool
+}
+
+func (s *closingStreamer) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestMergedStreamerClose(t *testing.T) {
+	a := &closingStreamer{chanStreamer: newChanStreamer()}
+	b := &closingStreamer{chanStreamer: newChanStreamer()}
+	m := NewMergedStreamer([]MergeSource{
+		{Name: "a", Streamer: a},
+		{Name: "plain", Streamer: newChanStreamer()},
+		{Name: "b", Streamer: b},
+	}, 0)
+
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !a.closed || !b.closed {
+		t.Errorf("closed a: %v, b: %v, want both sources closed", a.closed, b.closed)
+	}
+}
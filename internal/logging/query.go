@@ -0,0 +1,640 @@
+package logging
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Query is a parsed expression of the Logging query language, evaluated
+// locally against log entries. It supports a subset of the language:
+//
+//   - comparisons of a field with a value: = != > >= < <= : =~ !~
+//   - AND (or juxtaposition), OR, NOT and -, and parentheses; as in Cloud
+//     Logging, OR binds tighter than AND
+//   - bare words and strings, matching entries containing them in any field
+//   - the log_id("...") function
+//
+// Fields are named as in the LogEntry API: timestamp, severity, textPayload,
+// jsonPayload.*, protoPayload.*, insertId, logName, resource.type,
+// resource.labels.*, labels.*, httpRequest.*, trace, spanId and sourceLocation.*.
+type Query struct {
+	root queryNode
+}
+
+// ParseQuery parses a query. An empty query matches every entry.
+func ParseQuery(text string) (*Query, error) {
+	tokens, err := lexQuery(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return &Query{}, nil
+	}
+	root, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return &Query{root: root}, nil
+}
+
+// Match reports whether an entry matches the query
+func (q *Query) Match(entry model.LogEntry) bool {
+	return q.root == nil || q.root.match(entry)
+}
+
+// Token kinds of the query language
+const (
+	tokEOF = iota
+	tokWord
+	tokString
+	tokOperator
+	tokLParen
+	tokRParen
+)
+
+type queryToken struct {
+	kind int
+	text string
+	pos  int
+	// Segments of a field path; quoted segments may contain dots
+	path []string
+	// Whether the token is directly followed by an opening parenthesis
+	call bool
+}
+
+// queryOperators are the comparison operators, longest first
+var queryOperators = []string{">=", "<=", "!=", "=~", "!~", "=", ">", "<", ":"}
+
+// lexQuery splits a query into tokens
+func lexQuery(text string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			s, n, err := lexString(runes[i:])
+			if err != nil {
+				return nil, fmt.Errorf("position %d: %v", i, err)
+			}
+			tokens = append(tokens, queryToken{kind: tokString, text: s, pos: i})
+			i += n
+		case strings.ContainsRune("=!<>:", r):
+			op := ""
+			for _, candidate := range queryOperators {
+				if strings.HasPrefix(string(runes[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("position %d: unexpected %q", i, r)
+			}
+			tokens = append(tokens, queryToken{kind: tokOperator, text: op, pos: i})
+			i += len(op)
+		default:
+			tok, n, err := lexWord(runes[i:])
+			if err != nil {
+				return nil, fmt.Errorf("position %d: %v", i, err)
+			}
+			tok.pos = i
+			i += n
+			tok.call = i < len(runes) && runes[i] == '('
+			tokens = append(tokens, tok)
+		}
+	}
+	return append(tokens, queryToken{kind: tokEOF, pos: len(runes)}), nil
+}
+
+// lexString reads a double-quoted string and returns its value and length
+func lexString(runes []rune) (string, int, error) {
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case '"':
+			s, err := strconv.Unquote(string(runes[:i+1]))
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid string %s", string(runes[:i+1]))
+			}
+			return s, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// lexWord reads a bare word, which may be a field path with quoted segments
+// such as labels."k8s-pod/app"
+func lexWord(runes []rune) (queryToken, int, error) {
+	tok := queryToken{kind: tokWord}
+	segment := ""
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		if unicode.IsSpace(r) || strings.ContainsRune(`()=!<>:`, r) {
+			break
+		}
+		switch {
+		case r == '"' && strings.HasSuffix(string(runes[:i]), "."):
+			s, n, err := lexString(runes[i:])
+			if err != nil {
+				return tok, 0, err
+			}
+			segment += s
+			i += n
+			continue
+		case r == '"':
+			return tok, 0, fmt.Errorf("unexpected quote")
+		case r == '.':
+			tok.path = append(tok.path, segment)
+			segment = ""
+		default:
+			segment += string(r)
+		}
+		i++
+	}
+	tok.path = append(tok.path, segment)
+	tok.text = string(runes[:i])
+	return tok, i, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// isKeyword reports whether a token is the given keyword
+func isKeyword(tok queryToken, keyword string) bool {
+	return tok.kind == tokWord && tok.text == keyword
+}
+
+// parseAnd parses terms joined by AND or juxtaposition
+func (p *queryParser) parseAnd() (queryNode, error) {
+	left, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || tok.kind == tokRParen {
+			return left, nil
+		}
+		if isKeyword(tok, "AND") {
+			p.next()
+		}
+		right, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+// parseOr parses terms joined by OR
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for isKeyword(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+// parseUnary parses a negated or plain term
+func (p *queryParser) parseUnary() (queryNode, error) {
+	tok := p.peek()
+	switch {
+	case isKeyword(tok, "NOT"):
+		p.next()
+		term, err := p.parseUnary()
+		return notNode{term}, err
+	case tok.kind == tokWord && strings.HasPrefix(tok.text, "-") && len(tok.text) > 1:
+		// -term negates the term following the dash
+		p.tokens[p.pos] = trimDash(tok)
+		term, err := p.parseUnary()
+		return notNode{term}, err
+	}
+	return p.parsePrimary()
+}
+
+// trimDash removes the leading dash of a negated word
+func trimDash(tok queryToken) queryToken {
+	tok.text = tok.text[1:]
+	tok.path[0] = strings.TrimPrefix(tok.path[0], "-")
+	tok.pos++
+	return tok
+}
+
+// parsePrimary parses a parenthesized expression, function, comparison or bare term
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("missing ) for ( at position %d", tok.pos)
+		}
+		return node, nil
+	case tokString:
+		return textNode{strings.ToLower(tok.text)}, nil
+	case tokWord:
+		if tok.call {
+			return p.parseCall(tok)
+		}
+		if op := p.peek(); op.kind == tokOperator {
+			p.next()
+			return p.parseComparison(tok, op.text)
+		}
+		if isKeyword(tok, "AND") || isKeyword(tok, "OR") {
+			return nil, fmt.Errorf("unexpected %s at position %d", tok.text, tok.pos)
+		}
+		return textNode{strings.ToLower(tok.text)}, nil
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// parseCall parses a function call such as log_id("run.googleapis.com/requests")
+func (p *queryParser) parseCall(name queryToken) (queryNode, error) {
+	p.next() // (
+	arg := p.next()
+	if arg.kind != tokString && arg.kind != tokWord {
+		return nil, fmt.Errorf("%s expects an argument at position %d", name.text, arg.pos)
+	}
+	if closing := p.next(); closing.kind != tokRParen {
+		return nil, fmt.Errorf("missing ) after the argument of %s", name.text)
+	}
+	switch name.text {
+	case "log_id":
+		return logIDNode{arg.text}, nil
+	}
+	return nil, fmt.Errorf("unsupported function %s", name.text)
+}
+
+// parseComparison parses the value compared with a field
+func (p *queryParser) parseComparison(field queryToken, op string) (queryNode, error) {
+	value := p.next()
+	if value.kind != tokString && value.kind != tokWord {
+		return nil, fmt.Errorf("missing value after %s%s", field.text, op)
+	}
+	node := compareNode{path: field.path, op: op, value: value.text}
+	if op == "=~" || op == "!~" {
+		re, err := regexp.Compile(value.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %v", value.text, err)
+		}
+		node.re = re
+	}
+	return node, nil
+}
+
+// queryNode is a node of a parsed query
+type queryNode interface {
+	match(entry model.LogEntry) bool
+}
+
+type andNode struct{ left, right queryNode }
+
+func (n andNode) match(entry model.LogEntry) bool {
+	return n.left.match(entry) && n.right.match(entry)
+}
+
+type orNode struct{ left, right queryNode }
+
+func (n orNode) match(entry model.LogEntry) bool {
+	return n.left.match(entry) || n.right.match(entry)
+}
+
+type notNode struct{ term queryNode }
+
+func (n notNode) match(entry model.LogEntry) bool {
+	return !n.term.match(entry)
+}
+
+// textNode matches entries containing a text in any field, ignoring case
+type textNode struct{ text string }
+
+func (n textNode) match(entry model.LogEntry) bool {
+	found := false
+	walkEntryStrings(entry, func(s string) bool {
+		found = strings.Contains(strings.ToLower(s), n.text)
+		return !found
+	})
+	return found
+}
+
+// logIDNode matches the entries of a log
+type logIDNode struct{ id string }
+
+func (n logIDNode) match(entry model.LogEntry) bool {
+	return LogIDOf(entry) == n.id
+}
+
+// compareNode compares a field of the entry with a value
+type compareNode struct {
+	path  []string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (n compareNode) match(entry model.LogEntry) bool {
+	field, ok := entryField(entry, n.path)
+	if !ok {
+		return false
+	}
+
+	switch n.op {
+	case ":":
+		if n.value == "*" {
+			return true
+		}
+		return strings.Contains(strings.ToLower(valueText(field)), strings.ToLower(n.value))
+	case "=~":
+		return n.re.MatchString(valueText(field))
+	case "!~":
+		return !n.re.MatchString(valueText(field))
+	}
+
+	cmp, ok := compareValue(field, n.path, n.value)
+	if !ok {
+		return false
+	}
+	switch n.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// compareValue compares a field value with a query value, returning -1, 0 or 1.
+// It reports false when the values cannot be compared.
+func compareValue(field interface{}, path []string, value string) (int, bool) {
+	if len(path) == 1 && path[0] == "severity" {
+		return compareInts(int64(model.SeverityRank(valueText(field))), int64(model.SeverityRank(value))), true
+	}
+
+	switch v := field.(type) {
+	case time.Time:
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return 0, false
+		}
+		return compareInts(v.UnixNano(), t.UnixNano()), true
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, false
+		}
+		return compareInts(int64(v), int64(d)), true
+	case float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, false
+		}
+		switch {
+		case v < f:
+			return -1, true
+		case v > f:
+			return 1, true
+		}
+		return 0, true
+	}
+	return strings.Compare(valueText(field), value), true
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// valueText returns the text of a field value
+func valueText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// entryField returns the value of the field of an entry at path
+func entryField(entry model.LogEntry, path []string) (interface{}, bool) {
+	switch path[0] {
+	case "timestamp":
+		return entry.Timestamp, len(path) == 1
+	case "severity":
+		severity := entry.Severity
+		if severity == "" {
+			severity = model.SeverityDefault
+		}
+		return severity, len(path) == 1
+	case "textPayload":
+		return entry.Message, len(path) == 1 && entry.Payload == nil && entry.Message != ""
+	case "jsonPayload", "protoPayload":
+		_, proto := entry.Payload["@type"]
+		if entry.Payload == nil || proto != (path[0] == "protoPayload") {
+			return nil, false
+		}
+		return lookupPath(entry.Payload, path[1:])
+	case "insertId":
+		return entry.InsertID, len(path) == 1 && entry.InsertID != ""
+	case "logName":
+		return entry.LogName, len(path) == 1 && entry.LogName != ""
+	case "trace":
+		return entry.Trace, len(path) == 1 && entry.Trace != ""
+	case "spanId":
+		return entry.SpanID, len(path) == 1 && entry.SpanID != ""
+	case "labels":
+		return labelField(entry.Labels, path[1:])
+	case "resource":
+		switch {
+		case len(path) == 2 && path[1] == "type":
+			return entry.ResourceType, entry.ResourceType != ""
+		case len(path) >= 2 && path[1] == "labels":
+			return labelField(entry.ResourceLabels, path[2:])
+		}
+	case "httpRequest":
+		if entry.HTTPRequest != nil && len(path) == 2 {
+			return httpRequestField(entry.HTTPRequest, path[1])
+		}
+	case "sourceLocation":
+		if loc := entry.SourceLocation; loc != nil && len(path) == 2 {
+			switch path[1] {
+			case "file":
+				return loc.File, loc.File != ""
+			case "line":
+				return float64(loc.Line), loc.Line != 0
+			case "function":
+				return loc.Function, loc.Function != ""
+			}
+		}
+	}
+	return nil, false
+}
+
+// labelField returns a label, or the label map when key is empty
+func labelField(labels map[string]string, key []string) (interface{}, bool) {
+	switch len(key) {
+	case 0:
+		return strings.Join(sortedLabelValues(labels), " "), len(labels) > 0
+	case 1:
+		value, ok := labels[key[0]]
+		return value, ok
+	}
+	return nil, false
+}
+
+// sortedLabelValues returns the "key=value" pairs of labels, sorted so that
+// whole-map matches do not depend on map order
+func sortedLabelValues(labels map[string]string) []string {
+	values := make([]string, 0, len(labels))
+	for key, value := range labels {
+		values = append(values, key+"="+value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// httpRequestField returns a field of an HTTP request by its API name
+func httpRequestField(req *model.HTTPRequest, name string) (interface{}, bool) {
+	switch name {
+	case "requestMethod":
+		return req.Method, req.Method != ""
+	case "requestUrl":
+		return req.URL, req.URL != ""
+	case "status":
+		return float64(req.Status), req.Status != 0
+	case "requestSize":
+		return float64(req.RequestSize), true
+	case "responseSize":
+		return float64(req.ResponseSize), true
+	case "userAgent":
+		return req.UserAgent, req.UserAgent != ""
+	case "remoteIp":
+		return req.RemoteIP, req.RemoteIP != ""
+	case "protocol":
+		return req.Protocol, req.Protocol != ""
+	case "latency":
+		return req.Latency, true
+	}
+	return nil, false
+}
+
+// lookupPath returns the value at path in a payload
+func lookupPath(value interface{}, path []string) (interface{}, bool) {
+	for _, key := range path {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// walkEntryStrings calls fn with the text of every field of an entry until it returns false
+func walkEntryStrings(entry model.LogEntry, fn func(s string) bool) {
+	texts := []string{entry.Message, entry.Severity, entry.InsertID, entry.LogName, entry.ResourceType, entry.Trace, entry.SpanID}
+	for _, labels := range []map[string]string{entry.ResourceLabels, entry.Labels} {
+		for key, value := range labels {
+			texts = append(texts, key, value)
+		}
+	}
+	if req := entry.HTTPRequest; req != nil {
+		texts = append(texts, req.Method, req.URL, req.UserAgent, req.RemoteIP, req.Protocol)
+	}
+	for _, text := range texts {
+		if text != "" && !fn(text) {
+			return
+		}
+	}
+	walkPayloadStrings(entry.Payload, fn)
+}
+
+// walkPayloadStrings calls fn with every string and number of a payload until it returns false
+func walkPayloadStrings(value interface{}, fn func(s string) bool) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			if !walkPayloadStrings(item, fn) {
+				return false
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if !walkPayloadStrings(item, fn) {
+				return false
+			}
+		}
+	case string:
+		return fn(v)
+	case float64, bool:
+		return fn(valueText(v))
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package logging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestQueryMatch(t *testing.T) {
+	entry := model.LogEntry{
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Severity:  "ERROR",
+		Message:   "payment failed for order 42",
+		Payload: map[string]interface{}{
+			"message": "payment failed for order 42",
+			"order":   map[string]interface{}{"id": 42.0, "currency": "EUR"},
+		},
+		LogName:        "projects/p/logs/run.googleapis.com%2Fstderr",
+		ResourceType:   "cloud_run_revision",
+		ResourceLabels: map[string]string{"service_name": "checkout", "location": "europe-west1"},
+		Labels:         map[string]string{"k8s-pod/app": "checkout"},
+		HTTPRequest:    &model.HTTPRequest{Method: "POST", Status: 502, Latency: 1500 * time.Millisecond},
+		Trace:          "projects/p/traces/abc",
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{``, true},
+		{`resource.type="cloud_run_revision" resource.labels.service_name="checkout"`, true},
+		{`resource.labels.service_name="cart"`, false},
+		{`severity>=WARNING`, true},
+		{`severity<ERROR`, false},
+		{`severity=error`, true},
+		{`timestamp >= "2024-05-01T11:00:00Z" AND timestamp<="2024-05-01T12:00:00Z"`, true},
+		{`timestamp>"2024-05-01T12:00:00Z"`, false},
+		{`jsonPayload.order.id=42`, true},
+		{`jsonPayload.order.currency!="EUR"`, false},
+		{`jsonPayload.order.total:*`, false},
+		{`protoPayload.order.id=42`, false},
+		{`textPayload:payment`, false},
+		{`labels."k8s-pod/app"="checkout"`, true},
+		{`resource.labels=~"^location=europe-west1 service_name=checkout$"`, true},
+		{`httpRequest.status>=500 httpRequest.latency>"1s"`, true},
+		{`httpRequest.requestMethod="GET"`, false},
+		{`log_id("run.googleapis.com/stderr")`, true},
+		{`log_id("run.googleapis.com/stdout")`, false},
+		{`trace="projects/p/traces/abc"`, true},
+		{`PAYMENT`, true},
+		{`"order 43"`, false},
+		{`-payment`, false},
+		{`NOT refund`, true},
+		{`refund OR payment`, true},
+		{`refund OR payment AND severity=INFO`, false},
+		{`(refund OR payment) AND NOT (severity<ERROR)`, true},
+		{`jsonPayload.message=~"order \\d+$"`, true},
+		{`jsonPayload.message!~"^payment"`, false},
+	}
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query)
+		if err != nil {
+			t.Errorf("ParseQuery(%q) failed: %v", tt.query, err)
+			continue
+		}
+		if got := q.Match(entry); got != tt.want {
+			t.Errorf("ParseQuery(%q).Match() = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		`(severity>=ERROR`,
+		`severity>=`,
+		`message="unterminated`,
+		`jsonPayload.x=~"("`,
+		`unknown_fn("x")`,
+		`a AND`,
+	} {
+		if _, err := ParseQuery(query); err == nil {
+			t.Errorf("ParseQuery(%q) succeeded, want an error", query)
+		}
+	}
+}
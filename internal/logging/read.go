@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// readPageSize is the number of entries fetched per page by ReadLogs
+const readPageSize = 1000
+
+// ReadLogs calls fn with every entry matching opts, oldest first, fetching
+// them page by page. It stops at the first error returned by fn.
+func ReadLogs(ctx context.Context, provider model.LogProvider, opts model.CloudProviderOptions, fn func(entry model.LogEntry) error) error {
+	filter := QueryFilter(provider, opts)
+	token := ""
+	for {
+		page, err := provider.FetchPage(ctx, model.LogPageRequest{
+			Filter:    filter,
+			PageSize:  readPageSize,
+			PageToken: token,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs: %v", err)
+		}
+		for _, entry := range page.Entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if token = page.NextPageToken; token == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logging
+
+import "github.com/lpmourato/c9s/internal/model"
+
+// DefaultRingBufferSize is the number of entries kept by a log view unless configured otherwise
+const DefaultRingBufferSize = 50000
+
+// RingBuffer is a bounded buffer of log entries. When it is full, pushing a
+// new entry evicts the oldest one. Entries are addressed by a sequence number
+// that stays stable while the buffer wraps, so callers can keep references to
+// entries across pushes and check whether they have been evicted.
+type RingBuffer struct {
+	entries  []model.LogEntry
+	head     int   // index of the oldest entry
+	size     int   // number of stored entries
+	firstSeq int64 // sequence number of the oldest entry
+}
+
+// NewRingBuffer creates a ring buffer holding up to capacity entries
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity <= 0 {
+		capacity = DefaultRingBufferSize
+	}
+	return &RingBuffer{
+		entries: make([]model.LogEntry, capacity),
+	}
+}
+
+// Capacity returns the maximum number of entries kept
+func (b *RingBuffer) Capacity() int {
+	return len(b.entries)
+}
+
+// Len returns the number of stored entries
+func (b *RingBuffer) Len() int {
+	return b.size
+}
+
+// FirstSeq returns the sequence number of the oldest stored entry
+func (b *RingBuffer) FirstSeq() int64 {
+	return b.firstSeq
+}
+
+// NextSeq returns the sequence number the next pushed entry will get
+func (b *RingBuffer) NextSeq() int64 {
+	return b.firstSeq + int64(b.size)
+}
+
+// Push appends an entry, evicting the oldest one when full, and returns its sequence number
+func (b *RingBuffer) Push(entry model.LogEntry) int64 {
+	capacity := len(b.entries)
+	if b.size == capacity {
+		b.entries[b.head] = entry
+		b.head = (b.head + 1) % capacity
+		b.firstSeq++
+	} else {
+		b.entries[(b.head+b.size)%capacity] = entry
+		b.size++
+	}
+	return b.NextSeq() - 1
+}
+
+// Prepend inserts older entries, given oldest first, in front of the stored ones.
+// Only the newest entries that fit in the free capacity are kept, since older
+// entries must never evict newer ones. It returns the number of entries kept.
+func (b *RingBuffer) Prepend(entries []model.LogEntry) int {
+	capacity := len(b.entries)
+	n := len(entries)
+	if free := capacity - b.size; n > free {
+		n = free
+	}
+	for i := len(entries) - 1; i >= len(entries)-n; i-- {
+		b.head = (b.head - 1 + capacity) % capacity
+		b.entries[b.head] = entries[i]
+		b.size++
+		b.firstSeq--
+	}
+	return n
+}
+
+// Get returns the entry with the given sequence number, if it is still stored
+func (b *RingBuffer) Get(seq int64) (model.LogEntry, bool) {
+	offset := seq - b.firstSeq
+	if offset < 0 || offset >= int64(b.size) {
+		return model.LogEntry{}, false
+	}
+	return b.entries[(b.head+int(offset))%len(b.entries)], true
+}
+
+// Entries returns a copy of the stored entries, oldest first
+func (b *RingBuffer) Entries() []model.LogEntry {
+	result := make([]model.LogEntry, b.size)
+	for i := range result {
+		result[i] = b.entries[(b.head+i)%len(b.entries)]
+	}
+	return result
+}
+
+// Clear removes all entries
+func (b *RingBuffer) Clear() {
+	for i := range b.entries {
+		b.entries[i] = model.LogEntry{}
+	}
+	b.head = 0
+	b.size = 0
+	b.firstSeq = 0
+}
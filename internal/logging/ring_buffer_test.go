@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// bufferOp pushes its messages one by one, or prepends them at once
+type bufferOp struct {
+	prepend bool
+	msgs    []string
+}
+
+func push(msgs ...string) bufferOp    { return bufferOp{msgs: msgs} }
+func prepend(msgs ...string) bufferOp { return bufferOp{prepend: true, msgs: msgs} }
+
+func TestRingBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		ops      []bufferOp
+		want     []string
+		firstSeq int64
+		// kept is the number of entries kept by the prepends
+		kept int
+		// get maps sequence numbers to their messages, "" for evicted ones
+		get map[int64]string
+	}{
+		{
+			name:     "push below capacity",
+			capacity: 3,
+			ops:      []bufferOp{push("a", "b")},
+			want:     []string{"a", "b"},
+			firstSeq: 0,
+			get:      map[int64]string{0: "a", 1: "b", 2: ""},
+		},
+		{
+			name:     "push wraps around",
+			capacity: 3,
+			ops:      []bufferOp{push("a", "b", "c", "d", "e")},
+			want:     []string{"c", "d", "e"},
+			firstSeq: 2,
+			get:      map[int64]string{0: "", 1: "", 2: "c", 4: "e", 5: ""},
+		},
+		{
+			name:     "prepend fills the free capacity",
+			capacity: 4,
+			ops:      []bufferOp{push("c", "d"), prepend("a", "b")},
+			want:     []string{"a", "b", "c", "d"},
+			firstSeq: -2,
+			kept:     2,
+			get:      map[int64]string{-2: "a", -1: "b", 0: "c", 1: "d"},
+		},
+		{
+			name:     "prepend keeps the newest entries that fit",
+			capacity: 4,
+			ops:      []bufferOp{push("d", "e"), prepend("a", "b", "c")},
+			want:     []string{"b", "c", "d", "e"},
+			firstSeq: -2,
+			kept:     2,
+			get:      map[int64]string{-3: "", -2: "b", 1: "e"},
+		},
+		{
+			name:     "prepend to a full buffer",
+			capacity: 2,
+			ops:      []bufferOp{push("b", "c"), prepend("a")},
+			want:     []string{"b", "c"},
+			firstSeq: 0,
+			kept:     0,
+			get:      map[int64]string{-1: "", 0: "b"},
+		},
+		{
+			name:     "push after prepend evicts the prepended entries",
+			capacity: 3,
+			ops:      []bufferOp{push("c"), prepend("a", "b"), push("d", "e")},
+			want:     []string{"c", "d", "e"},
+			firstSeq: 0,
+			kept:     2,
+			get:      map[int64]string{-2: "", -1: "", 0: "c", 2: "e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewRingBuffer(tt.capacity)
+			kept := 0
+			for _, op := range tt.ops {
+				var entries []model.LogEntry
+				for _, msg := range op.msgs {
+					entries = append(entries, model.LogEntry{Message: msg})
+				}
+				if op.prepend {
+					kept += b.Prepend(entries)
+					continue
+				}
+				for _, entry := range entries {
+					if seq := b.Push(entry); seq != b.NextSeq()-1 {
+						t.Fatalf("Push(%q) = %d, want %d", entry.Message, seq, b.NextSeq()-1)
+					}
+				}
+			}
+
+			var got []string
+			for _, entry := range b.Entries() {
+				got = append(got, entry.Message)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Entries() = %v, want %v", got, tt.want)
+			}
+			if b.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", b.Len(), len(tt.want))
+			}
+			if b.FirstSeq() != tt.firstSeq {
+				t.Errorf("FirstSeq() = %d, want %d", b.FirstSeq(), tt.firstSeq)
+			}
+			if kept != tt.kept {
+				t.Errorf("Prepend() kept %d entries, want %d", kept, tt.kept)
+			}
+			for seq, want := range tt.get {
+				entry, ok := b.Get(seq)
+				if ok != (want != "") || entry.Message != want {
+					t.Errorf("Get(%d) = %q, %v, want %q", seq, entry.Message, ok, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Stage is a step of a log pipeline, run on the entries of a stream before
+// they reach its consumer, e.g. to evaluate alert rules
+type Stage interface {
+	// Process forwards the entries of in to out until in is closed or ctx is done
+	Process(ctx context.Context, in <-chan model.LogEntry, out chan<- model.LogEntry)
+}
+
+// Pipe runs the entries of ch through stages, in order, and returns the
+// channel of the last stage. Each channel is closed after the one before it.
+func Pipe(ctx context.Context, ch chan model.LogEntry, stages ...Stage) chan model.LogEntry {
+	for _, stage := range stages {
+		in, out := ch, make(chan model.LogEntry, defaultBatchSize)
+		go func(stage Stage) {
+			defer close(out)
+			stage.Process(ctx, in, out)
+		}(stage)
+		ch = out
+	}
+	return ch
+}
+
+// stagedStreamer runs the entries of a streamer through stages
+type stagedStreamer struct {
+	streamer model.LogStreamer
+	stages   []Stage
+}
+
+// WithStages returns a streamer running the entries of streamer through
+// stages. LogService takes its stages directly, which keeps it a model.LogPager.
+func WithStages(streamer model.LogStreamer, stages ...Stage) model.LogStreamer {
+	if len(stages) == 0 {
+		return streamer
+	}
+	return &stagedStreamer{streamer: streamer, stages: stages}
+}
+
+// StreamLogs implements model.LogStreamer
+func (s *stagedStreamer) StreamLogs(ctx context.Context) chan model.LogEntry {
+	return Pipe(ctx, s.streamer.StreamLogs(ctx), s.stages...)
+}
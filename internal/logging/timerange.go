@@ -0,0 +1,217 @@
+package logging
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// absoluteLayouts are the absolute time formats accepted by ParseTime, interpreted in local time
+var absoluteLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// clockLayouts are the time-of-day formats accepted by ParseTime
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+}
+
+// ParseTime parses an absolute or relative point in time.
+//
+// Accepted forms are RFC 3339 timestamps, "2006-01-02[ 15:04[:05]]",
+// a time of day ("14:02", today), "today"/"yesterday" optionally followed by
+// a time of day, "now", and durations relative to now such as "90m", "1h30m" or "2d".
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+
+	lower := strings.ToLower(value)
+	if lower == "now" {
+		return now, nil
+	}
+	if d, err := parseRelative(lower); err == nil {
+		return now.Add(-d), nil
+	}
+
+	day := now
+	clock := lower
+	switch {
+	case strings.HasPrefix(lower, "yesterday"):
+		day = now.AddDate(0, 0, -1)
+		clock = strings.TrimSpace(strings.TrimPrefix(lower, "yesterday"))
+	case strings.HasPrefix(lower, "today"):
+		clock = strings.TrimSpace(strings.TrimPrefix(lower, "today"))
+	}
+	if t, ok := atClock(day, clock); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// rangeSeparators separate the bounds of a time range
+var rangeSeparators = []string{"..", "–", " to "}
+
+// ParseTimeRange parses a range written as "<since>..<until>", "<since> to <until>"
+// or just "<since>" for an open-ended range. A "today" or "yesterday" before or
+// after the whole range applies to both bounds, so "yesterday 14:02..14:20" and
+// "14:02–14:20 yesterday" are the same range. When until is only a time of day
+// it is taken on the day of since, or on the next day when it is earlier than
+// since, so "23:00..01:00" crosses midnight.
+func ParseTimeRange(value string, now time.Time) (since, until time.Time, err error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+
+	sinceText, untilText, ok := splitRange(value)
+	if !ok {
+		since, err = ParseTime(value, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		return since, time.Time{}, nil
+	}
+
+	day, rest := cutDayWord(value, now)
+	if rest != value {
+		sinceText, untilText, _ = splitRange(rest)
+	}
+
+	sinceText = strings.TrimSpace(sinceText)
+	if t, ok := atClock(day, sinceText); ok && sinceText != "" {
+		since = t
+	} else if since, err = ParseTime(sinceText, now); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	untilText = strings.TrimSpace(untilText)
+	if untilText == "" {
+		return since, time.Time{}, nil
+	}
+
+	if t, ok := atClock(since, untilText); ok {
+		until = t
+		if until.Before(since) {
+			until = until.AddDate(0, 0, 1)
+		}
+	} else if until, err = ParseTime(untilText, now); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if err := checkRange(since, until); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return since, until, nil
+}
+
+// ParseTimeBounds parses the bounds of a range given separately, such as the
+// --since and --until flags. Either may be empty for an open bound.
+func ParseTimeBounds(since, until string, now time.Time) (sinceTime, untilTime time.Time, err error) {
+	if strings.TrimSpace(since) != "" {
+		if sinceTime, err = ParseTime(since, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if strings.TrimSpace(until) != "" {
+		if untilTime, err = ParseTime(until, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if err := checkRange(sinceTime, untilTime); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return sinceTime, untilTime, nil
+}
+
+// checkRange fails when both bounds are set and the end precedes the start
+func checkRange(since, until time.Time) error {
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return fmt.Errorf("end of range %s is before its start %s",
+			until.Format(time.RFC3339), since.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// splitRange splits a range at its first separator
+func splitRange(value string) (since, until string, ok bool) {
+	for _, sep := range rangeSeparators {
+		if i := strings.Index(value, sep); i >= 0 {
+			return value[:i], value[i+len(sep):], true
+		}
+	}
+	return value, "", false
+}
+
+// cutDayWord removes a leading or trailing "today" or "yesterday" from a
+// range and returns the day it names, or now and the range unchanged
+func cutDayWord(value string, now time.Time) (time.Time, string) {
+	days := map[string]time.Time{"today": now, "yesterday": now.AddDate(0, 0, -1)}
+	lower := strings.ToLower(value)
+	for word, day := range days {
+		if strings.HasPrefix(lower, word+" ") {
+			return day, strings.TrimSpace(value[len(word):])
+		}
+		if strings.HasSuffix(lower, " "+word) {
+			return day, strings.TrimSpace(value[:len(value)-len(word)])
+		}
+	}
+	return now, value
+}
+
+// FormatTimeRange renders a time range for display; zero bounds are shown as open
+func FormatTimeRange(since, until time.Time) string {
+	const layout = "2006-01-02 15:04:05"
+	switch {
+	case since.IsZero() && until.IsZero():
+		return "live"
+	case until.IsZero():
+		return since.Local().Format(layout) + " .. now"
+	case since.IsZero():
+		return ".. " + until.Local().Format(layout)
+	}
+	return since.Local().Format(layout) + " .. " + until.Local().Format(layout)
+}
+
+// parseRelative parses a duration, also accepting a number of days such as "2d"
+func parseRelative(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// atClock returns the given time of day on the day of base
+func atClock(base time.Time, clock string) (time.Time, bool) {
+	if clock == "" {
+		y, m, d := base.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, base.Location()), true
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, clock); err == nil {
+			y, m, d := base.Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, base.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-05-01T14:02:00Z", time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC)},
+		{"2024-05-01 14:02", time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC)},
+		{"2024-05-01", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"now", now},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"2d", now.Add(-48 * time.Hour)},
+		{"14:02", time.Date(2024, 5, 2, 14, 2, 0, 0, time.UTC)},
+		{"today", time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+		{"yesterday 14:02:30", time.Date(2024, 5, 1, 14, 2, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTime(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTime(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "later", "25:00", "yesterday noon"} {
+		if _, err := ParseTime(value, now); err == nil {
+			t.Errorf("ParseTime(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)
+	day := func(d, h, m int) time.Time { return time.Date(2024, 5, d, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		value        string
+		since, until time.Time
+	}{
+		{"", time.Time{}, time.Time{}},
+		{"1h", now.Add(-time.Hour), time.Time{}},
+		{"yesterday 14:02", day(1, 14, 2), time.Time{}},
+		{"yesterday 14:02..14:20", day(1, 14, 2), day(1, 14, 20)},
+		{"yesterday 14:02–14:20", day(1, 14, 2), day(1, 14, 20)},
+		{"14:02–14:20 yesterday", day(1, 14, 2), day(1, 14, 20)},
+		{"14:02 to 14:20 Yesterday", day(1, 14, 2), day(1, 14, 20)},
+		{"today 09:00..10:00", day(2, 9, 0), day(2, 10, 0)},
+		{"09:00..10:00", day(2, 9, 0), day(2, 10, 0)},
+		{"23:00..01:00", day(2, 23, 0), day(3, 1, 0)},
+		{"yesterday 23:00..01:00", day(1, 23, 0), day(2, 1, 0)},
+		{"yesterday 14:02..now", day(1, 14, 2), now},
+		{"2024-05-01 08:00..2024-05-01 09:30", day(1, 8, 0), day(1, 9, 30)},
+		{"2h..1h", now.Add(-2 * time.Hour), now.Add(-time.Hour)},
+		{"10:00..", day(2, 10, 0), time.Time{}},
+	}
+	for _, tt := range tests {
+		since, until, err := ParseTimeRange(tt.value, now)
+		if err != nil {
+			t.Errorf("ParseTimeRange(%q) error = %v", tt.value, err)
+			continue
+		}
+		if !since.Equal(tt.since) || !until.Equal(tt.until) {
+			t.Errorf("ParseTimeRange(%q) = %v..%v, want %v..%v", tt.value, since, until, tt.since, tt.until)
+		}
+	}
+
+	for _, value := range []string{"1h..2h", "14:02..later", "soon..14:00"} {
+		if _, _, err := ParseTimeRange(value, now); err == nil {
+			t.Errorf("ParseTimeRange(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestParseTimeBounds(t *testing.T) {
+	now := time.Date(2024, 5, 2, 12, 30, 0, 0, time.UTC)
+	tests := []struct {
+		since, until string
+		wantSince    time.Time
+		wantUntil    time.Time
+		wantErr      bool
+	}{
+		{"", "", time.Time{}, time.Time{}, false},
+		{"1h", "", now.Add(-time.Hour), time.Time{}, false},
+		{"", "yesterday 14:02", time.Time{}, time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC), false},
+		{"2024-05-01 14:02", "30m", time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC), now.Add(-30 * time.Minute), false},
+		{"30m", "1h", time.Time{}, time.Time{}, true},
+		{"soon", "", time.Time{}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		since, until, err := ParseTimeBounds(tt.since, tt.until, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimeBounds(%q, %q) error = %v, wantErr %v", tt.since, tt.until, err, tt.wantErr)
+			continue
+		}
+		if !since.Equal(tt.wantSince) || !until.Equal(tt.wantUntil) {
+			t.Errorf("ParseTimeBounds(%q, %q) = %v, %v, want %v, %v", tt.since, tt.until, since, until, tt.wantSince, tt.wantUntil)
+		}
+	}
+}
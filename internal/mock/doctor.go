@@ -0,0 +1,55 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Diagnostics is a healthy environment for the doctor checks: every check
+// passes and each region has the mocked services of that region
+type Diagnostics struct {
+	services []model.Service
+}
+
+// NewDiagnostics creates the environment of the mocked services
+func NewDiagnostics(services []model.Service) *Diagnostics {
+	return &Diagnostics{services: services}
+}
+
+// Credentials implements doctor.Environment
+func (d *Diagnostics) Credentials(ctx context.Context) (string, error) {
+	return "mock-user@example.com", nil
+}
+
+// Project implements doctor.Environment
+func (d *Diagnostics) Project(ctx context.Context, projectID string) (string, error) {
+	return "Mock Project", nil
+}
+
+// ProjectOptional implements doctor.ProjectOptional: the mocked services
+// belong to no project
+func (d *Diagnostics) ProjectOptional() bool {
+	return true
+}
+
+// ServiceEnabled implements doctor.Environment
+func (d *Diagnostics) ServiceEnabled(ctx context.Context, projectID, service string) (bool, error) {
+	return true, nil
+}
+
+// TestPermissions implements doctor.Environment
+func (d *Diagnostics) TestPermissions(ctx context.Context, projectID string, permissions []string) ([]string, error) {
+	return permissions, nil
+}
+
+// ListServices implements doctor.Environment
+func (d *Diagnostics) ListServices(ctx context.Context, projectID, region string) (int, error) {
+	count := 0
+	for _, svc := range d.services {
+		if svc.GetRegion() == region {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,234 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+const (
+	// DefaultLogInterval is the time between two mock log entries of a service
+	DefaultLogInterval = 2 * time.Second
+	// DefaultLogHistory is how far back mock log entries exist
+	DefaultLogHistory = 24 * time.Hour
+	// instanceStartEvery is the number of entries between two starts of a
+	// new instance, logged to the system log and followed by its startup probe
+	instanceStartEvery = 1000
+)
+
+var (
+	serviceClause  = regexp.MustCompile(`resource\.labels\.service_name="([^"]*)"`)
+	regionClause   = regexp.MustCompile(`resource\.labels\.location="([^"]*)"`)
+	severityClause = regexp.MustCompile(`severity\s*>=\s*(\w+)`)
+	timeClause     = regexp.MustCompile(`timestamp\s*(>=|>|<=|<)\s*"([^"]+)"`)
+	logIDClause    = regexp.MustCompile(`log_id\("([^"]+)"\)`)
+)
+
+// Ensure LogProvider can back a log service
+var _ model.LogProvider = (*LogProvider)(nil)
+
+// LogProvider implements model.LogProvider with generated logs, so the log
+// stack can run without GCP. Every service writes one entry per Interval;
+// the entries only depend on the service and their time, so pages and polls
+// of the same range always return the same entries.
+type LogProvider struct {
+	Interval time.Duration
+	History  time.Duration
+	// Now returns the current time; entries are never newer than it
+	Now func() time.Time
+}
+
+// NewLogProvider creates a mock log provider following the current time
+func NewLogProvider() *LogProvider {
+	return &LogProvider{
+		Interval: DefaultLogInterval,
+		History:  DefaultLogHistory,
+		Now:      time.Now,
+	}
+}
+
+// GetBaseFilter implements model.LogProvider using the Cloud Run filter format
+func (p *LogProvider) GetBaseFilter(serviceName string) string {
+	return fmt.Sprintf(`resource.type="cloud_run_revision" resource.labels.service_name="%s"`, serviceName)
+}
+
+// BuildFilter implements model.LogProvider
+func (p *LogProvider) BuildFilter(baseFilter string, timestamp time.Time) string {
+	if timestamp.IsZero() {
+		return baseFilter
+	}
+	return fmt.Sprintf(`%s AND timestamp >= "%s"`, baseFilter, timestamp.Format(time.RFC3339Nano))
+}
+
+// FetchLogs implements model.LogProvider, returning the oldest entries matching filter
+func (p *LogProvider) FetchLogs(ctx context.Context, filter string, pageSize int) ([]model.LogEntry, error) {
+	page, err := p.FetchPage(ctx, model.LogPageRequest{Filter: filter, PageSize: pageSize})
+	return page.Entries, err
+}
+
+// FetchPage implements model.LogProvider. Only the service, location, log,
+// severity and timestamp restrictions of the filter are applied.
+func (p *LogProvider) FetchPage(ctx context.Context, req model.LogPageRequest) (model.LogPage, error) {
+	if err := ctx.Err(); err != nil {
+		return model.LogPage{}, err
+	}
+	q, err := p.parseFilter(req.Filter)
+	if err != nil {
+		return model.LogPage{}, err
+	}
+
+	// Entries are indexed by their time divided by the interval
+	interval := int64(p.Interval)
+	first := (q.from.UnixNano() + interval - 1) / interval
+	last := q.to.UnixNano() / interval
+
+	step := int64(1)
+	index := first
+	if req.NewestFirst {
+		step, index = -1, last
+	}
+	if req.PageToken != "" {
+		if index, err = strconv.ParseInt(req.PageToken, 10, 64); err != nil {
+			return model.LogPage{}, fmt.Errorf("invalid page token %q: %v", req.PageToken, err)
+		}
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	var page model.LogPage
+	for ; index >= first && index <= last; index += step {
+		if len(page.Entries) == pageSize {
+			page.NextPageToken = strconv.FormatInt(index, 10)
+			break
+		}
+		entry := p.entry(q.service, q.region, index)
+		if model.SeverityAtLeast(entry.Severity, q.minSeverity) && q.matchesLog(entry) {
+			page.Entries = append(page.Entries, entry)
+		}
+	}
+	return page, nil
+}
+
+// mockQuery holds the restrictions of a filter understood by the mock provider
+type mockQuery struct {
+	service     string
+	region      string
+	minSeverity string
+	// Log names the entries must be written to; empty means all logs
+	logNames []string
+	// Inclusive time bounds
+	from time.Time
+	to   time.Time
+}
+
+// parseFilter extracts the restrictions of a filter, bounded by the available history
+func (p *LogProvider) parseFilter(filter string) (mockQuery, error) {
+	now := p.Now()
+	q := mockQuery{
+		service: "mock-service",
+		region:  "us-central1",
+		from:    now.Add(-p.History),
+		to:      now,
+	}
+	if m := serviceClause.FindStringSubmatch(filter); m != nil {
+		q.service = m[1]
+	}
+	if m := regionClause.FindStringSubmatch(filter); m != nil {
+		q.region = m[1]
+	}
+	if m := severityClause.FindStringSubmatch(filter); m != nil {
+		q.minSeverity = m[1]
+	}
+	for _, m := range logIDClause.FindAllStringSubmatch(filter, -1) {
+		q.logNames = append(q.logNames, logName(m[1]))
+	}
+
+	for _, m := range timeClause.FindAllStringSubmatch(filter, -1) {
+		t, err := time.Parse(time.RFC3339Nano, m[2])
+		if err != nil {
+			return q, fmt.Errorf("invalid timestamp %q: %v", m[2], err)
+		}
+		switch m[1] {
+		case ">":
+			t = t.Add(time.Nanosecond)
+			fallthrough
+		case ">=":
+			if t.After(q.from) {
+				q.from = t
+			}
+		case "<":
+			t = t.Add(-time.Nanosecond)
+			fallthrough
+		case "<=":
+			if t.Before(q.to) {
+				q.to = t
+			}
+		}
+	}
+	return q, nil
+}
+
+// matchesLog reports whether an entry is written to one of the logs of the query
+func (q mockQuery) matchesLog(entry model.LogEntry) bool {
+	if len(q.logNames) == 0 {
+		return true
+	}
+	for _, name := range q.logNames {
+		if entry.LogName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// logName returns the mock log name of a log ID
+func logName(logID string) string {
+	return "projects/mock/logs/" + url.PathEscape(logID)
+}
+
+// entry generates the entry of a service at an index. Every
+// instanceStartEvery entries, a new instance starts and passes its startup
+// probe in the next entry.
+func (p *LogProvider) entry(service, region string, index int64) model.LogEntry {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s/%d", service, index)
+	n := int(h.Sum32() % 1000)
+
+	sample := sampleLogs[n%len(sampleLogs)]
+	entry := model.LogEntry{
+		Timestamp:    time.Unix(0, index*int64(p.Interval)).UTC(),
+		Severity:     sample.severity,
+		Message:      sample.messages[n/len(sampleLogs)%len(sample.messages)],
+		InsertID:     fmt.Sprintf("%s-%d", service, index),
+		LogName:      logName("run.googleapis.com/stdout"),
+		ResourceType: "cloud_run_revision",
+		ResourceLabels: map[string]string{
+			"service_name":  service,
+			"location":      region,
+			"revision_name": service + "-00001",
+		},
+	}
+
+	switch index % instanceStartEvery {
+	case 0:
+		entry.Severity = "INFO"
+		entry.Message = "Starting new instance. Reason: AUTOSCALING - Instance started due to configured scaling factors (e.g. CPU utilization, request throughput, etc.) or no existing capacity for current traffic."
+	case 1:
+		entry.Severity = "INFO"
+		entry.Message = `Default STARTUP TCP probe succeeded after 1 attempt for container "app" on port 8080.`
+	default:
+		return entry
+	}
+	entry.LogName = logName("run.googleapis.com/varlog/system")
+	entry.Labels = map[string]string{"instanceId": fmt.Sprintf("%s-%08x", service, index/instanceStartEvery)}
+	return entry
+}
@@ -13,6 +13,32 @@ type LogProvider interface {
 	BuildFilter(baseFilter string, timestamp time.Time) string
 	// GetBaseFilter returns the provider-specific base filter format
 	GetBaseFilter(serviceName string) string
+	// FetchPage retrieves a single page of logs, resuming from the request page token
+	FetchPage(ctx context.Context, req LogPageRequest) (LogPage, error)
+}
+
+// LogPageRequest describes a single page of logs to fetch
+type LogPageRequest struct {
+	Filter   string
+	PageSize int
+	// Token returned with the previous page; empty for the first page
+	PageToken string
+	// Return the newest entries first instead of the oldest
+	NewestFirst bool
+}
+
+// LogPage is a page of logs in the requested order
+type LogPage struct {
+	Entries []LogEntry
+	// Token of the following page; empty when there are no more pages
+	NextPageToken string
+}
+
+// LogPager is implemented by log streamers that can load older logs on demand
+type LogPager interface {
+	// FetchOlder returns the page of entries preceding the oldest entry streamed so far,
+	// oldest first, and whether even older entries are available
+	FetchOlder(ctx context.Context) ([]LogEntry, bool, error)
 }
 
 // TimeWindow represents a time window for fetching logs
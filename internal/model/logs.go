@@ -8,11 +8,53 @@ import (
 // LogEntry represents a single log message with its metadata
 type LogEntry struct {
 	// Timestamp when the log was created
-	Timestamp time.Time
+	Timestamp time.Time `json:"timestamp"`
 	// Severity level of the log (e.g., INFO, WARNING, ERROR)
-	Severity string
+	Severity string `json:"severity"`
 	// Actual log message content
-	Message string
+	Message string `json:"message"`
+
+	// Unique identifier of the entry within its log
+	InsertID string `json:"insertId,omitempty"`
+	// Full name of the log the entry was written to
+	LogName string `json:"logName,omitempty"`
+	// Monitored resource that produced the entry
+	ResourceType   string            `json:"resourceType,omitempty"`
+	ResourceLabels map[string]string `json:"resourceLabels,omitempty"`
+	// User-defined labels of the entry
+	Labels map[string]string `json:"labels,omitempty"`
+	// Structured JSON or proto payload, nil for text entries
+	Payload map[string]interface{} `json:"payload,omitempty"`
+	// HTTP request the entry is about, if any
+	HTTPRequest *HTTPRequest `json:"httpRequest,omitempty"`
+	// Trace and span the entry belongs to
+	Trace  string `json:"trace,omitempty"`
+	SpanID string `json:"spanId,omitempty"`
+	// Source code location that wrote the entry
+	SourceLocation *SourceLocation `json:"sourceLocation,omitempty"`
+
+	// Name of the stream the entry came from when several streams are merged
+	Source string `json:"source,omitempty"`
+}
+
+// HTTPRequest holds the HTTP request information attached to a log entry
+type HTTPRequest struct {
+	Method       string        `json:"method,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	Status       int           `json:"status,omitempty"`
+	RequestSize  int64         `json:"requestSize,omitempty"`
+	ResponseSize int64         `json:"responseSize,omitempty"`
+	UserAgent    string        `json:"userAgent,omitempty"`
+	RemoteIP     string        `json:"remoteIp,omitempty"`
+	Protocol     string        `json:"protocol,omitempty"`
+	Latency      time.Duration `json:"latency,omitempty"`
+}
+
+// SourceLocation identifies the code that wrote a log entry
+type SourceLocation struct {
+	File     string `json:"file,omitempty"`
+	Line     int64  `json:"line,omitempty"`
+	Function string `json:"function,omitempty"`
 }
 
 // LogStreamer provides an interface for streaming logs
@@ -30,4 +72,18 @@ type CloudProviderOptions struct {
 	ServiceName string
 	// Region where the service is deployed
 	Region string
+	// Revision to restrict the logs to; empty means all revisions
+	Revision string
+	// Minimum severity pushed into the provider filter; empty means all severities
+	MinSeverity string
+	// Additional user-typed expression in the provider query language
+	Query string
+	// Start of the time range to load; zero loads the most recent logs
+	Since time.Time
+	// End of the time range to load; zero keeps following new logs
+	Until time.Time
+	// Trace to restrict the logs to, as "projects/<project>/traces/<id>"
+	Trace string
+	// Logs to restrict the logs to, e.g. run.googleapis.com/requests; empty means all logs
+	LogIDs []string
 }
@@ -21,3 +21,7 @@ func (p *MockLogProvider) BuildFilter(baseFilter string, timestamp time.Time) st
 func (p *MockLogProvider) FetchLogs(ctx context.Context, filter string, batchSize int) ([]LogEntry, error) {
 	return nil, nil
 }
+
+func (p *MockLogProvider) FetchPage(ctx context.Context, req LogPageRequest) (LogPage, error) {
+	return LogPage{}, nil
+}
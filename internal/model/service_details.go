@@ -5,129 +5,129 @@ import "time"
 // ServiceDetails contains detailed information about a Cloud Run service
 type ServiceDetails struct {
 	// Basic Service Information
-	Name           string
-	Region         string
-	URL            string
-	LastUpdated    time.Time
-	Ready          bool
-	ActiveRevision string
-	Traffic        []RevisionTraffic
+	Name           string            `json:"name" yaml:"name"`
+	Region         string            `json:"region" yaml:"region"`
+	URL            string            `json:"url" yaml:"url"`
+	LastUpdated    time.Time         `json:"lastUpdated" yaml:"lastUpdated"`
+	Ready          bool              `json:"ready" yaml:"ready"`
+	ActiveRevision string            `json:"activeRevision" yaml:"activeRevision"`
+	Traffic        []RevisionTraffic `json:"traffic" yaml:"traffic"`
 
 	// Service Metadata
-	UID          string
-	Generation   int64
-	CreationTime time.Time
-	Creator      string
-	LastModifier string
-	Labels       map[string]string
-	Annotations  map[string]string
+	UID          string            `json:"uid" yaml:"uid"`
+	Generation   int64             `json:"generation" yaml:"generation"`
+	CreationTime time.Time         `json:"creationTime" yaml:"creationTime"`
+	Creator      string            `json:"creator" yaml:"creator"`
+	LastModifier string            `json:"lastModifier" yaml:"lastModifier"`
+	Labels       map[string]string `json:"labels" yaml:"labels"`
+	Annotations  map[string]string `json:"annotations" yaml:"annotations"`
 
 	// Container Configuration
-	ContainerImage       string
-	ImageDigest          string
-	CPU                  string
-	Memory               string
-	Port                 int32
-	ContainerName        string
-	ContainerConcurrency int32
-	TimeoutSeconds       int32
+	ContainerImage       string `json:"containerImage" yaml:"containerImage"`
+	ImageDigest          string `json:"imageDigest" yaml:"imageDigest"`
+	CPU                  string `json:"cpu" yaml:"cpu"`
+	Memory               string `json:"memory" yaml:"memory"`
+	Port                 int32  `json:"port" yaml:"port"`
+	ContainerName        string `json:"containerName" yaml:"containerName"`
+	ContainerConcurrency int32  `json:"containerConcurrency" yaml:"containerConcurrency"`
+	TimeoutSeconds       int32  `json:"timeoutSeconds" yaml:"timeoutSeconds"`
 
 	// Environment & Secrets
-	EnvVars map[string]string
-	Secrets []SecretMount
-	Volumes []VolumeMount
+	EnvVars map[string]string `json:"envVars" yaml:"envVars"`
+	Secrets []SecretMount     `json:"secrets" yaml:"secrets"`
+	Volumes []VolumeMount     `json:"volumes" yaml:"volumes"`
 
 	// Scaling Configuration
-	MinInstances int32
-	MaxInstances int32
+	MinInstances int32 `json:"minInstances" yaml:"minInstances"`
+	MaxInstances int32 `json:"maxInstances" yaml:"maxInstances"`
 
 	// Network & Security
-	ServiceAccount  string
-	VPCConnector    string
-	VPCEgress       string
-	IngressSettings string
-	ExecutionEnv    string
-	CPUThrottling   bool
+	ServiceAccount  string `json:"serviceAccount" yaml:"serviceAccount"`
+	VPCConnector    string `json:"vpcConnector" yaml:"vpcConnector"`
+	VPCEgress       string `json:"vpcEgress" yaml:"vpcEgress"`
+	IngressSettings string `json:"ingressSettings" yaml:"ingressSettings"`
+	ExecutionEnv    string `json:"executionEnv" yaml:"executionEnv"`
+	CPUThrottling   bool   `json:"cpuThrottling" yaml:"cpuThrottling"`
 
 	// Health Checks
-	LivenessProbe  *HealthProbe
-	ReadinessProbe *HealthProbe
-	StartupProbe   *HealthProbe
+	LivenessProbe  *HealthProbe `json:"livenessProbe" yaml:"livenessProbe"`
+	ReadinessProbe *HealthProbe `json:"readinessProbe" yaml:"readinessProbe"`
+	StartupProbe   *HealthProbe `json:"startupProbe" yaml:"startupProbe"`
 
 	// Additional Metadata
-	LaunchStage string
-	OperationID string
-	LogURL      string
-	SelfLink    string
+	LaunchStage string `json:"launchStage" yaml:"launchStage"`
+	OperationID string `json:"operationId" yaml:"operationId"`
+	LogURL      string `json:"logUrl" yaml:"logUrl"`
+	SelfLink    string `json:"selfLink" yaml:"selfLink"`
 
 	// Revision Details
-	LatestRevision       string
-	LatestReadyRevision  string
-	RevisionCreationTime time.Time
-	ContainerStatuses    []ContainerStatus
-	RevisionConditions   []Condition
+	LatestRevision       string            `json:"latestRevision" yaml:"latestRevision"`
+	LatestReadyRevision  string            `json:"latestReadyRevision" yaml:"latestReadyRevision"`
+	RevisionCreationTime time.Time         `json:"revisionCreationTime" yaml:"revisionCreationTime"`
+	ContainerStatuses    []ContainerStatus `json:"containerStatuses" yaml:"containerStatuses"`
+	RevisionConditions   []Condition       `json:"revisionConditions" yaml:"revisionConditions"`
 }
 
 // RevisionTraffic represents traffic allocation for a revision
 type RevisionTraffic struct {
-	RevisionName string
-	Percent      int32
-	Tag          string
-	Latest       bool
+	RevisionName string `json:"revisionName" yaml:"revisionName"`
+	Percent      int32  `json:"percent" yaml:"percent"`
+	Tag          string `json:"tag" yaml:"tag"`
+	Latest       bool   `json:"latest" yaml:"latest"`
 }
 
 // SecretMount represents a mounted secret
 type SecretMount struct {
-	Name      string
-	MountPath string
-	Items     []SecretItem
+	Name      string       `json:"name" yaml:"name"`
+	MountPath string       `json:"mountPath" yaml:"mountPath"`
+	Items     []SecretItem `json:"items" yaml:"items"`
 }
 
 // SecretItem represents an item in a secret
 type SecretItem struct {
-	Key  string
-	Path string
+	Key  string `json:"key" yaml:"key"`
+	Path string `json:"path" yaml:"path"`
 }
 
 // VolumeMount represents a mounted volume
 type VolumeMount struct {
-	Name       string
-	MountPath  string
-	ReadOnly   bool
-	VolumeType string // secret, configMap, etc.
+	Name       string `json:"name" yaml:"name"`
+	MountPath  string `json:"mountPath" yaml:"mountPath"`
+	ReadOnly   bool   `json:"readOnly" yaml:"readOnly"`
+	VolumeType string `json:"volumeType" yaml:"volumeType"` // secret, configMap, etc.
 }
 
 // HealthProbe represents health check configuration
 type HealthProbe struct {
-	HTTPGet             *HTTPGetAction
-	InitialDelaySeconds int32
-	PeriodSeconds       int32
-	TimeoutSeconds      int32
-	FailureThreshold    int32
-	SuccessThreshold    int32
+	HTTPGet             *HTTPGetAction `json:"httpGet" yaml:"httpGet"`
+	InitialDelaySeconds int32          `json:"initialDelaySeconds" yaml:"initialDelaySeconds"`
+	PeriodSeconds       int32          `json:"periodSeconds" yaml:"periodSeconds"`
+	TimeoutSeconds      int32          `json:"timeoutSeconds" yaml:"timeoutSeconds"`
+	FailureThreshold    int32          `json:"failureThreshold" yaml:"failureThreshold"`
+	SuccessThreshold    int32          `json:"successThreshold" yaml:"successThreshold"`
 }
 
 // HTTPGetAction represents HTTP GET health check
 type HTTPGetAction struct {
-	Path    string
-	Port    int32
-	Scheme  string
-	Headers map[string]string
+	Path    string            `json:"path" yaml:"path"`
+	Port    int32             `json:"port" yaml:"port"`
+	Scheme  string            `json:"scheme" yaml:"scheme"`
+	Headers map[string]string `json:"headers" yaml:"headers"`
 }
 
 // ContainerStatus represents the status of a container
 type ContainerStatus struct {
-	Name         string
-	ImageDigest  string
-	Ready        bool
-	RestartCount int32
+	Name         string `json:"name" yaml:"name"`
+	ImageDigest  string `json:"imageDigest" yaml:"imageDigest"`
+	Ready        bool   `json:"ready" yaml:"ready"`
+	RestartCount int32  `json:"restartCount" yaml:"restartCount"`
 }
 
 // Condition represents a service or revision condition
 type Condition struct {
-	Type               string
-	Status             string
-	LastTransitionTime time.Time
-	Reason             string
-	Message            string
+	Type               string    `json:"type" yaml:"type"`
+	Status             string    `json:"status" yaml:"status"`
+	LastTransitionTime time.Time `json:"lastTransitionTime" yaml:"lastTransitionTime"`
+	Reason             string    `json:"reason" yaml:"reason"`
+	Message            string    `json:"message" yaml:"message"`
 }
@@ -0,0 +1,69 @@
+package model
+
+import "strings"
+
+// Severity levels as reported by Cloud Logging
+const (
+	SeverityDefault   = "DEFAULT"
+	SeverityTrace     = "TRACE"
+	SeverityDebug     = "DEBUG"
+	SeverityInfo      = "INFO"
+	SeverityNotice    = "NOTICE"
+	SeverityWarning   = "WARNING"
+	SeverityError     = "ERROR"
+	SeverityCritical  = "CRITICAL"
+	SeverityAlert     = "ALERT"
+	SeverityEmergency = "EMERGENCY"
+)
+
+var severityRanks = map[string]int{
+	SeverityDefault:   0,
+	SeverityTrace:     50,
+	SeverityDebug:     100,
+	SeverityInfo:      200,
+	SeverityNotice:    300,
+	SeverityWarning:   400,
+	"WARN":            400,
+	SeverityError:     500,
+	SeverityCritical:  600,
+	"FATAL":           600,
+	SeverityAlert:     700,
+	SeverityEmergency: 800,
+}
+
+// severityAliases map severity names used by other loggers to the closest
+// LogSeverity name accepted by Cloud Logging filters
+var severityAliases = map[string]string{
+	"WARN":        SeverityWarning,
+	"FATAL":       SeverityCritical,
+	SeverityTrace: SeverityDebug,
+}
+
+// NormalizeSeverity returns the LogSeverity name of a severity, accepting
+// any case and the WARN, FATAL and TRACE aliases. It reports false for
+// unknown severities.
+func NormalizeSeverity(severity string) (string, bool) {
+	severity = strings.ToUpper(strings.TrimSpace(severity))
+	if alias, ok := severityAliases[severity]; ok {
+		return alias, true
+	}
+	if _, ok := severityRanks[severity]; ok {
+		return severity, true
+	}
+	return "", false
+}
+
+// SeverityRank returns the relative importance of a severity level.
+// Unknown or empty severities rank the same as DEFAULT.
+func SeverityRank(severity string) int {
+	return severityRanks[strings.ToUpper(severity)]
+}
+
+// SeverityAtLeast reports whether severity is at or above the given minimum.
+// An empty minimum matches every severity.
+func SeverityAtLeast(severity, minimum string) bool {
+	if minimum == "" {
+		return true
+	}
+	return SeverityRank(severity) >= SeverityRank(minimum)
+}
@@ -0,0 +1,432 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// SecretMask replaces the values of sensitive environment variables
+const SecretMask = "********"
+
+// sensitiveKeys are fragments of the names of sensitive environment variables
+var sensitiveKeys = []string{
+	"PASSWORD", "SECRET", "KEY", "TOKEN", "CREDENTIAL",
+	"AUTH", "PRIVATE", "CERT", "SIGNATURE",
+}
+
+// IsSensitive reports whether an environment variable likely holds a secret
+func IsSensitive(key string) bool {
+	key = strings.ToUpper(key)
+	for _, k := range sensitiveKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecrets returns a copy of details whose sensitive environment
+// variables are masked
+func MaskSecrets(details *model.ServiceDetails) *model.ServiceDetails {
+	masked := *details
+	if details.EnvVars != nil {
+		masked.EnvVars = make(map[string]string, len(details.EnvVars))
+		for k, v := range details.EnvVars {
+			if IsSensitive(k) {
+				v = SecretMask
+			}
+			masked.EnvVars[k] = v
+		}
+	}
+	return &masked
+}
+
+// Style holds the markup of the parts of rendered details. Each markup is
+// written before its part; Reset ends a section title.
+type Style struct {
+	Section string
+	Reset   string
+	Key     string
+	Value   string
+	Dim     string
+	// Markup of good and bad states, e.g. Ready and Not Ready
+	Good string
+	Bad  string
+	// Markup of revision tags
+	Tag string
+	// Escape protects values from being read as markup
+	Escape func(string) string
+}
+
+// PlainStyle renders details as plain text
+var PlainStyle = Style{Escape: func(s string) string { return s }}
+
+// DetailsWriter renders the details of a service in sections: service
+// information and metadata, container, scaling, network, health checks,
+// traffic, revision, environment, secrets and volumes, conditions and links
+type DetailsWriter struct {
+	Style Style
+	// Location of the rendered times
+	Location *time.Location
+	// After is called after each section is written, e.g. to add sections
+	// between them
+	After func(section string)
+
+	w       io.Writer
+	err     error
+	current string
+}
+
+// NewDetailsWriter creates a writer of details in a style
+func NewDetailsWriter(style Style, loc *time.Location) *DetailsWriter {
+	return &DetailsWriter{Style: style, Location: loc}
+}
+
+// Write renders details to w; sensitive environment variables must already
+// be masked with MaskSecrets
+func (dw *DetailsWriter) Write(w io.Writer, details *model.ServiceDetails) error {
+	dw.w, dw.err = w, nil
+	s := dw.Style
+
+	// Service Information
+	dw.section("Service Information")
+	dw.keyValue("Name", details.Name)
+	dw.keyValue("Region", details.Region)
+	dw.keyValue("URL", details.URL)
+	dw.keyValueRaw("Status", dw.readyText(details.Ready))
+	if details.ActiveRevision != "" {
+		dw.keyValue("Active Revision", details.ActiveRevision)
+	}
+	dw.keyValue("Creation Time", dw.time(details.CreationTime))
+	dw.keyValue("Last Updated", dw.time(details.LastUpdated))
+	if details.Creator != "" {
+		dw.keyValue("Creator", details.Creator)
+	}
+	if details.LastModifier != "" {
+		dw.keyValue("Last Modifier", details.LastModifier)
+	}
+	dw.end()
+
+	// Service Metadata
+	if details.UID != "" || details.Generation > 0 || details.LaunchStage != "" || len(details.Labels) > 0 || len(details.Annotations) > 0 {
+		dw.section("Service Metadata")
+		if details.UID != "" {
+			dw.keyValue("UID", details.UID)
+		}
+		if details.Generation > 0 {
+			dw.keyValue("Generation", fmt.Sprintf("%d", details.Generation))
+		}
+		if details.LaunchStage != "" {
+			dw.keyValue("Launch Stage", details.LaunchStage)
+		}
+		dw.keyValues("Labels", details.Labels)
+		dw.keyValues("Annotations", details.Annotations)
+		dw.end()
+	}
+
+	// Container Configuration
+	dw.section("Container Configuration")
+	dw.keyValue("Image", details.ContainerImage)
+	if details.ImageDigest != "" {
+		dw.keyValue("Image Digest", details.ImageDigest)
+	}
+	if details.ContainerName != "" {
+		dw.keyValue("Container Name", details.ContainerName)
+	}
+	dw.keyValue("CPU", details.CPU)
+	dw.keyValue("Memory", details.Memory)
+	dw.keyValue("Port", fmt.Sprintf("%d", details.Port))
+	if details.ContainerConcurrency > 0 {
+		dw.keyValue("Concurrency", fmt.Sprintf("%d", details.ContainerConcurrency))
+	}
+	if details.TimeoutSeconds > 0 {
+		dw.keyValue("Timeout", fmt.Sprintf("%ds", details.TimeoutSeconds))
+	}
+	dw.end()
+
+	// Scaling Configuration
+	dw.section("Scaling Configuration")
+	dw.keyValue("Minimum Instances", fmt.Sprintf("%d", details.MinInstances))
+	dw.keyValue("Maximum Instances", fmt.Sprintf("%d", details.MaxInstances))
+	dw.end()
+
+	// Network & Security
+	if details.ServiceAccount != "" || details.VPCConnector != "" || details.IngressSettings != "" {
+		dw.section("Network & Security")
+		if details.ServiceAccount != "" {
+			dw.keyValue("Service Account", details.ServiceAccount)
+		}
+		if details.VPCConnector != "" {
+			dw.keyValue("VPC Connector", details.VPCConnector)
+		}
+		if details.VPCEgress != "" {
+			dw.keyValue("VPC Egress", details.VPCEgress)
+		}
+		if details.IngressSettings != "" {
+			dw.keyValue("Ingress", details.IngressSettings)
+		}
+		if details.ExecutionEnv != "" {
+			dw.keyValue("Execution Environment", details.ExecutionEnv)
+		}
+		dw.keyValueRaw("CPU Throttling", dw.enabledText(details.CPUThrottling))
+		dw.end()
+	}
+
+	// Health Checks
+	if details.LivenessProbe != nil || details.ReadinessProbe != nil || details.StartupProbe != nil {
+		dw.section("Health Checks")
+		dw.probe("Liveness Probe", details.LivenessProbe)
+		dw.probe("Readiness Probe", details.ReadinessProbe)
+		dw.probe("Startup Probe", details.StartupProbe)
+		dw.end()
+	}
+
+	// Traffic Configuration
+	if len(details.Traffic) > 0 {
+		dw.section("Traffic Configuration")
+		for _, t := range details.Traffic {
+			status := ""
+			if t.Latest {
+				status = " " + s.Good + "(latest)"
+			}
+			if t.Tag != "" {
+				status += " " + s.Tag + "(" + s.Escape(t.Tag) + ")"
+			}
+			dw.keyValueRaw("Revision "+t.RevisionName, fmt.Sprintf("%d%%%s", t.Percent, status))
+		}
+		dw.end()
+	}
+
+	// Revision Information
+	if details.LatestRevision != "" || details.LatestReadyRevision != "" {
+		dw.section("Revision Information")
+		if details.LatestRevision != "" {
+			dw.keyValue("Latest Revision", details.LatestRevision)
+		}
+		if details.LatestReadyRevision != "" {
+			dw.keyValue("Latest Ready Revision", details.LatestReadyRevision)
+		}
+		if !details.RevisionCreationTime.IsZero() {
+			dw.keyValue("Revision Created", dw.time(details.RevisionCreationTime))
+		}
+		if len(details.ContainerStatuses) > 0 {
+			dw.keyValue("Container Statuses", "")
+			for _, cs := range details.ContainerStatuses {
+				digest := ""
+				if cs.ImageDigest != "" {
+					digest = ", digest: " + s.Escape(cs.ImageDigest)
+				}
+				dw.indented(1, "%s%s: %s%s (restarts: %d%s)", s.Dim, s.Escape(cs.Name), dw.readyText(cs.Ready), s.Dim, cs.RestartCount, digest)
+			}
+		}
+		dw.end()
+	}
+
+	// Environment Variables
+	if len(details.EnvVars) > 0 {
+		dw.section("Environment Variables")
+		for _, k := range sortedKeys(details.EnvVars) {
+			dw.keyValue(k, details.EnvVars[k])
+		}
+		dw.end()
+	}
+
+	// Secrets & Volumes
+	if len(details.Secrets) > 0 || len(details.Volumes) > 0 {
+		dw.section("Secrets & Volumes")
+		if len(details.Secrets) > 0 {
+			dw.keyValue("Secrets", "")
+			for _, secret := range details.Secrets {
+				dw.indented(1, "%s%s: %s%s", s.Dim, s.Escape(secret.Name), s.Value, s.Escape(secret.MountPath))
+				for _, item := range secret.Items {
+					dw.indented(2, "%s%s as %s%s", s.Dim, s.Escape(item.Key), s.Value, s.Escape(item.Path))
+				}
+			}
+		}
+		if len(details.Volumes) > 0 {
+			dw.keyValue("Volumes", "")
+			for _, vol := range details.Volumes {
+				readOnly := ""
+				if vol.ReadOnly {
+					readOnly = " " + s.Dim + "(read-only)"
+				}
+				dw.indented(1, "%s%s (%s): %s%s%s", s.Dim, s.Escape(vol.Name), s.Escape(vol.VolumeType), s.Value, s.Escape(vol.MountPath), readOnly)
+			}
+		}
+		dw.end()
+	}
+
+	// Service Conditions
+	if len(details.RevisionConditions) > 0 {
+		dw.section("Service Conditions")
+		for _, cond := range details.RevisionConditions {
+			status := s.Bad + s.Escape(cond.Status)
+			if cond.Status == "True" {
+				status = s.Good + s.Escape(cond.Status)
+			}
+			dw.keyValueRaw(cond.Type, fmt.Sprintf("%s (%s)", status, s.Escape(cond.Reason)))
+			if cond.Message != "" {
+				dw.indented(2, "%s%s", s.Dim, s.Escape(cond.Message))
+			}
+			if !cond.LastTransitionTime.IsZero() {
+				dw.indented(2, "%sSince %s", s.Dim, dw.time(cond.LastTransitionTime))
+			}
+		}
+		dw.end()
+	}
+
+	// Additional Information
+	if details.LogURL != "" || details.SelfLink != "" {
+		dw.section("Additional Information")
+		if details.LogURL != "" {
+			dw.keyValue("Logs", details.LogURL)
+		}
+		if details.SelfLink != "" {
+			dw.keyValue("Self Link", details.SelfLink)
+		}
+		if details.OperationID != "" {
+			dw.keyValue("Operation ID", details.OperationID)
+		}
+		dw.end()
+	}
+
+	return dw.err
+}
+
+func (dw *DetailsWriter) section(title string) {
+	dw.current = title
+	dw.printf("%s%s%s\n", dw.Style.Section, title, dw.Style.Reset)
+}
+
+// end ends the current section
+func (dw *DetailsWriter) end() {
+	dw.printf("\n")
+	if dw.After != nil && dw.err == nil {
+		dw.After(dw.current)
+	}
+}
+
+// keyValue writes an escaped value
+func (dw *DetailsWriter) keyValue(key, value string) {
+	dw.keyValueRaw(key, dw.Style.Escape(value))
+}
+
+// keyValueRaw writes a value holding markup
+func (dw *DetailsWriter) keyValueRaw(key, value string) {
+	if value == "" {
+		// The key of an indented list
+		dw.printf("\t%s%s:\n", dw.Style.Key, dw.Style.Escape(key))
+		return
+	}
+	dw.printf("\t%s%s: %s%s\n", dw.Style.Key, dw.Style.Escape(key), dw.Style.Value, value)
+}
+
+// keyValues writes a map under a key, sorted by key
+func (dw *DetailsWriter) keyValues(key string, values map[string]string) {
+	if len(values) == 0 {
+		return
+	}
+	dw.keyValue(key, "")
+	for _, k := range sortedKeys(values) {
+		dw.indented(1, "%s%s: %s%s", dw.Style.Dim, dw.Style.Escape(k), dw.Style.Value, dw.Style.Escape(values[k]))
+	}
+}
+
+func (dw *DetailsWriter) indented(indent int, format string, args ...interface{}) {
+	dw.printf(strings.Repeat("  ", indent)+format+"\n", args...)
+}
+
+// probe writes a health probe, if set
+func (dw *DetailsWriter) probe(name string, probe *model.HealthProbe) {
+	if probe == nil {
+		return
+	}
+	timing := fmt.Sprintf("delay: %ds, period: %ds, timeout: %ds, failure threshold: %d, success threshold: %d",
+		probe.InitialDelaySeconds, probe.PeriodSeconds, probe.TimeoutSeconds, probe.FailureThreshold, probe.SuccessThreshold)
+	if probe.HTTPGet == nil {
+		dw.keyValue(name, fmt.Sprintf("TCP (%s)", timing))
+		return
+	}
+	path := probe.HTTPGet.Path
+	if path == "" {
+		path = "/"
+	}
+	dw.keyValue(name, fmt.Sprintf("HTTP %s:%d%s (%s)", probe.HTTPGet.Scheme, probe.HTTPGet.Port, path, timing))
+	for _, k := range sortedKeys(probe.HTTPGet.Headers) {
+		dw.indented(2, "%s%s: %s", dw.Style.Dim, dw.Style.Escape(k), dw.Style.Escape(probe.HTTPGet.Headers[k]))
+	}
+}
+
+func (dw *DetailsWriter) readyText(ready bool) string {
+	if ready {
+		return dw.Style.Good + "Ready"
+	}
+	return dw.Style.Bad + "Not Ready"
+}
+
+func (dw *DetailsWriter) enabledText(enabled bool) string {
+	if enabled {
+		return dw.Style.Good + "Enabled"
+	}
+	return dw.Style.Bad + "Disabled"
+}
+
+// time formats a time in the location of the writer, or N/A
+func (dw *DetailsWriter) time(t time.Time) string {
+	if t.IsZero() {
+		return "N/A"
+	}
+	loc := dw.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return t.In(loc).Format(DeployTimeLayout)
+}
+
+func (dw *DetailsWriter) printf(format string, args ...interface{}) {
+	if dw.err == nil {
+		_, dw.err = fmt.Fprintf(dw.w, format, args...)
+	}
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// WriteDetails writes the details of a service to w in the text, JSON or
+// YAML format, masking sensitive environment variables unless showSecrets
+func WriteDetails(w io.Writer, details *model.ServiceDetails, format Format, showSecrets bool, loc *time.Location) error {
+	if !showSecrets {
+		details = MaskSecrets(details)
+	}
+	switch format {
+	case Text, "":
+		return NewDetailsWriter(PlainStyle, loc).Write(w, details)
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(details)
+	case YAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(details); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
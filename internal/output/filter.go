@@ -0,0 +1,103 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// serviceFields returns the fields of a service that filters can match, by name
+var serviceFields = map[string]func(model.Service) string{
+	"name":    model.Service.GetName,
+	"region":  model.Service.GetRegion,
+	"url":     model.Service.GetURL,
+	"status":  model.Service.GetStatus,
+	"traffic": model.Service.GetTraffic,
+}
+
+// filterTerm is a condition of a service filter
+type filterTerm struct {
+	field   string
+	pattern *regexp.Regexp
+	negate  bool
+}
+
+// ServiceFilter selects services. It is a name substring, or comma separated
+// field=pattern and field!=pattern terms that must all match, where pattern
+// is matched case-insensitively and * and ? match any text and any
+// character, e.g. status!=Ready,region=us-*
+type ServiceFilter struct {
+	terms []filterTerm
+}
+
+// ParseServiceFilter parses a filter expression; an empty one matches all services
+func ParseServiceFilter(expr string) (*ServiceFilter, error) {
+	f := &ServiceFilter{}
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return f, nil
+	}
+	if !strings.Contains(expr, "=") {
+		f.terms = append(f.terms, filterTerm{field: "name", pattern: glob("*" + expr + "*")})
+		return f, nil
+	}
+
+	for _, text := range strings.Split(expr, ",") {
+		field, pattern, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter term %q: want field=pattern or field!=pattern", text)
+		}
+		term := filterTerm{pattern: glob(strings.TrimSpace(pattern))}
+		if strings.HasSuffix(field, "!") {
+			term.negate = true
+			field = strings.TrimSuffix(field, "!")
+		}
+		term.field = strings.ToLower(strings.TrimSpace(field))
+		if _, ok := serviceFields[term.field]; !ok {
+			return nil, fmt.Errorf("unknown filter field %q (allowed: name, region, url, status, traffic)", term.field)
+		}
+		f.terms = append(f.terms, term)
+	}
+	return f, nil
+}
+
+// Match reports whether a service matches all the terms of the filter
+func (f *ServiceFilter) Match(svc model.Service) bool {
+	for _, term := range f.terms {
+		if term.pattern.MatchString(serviceFields[term.field](svc)) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// glob compiles a pattern where * and ? match any text and any character
+func glob(pattern string) *regexp.Regexp {
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.MustCompile(expr.String())
+}
+
+// Filter returns the services matching the filter, in order
+func (f *ServiceFilter) Filter(services []model.Service) []model.Service {
+	var matched []model.Service
+	for _, svc := range services {
+		if f.Match(svc) {
+			matched = append(matched, svc)
+		}
+	}
+	return matched
+}
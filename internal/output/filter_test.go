@@ -0,0 +1,47 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestServiceFilter(t *testing.T) {
+	svc := &model.CloudRunService{
+		Name:    "backend-api",
+		Region:  "europe-west1",
+		URL:     "https://backend-api-hash.run.app",
+		Status:  "Ready",
+		Traffic: "100%",
+	}
+	tests := map[string]bool{
+		"":                                true,
+		"API":                             true,
+		"frontend":                        false,
+		"region=europe-*":                 true,
+		"region=us-*":                     false,
+		"url=*.run.app":                   true,
+		"status!=ready":                   false,
+		"status=Ready,name=backend-???":   true,
+		"status=Ready,traffic!=100%":      false,
+		" name = backend-api , region=*1": true,
+	}
+	for expr, want := range tests {
+		f, err := ParseServiceFilter(expr)
+		if err != nil {
+			t.Errorf("ParseServiceFilter(%q) failed: %v", expr, err)
+			continue
+		}
+		if got := f.Match(svc); got != want {
+			t.Errorf("filter %q matched = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestParseServiceFilterErrors(t *testing.T) {
+	for _, expr := range []string{"owner=me", "status=Ready,broken"} {
+		if _, err := ParseServiceFilter(expr); err == nil {
+			t.Errorf("ParseServiceFilter(%q) succeeded, want an error", expr)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Package output renders services and their details for the terminal UI and
+// for the non-interactive commands, so both show the same columns and sections.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// Format is an output format of the non-interactive commands
+type Format string
+
+const (
+	Table Format = "table"
+	// Wide is the table with the details of each service
+	Wide Format = "wide"
+	JSON Format = "json"
+	YAML Format = "yaml"
+	// Name prints one service name per line
+	Name Format = "name"
+	// Text prints the details of a service in the sections of the deployment view
+	Text Format = "text"
+)
+
+// DeployTimeLayout is the layout of the last deploy time in tables
+const DeployTimeLayout = "2006-01-02 15:04:05 MST"
+
+// ServiceColumns are the columns of the services table
+var ServiceColumns = []string{"Name", "Region", "URL", "Status", "Last Deploy", "Traffic"}
+
+// WideColumns are the columns added to the services table by the wide format
+var WideColumns = []string{"Revision", "Image", "CPU", "Memory", "Instances"}
+
+// ServiceRow returns the cells of a service in the services table, with the
+// last deploy time in loc
+func ServiceRow(svc model.Service, loc *time.Location) []string {
+	return []string{
+		svc.GetName(),
+		svc.GetRegion(),
+		svc.GetURL(),
+		svc.GetStatus(),
+		svc.GetLastDeploy().In(loc).Format(DeployTimeLayout),
+		svc.GetTraffic(),
+	}
+}
+
+// WideRow returns the cells added by the wide format from the details of a
+// service; nil details leave them empty
+func WideRow(details *model.ServiceDetails) []string {
+	if details == nil {
+		return make([]string, len(WideColumns))
+	}
+	revision := details.LatestReadyRevision
+	if revision == "" {
+		revision = details.ActiveRevision
+	}
+	return []string{
+		revision,
+		details.ContainerImage,
+		details.CPU,
+		details.Memory,
+		fmt.Sprintf("%d-%d", details.MinInstances, details.MaxInstances),
+	}
+}
+
+// ServiceRecord is a service in the JSON and YAML formats
+type ServiceRecord struct {
+	Name       string    `json:"name" yaml:"name"`
+	Region     string    `json:"region" yaml:"region"`
+	URL        string    `json:"url" yaml:"url"`
+	Status     string    `json:"status" yaml:"status"`
+	LastDeploy time.Time `json:"lastDeploy" yaml:"lastDeploy"`
+	Traffic    string    `json:"traffic" yaml:"traffic"`
+}
+
+// NewServiceRecord returns the record of a service, with its last deploy time in UTC
+func NewServiceRecord(svc model.Service) ServiceRecord {
+	return ServiceRecord{
+		Name:       svc.GetName(),
+		Region:     svc.GetRegion(),
+		URL:        svc.GetURL(),
+		Status:     svc.GetStatus(),
+		LastDeploy: svc.GetLastDeploy().UTC(),
+		Traffic:    svc.GetTraffic(),
+	}
+}
+
+// ServiceWriter writes services in an output format
+type ServiceWriter struct {
+	Format Format
+	// Location of the last deploy times of tables
+	Location *time.Location
+	// Details returns the details of a service for the wide format
+	Details func(svc model.Service) (*model.ServiceDetails, error)
+}
+
+// Write writes services to w. In the wide format, services whose details
+// cannot be loaded are written with empty details and the first error is
+// returned after all services are written.
+func (sw ServiceWriter) Write(w io.Writer, services []model.Service) error {
+	switch sw.Format {
+	case Table, Wide, "":
+		return sw.writeTable(w, services)
+	case JSON, YAML:
+		records := make([]ServiceRecord, len(services))
+		for i, svc := range services {
+			records[i] = NewServiceRecord(svc)
+		}
+		if sw.Format == YAML {
+			enc := yaml.NewEncoder(w)
+			enc.SetIndent(2)
+			if err := enc.Encode(records); err != nil {
+				return err
+			}
+			return enc.Close()
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case Name:
+		for _, svc := range services {
+			if _, err := fmt.Fprintln(w, svc.GetName()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q", sw.Format)
+}
+
+// writeTable writes services as a table aligned on tab stops
+func (sw ServiceWriter) writeTable(w io.Writer, services []model.Service) error {
+	loc := sw.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	wide := sw.Format == Wide
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	columns := ServiceColumns
+	if wide {
+		columns = append(append([]string(nil), ServiceColumns...), WideColumns...)
+	}
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+
+	var firstErr error
+	for _, svc := range services {
+		row := ServiceRow(svc, loc)
+		if wide {
+			var details *model.ServiceDetails
+			if sw.Details != nil {
+				var err error
+				if details, err = sw.Details(svc); err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to get details of %s in %s: %v", svc.GetName(), svc.GetRegion(), err)
+				}
+			}
+			row = append(row, WideRow(details)...)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	return firstErr
+}
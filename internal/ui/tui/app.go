@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"github.com/derailed/tcell/v2"
 	"github.com/derailed/tview"
 	"github.com/lpmourato/c9s/internal/interfaces"
 )
@@ -14,6 +15,7 @@ type App struct {
 	pages      *tview.Pages
 	mainView   tview.Primitive // The main services view
 	activeView tview.Primitive // The currently displayed view
+	bell       bool            // Ring the terminal bell after the next draw
 }
 
 // NewApp creates a new application instance
@@ -22,6 +24,12 @@ func NewApp() *App {
 		Application: tview.NewApplication(),
 		pages:       tview.NewPages(),
 	}
+	app.SetAfterDrawFunc(func(screen tcell.Screen) {
+		if app.bell {
+			app.bell = false
+			screen.Beep()
+		}
+	})
 
 	return app
 }
@@ -66,6 +74,12 @@ func (a *App) ShowError(msg string) {
 	// For now, silently ignore errors to avoid console output
 }
 
+// Beep rings the terminal bell after the next draw.
+// It must be called from the UI goroutine, e.g. within QueueUpdateDraw.
+func (a *App) Beep() {
+	a.bell = true
+}
+
 // QueueUpdateDraw wraps the tview QueueUpdateDraw to match the UIController interface
 func (a *App) QueueUpdateDraw(f func()) {
 	a.Application.QueueUpdateDraw(f)
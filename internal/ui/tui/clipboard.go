@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// clipboardCommands are the clipboard tools tried in order, with their arguments
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// CopyToClipboard copies text to the system clipboard with the first
+// clipboard tool found. Writing a terminal sequence instead would interleave
+// with the screen updates of the UI, so without a tool it fails.
+func CopyToClipboard(text string) error {
+	for _, args := range clipboardCommands {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %v", args[0], err)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no clipboard tool found, install one of %s", clipboardToolNames())
+}
+
+// clipboardToolNames returns the names of the clipboard tools, e.g. "pbcopy, xclip"
+func clipboardToolNames() string {
+	names := make([]string, len(clipboardCommands))
+	for i, args := range clipboardCommands {
+		names[i] = args[0]
+	}
+	return strings.Join(names, ", ")
+}
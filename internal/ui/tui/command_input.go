@@ -20,6 +20,10 @@ type CommandHandler interface {
 	HandleProject(project string) error
 	HandleService(service string) error
 	HandleClear() error
+	HandleRequests(service string) error
+	HandleOpenLogs(path string) error
+	HandleAudit(service string) error
+	HandleDoctor() error
 	HandleQuit()
 }
 
@@ -55,6 +59,10 @@ func NewCommandInput(app *App, mainTable tview.Primitive) *CommandInput {
 		{Command: "service", Alias: "svc", Description: "Filter services by name"},
 		{Command: "project", Alias: "proj", Description: "Switch to a different project"},
 		{Command: "clear", Alias: "cl", Description: "Clear the current service filter"},
+		{Command: "requests", Alias: "req", Description: "Show the HTTP requests of a service"},
+		{Command: "audit", Alias: "au", Description: "Show who changed a service, from its audit logs"},
+		{Command: "doctor", Alias: "dr", Description: "Check the credentials, project, APIs, permissions and regions"},
+		{Command: "openlogs", Alias: "ol", Description: "Open exported logs from a JSON or JSON lines file or directory"},
 		{Command: "quit", Alias: "q", Description: "Exit the application"},
 	}
 
@@ -93,6 +101,25 @@ func NewCommandInput(app *App, mainTable tview.Primitive) *CommandInput {
 						if len(parts) > 1 {
 							input.handler.HandleService(parts[1])
 						}
+					case "requests", "req":
+						service := ""
+						if len(parts) > 1 {
+							service = parts[1]
+						}
+						input.handler.HandleRequests(service)
+					case "audit", "au":
+						service := ""
+						if len(parts) > 1 {
+							service = parts[1]
+						}
+						input.handler.HandleAudit(service)
+					case "doctor", "dr":
+						input.handler.HandleDoctor()
+					case "openlogs", "ol":
+						// The path is the rest of the command, so it may contain spaces
+						if path := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(cmd), parts[0])); path != "" {
+							input.handler.HandleOpenLogs(path)
+						}
 					case "quit", "q":
 						input.handler.HandleQuit()
 					case "clear", "cl":
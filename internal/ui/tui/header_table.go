@@ -91,3 +91,8 @@ func (h *HeaderTable) AddSection(row, col int, title, content string) {
 		SetBackgroundColor(tcell.ColorBlack).
 		SetSelectable(false))
 }
+
+// AddSparkline adds a titled section showing a sparkline of bars followed by a caption
+func (h *HeaderTable) AddSparkline(row, col int, title string, bars []SparkBar, selected int, caption string) {
+	h.AddSection(row, col, title, Sparkline(bars, selected)+" [gray::]"+caption)
+}
@@ -20,6 +20,33 @@ const (
 	ActionFilter
 	ActionRefresh
 	ActionHelp
+	ActionSeverityAll
+	ActionSeverityError
+	ActionSeverityWarning
+	ActionSeverityInfo
+	ActionSeverityDebug
+	ActionToggleServerSeverity
+	ActionEditQuery
+	ActionEditRevision
+	ActionEditTimeRange
+	ActionSaveLogs
+	ActionToggleTee
+	ActionFollow
+	ActionToggleFreeze
+	ActionToggleMark
+	ActionShowTrace
+	ActionCopyValue
+	ActionCopyEntry
+	ActionToggleRaw
+	ActionToggle5xx
+	ActionEditSlowThreshold
+	ActionClearFilters
+	ActionToggleCollapse
+	ActionShowPatterns
+	ActionPreviousBucket
+	ActionNextBucket
+	ActionToggleLogKind
+	ActionToggleColdStarts
 )
 
 // KeyHandler represents a centralized keyboard input handler
@@ -161,6 +188,11 @@ const (
 	ContextLogView
 	ContextDeploymentView
 	ContextServiceDetails
+	ContextLogInspector
+	ContextRequestsView
+	ContextLogPatterns
+	ContextAuditView
+	ContextDoctorView
 )
 
 // ContextualKeyHandler extends KeyHandler with context awareness
@@ -196,12 +228,80 @@ func (ckh *ContextualKeyHandler) setupContextFilters() {
 		return event.Key() == tcell.KeyEscape
 	}
 
-	// Log view context - handle navigation and escape
+	// Log view context - handle navigation, escape, severity filters and query editing
 	ckh.contextFilters[ContextLogView] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', 's', 'S', '0', '1', '2', '3', '4',
+				'/', 'v', 'V', 'r', 'R', 'T', 'w', 'W', 'G', 'f', 'p', 't', 'c', 'P', '[', ']', 'l':
+				return true
+			}
+			return false
+		}
 		return event.Key() == tcell.KeyEscape ||
+			event.Key() == tcell.KeyEnter ||
 			event.Key() == tcell.KeyUp ||
-			event.Key() == tcell.KeyDown ||
-			(event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q'))
+			event.Key() == tcell.KeyDown
+	}
+
+	// Log inspector context - field actions, navigation is left to the table
+	ckh.contextFilters[ContextLogInspector] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', 'y', 'Y', 'J':
+				return true
+			}
+			return false
+		}
+		return event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter
+	}
+
+	// Log patterns context - selecting a pattern, navigation is left to the table
+	ckh.contextFilters[ContextLogPatterns] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', 'P':
+				return true
+			}
+			return false
+		}
+		return event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter
+	}
+
+	// Requests view context - quick filters, navigation is left to the table
+	ckh.contextFilters[ContextRequestsView] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', '5', 's', '/', 'c':
+				return true
+			}
+			return false
+		}
+		return event.Key() == tcell.KeyEscape
+	}
+
+	// Audit view context - refresh and moving to the diff, navigation is left to the table
+	ckh.contextFilters[ContextAuditView] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', 'r', 'R':
+				return true
+			}
+			return false
+		}
+		return event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyEnter
+	}
+
+	// Doctor view context - running the checks again, navigation is left to the table
+	ckh.contextFilters[ContextDoctorView] = func(event *tcell.EventKey) bool {
+		if event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'q', 'Q', 'r', 'R':
+				return true
+			}
+			return false
+		}
+		return event.Key() == tcell.KeyEscape
 	}
 
 	// Deployment view context - similar to log view
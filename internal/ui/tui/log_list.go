@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+// LogRows provides the rows displayed by a LogList
+type LogRows interface {
+	// RowCount returns the number of rows
+	RowCount() int
+	// RowText returns the text of a row, which may contain color tags
+	RowText(row int) string
+}
+
+// LogList is a scrollable list of log rows with a selected row. Unlike a
+// TextView it never holds the rendered text: only the rows visible on screen
+// are formatted and drawn, so its cost does not grow with the number of rows.
+type LogList struct {
+	*tview.Box
+	rows          LogRows
+	offset        int  // index of the first visible row
+	selected      int  // index of the selected row, unless following
+	trackEnd      bool // follow mode: keep the last row visible and selected as rows are added
+	height        int  // inner height at the last draw
+	topReached    func()
+	followChanged func(following bool)
+}
+
+// NewLogList creates a list drawing the rows provided by rows
+func NewLogList(rows LogRows) *LogList {
+	return &LogList{
+		Box:      tview.NewBox(),
+		rows:     rows,
+		trackEnd: true,
+	}
+}
+
+// SetTopReachedFunc sets a handler called when the user scrolls up past the first row
+func (l *LogList) SetTopReachedFunc(handler func()) *LogList {
+	l.topReached = handler
+	return l
+}
+
+// SetFollowChangedFunc sets a handler called when follow mode is turned on or off
+func (l *LogList) SetFollowChangedFunc(handler func(following bool)) *LogList {
+	l.followChanged = handler
+	return l
+}
+
+// ScrollToEnd scrolls to the last row and turns on follow mode, keeping the
+// last row visible as rows are added
+func (l *LogList) ScrollToEnd() {
+	l.setFollow(true)
+}
+
+// IsFollowing reports whether follow mode is on
+func (l *LogList) IsFollowing() bool {
+	return l.trackEnd
+}
+
+// ScrollToBeginning scrolls to the first row
+func (l *LogList) ScrollToBeginning() {
+	l.ScrollTo(0)
+}
+
+// ScrollTo makes row the first visible row and turns off follow mode
+func (l *LogList) ScrollTo(row int) {
+	l.setFollow(false)
+	l.offset = row
+	l.clampOffset()
+	l.selectVisible()
+}
+
+// ShiftRows keeps the visible and selected rows in place after delta rows were
+// inserted (or removed when negative) before them. It does nothing while following.
+func (l *LogList) ShiftRows(delta int) {
+	if l.trackEnd {
+		return
+	}
+	l.offset += delta
+	l.selected += delta
+	l.clampOffset()
+	l.clampSelection()
+}
+
+// GetSelectedRow returns the index of the selected row, or -1 when there are no rows
+func (l *LogList) GetSelectedRow() int {
+	if l.trackEnd {
+		return l.rows.RowCount() - 1
+	}
+	if l.rows.RowCount() == 0 {
+		return -1
+	}
+	return l.selected
+}
+
+// Select selects a row, scrolling to it, and turns off follow mode
+func (l *LogList) Select(row int) {
+	l.setFollow(false)
+	l.selected = row
+	l.clampSelection()
+	l.showSelected()
+}
+
+// GetScrollOffset returns the index of the first visible row
+func (l *LogList) GetScrollOffset() int {
+	if l.trackEnd {
+		return l.maxOffset()
+	}
+	return l.offset
+}
+
+// IsAtEnd reports whether the last row is visible
+func (l *LogList) IsAtEnd() bool {
+	return l.trackEnd || l.offset >= l.maxOffset()
+}
+
+// Draw draws the visible rows
+func (l *LogList) Draw(screen tcell.Screen) {
+	l.Box.DrawForSubclass(screen, l)
+	x, y, width, height := l.GetInnerRect()
+	l.height = height
+
+	if l.trackEnd {
+		l.offset = l.maxOffset()
+	}
+	l.clampOffset()
+
+	selected := l.GetSelectedRow()
+	count := l.rows.RowCount()
+	for i := 0; i < height && l.offset+i < count; i++ {
+		tview.Print(screen, l.rows.RowText(l.offset+i), x, y+i, width, tview.AlignLeft, tcell.ColorWhite)
+		if l.offset+i == selected {
+			highlightRow(screen, x, y+i, width)
+		}
+	}
+}
+
+// highlightRow sets the selection background on a drawn row
+func highlightRow(screen tcell.Screen, x, y, width int) {
+	for cx := x; cx < x+width; cx++ {
+		mainc, combc, style, _ := screen.GetContent(cx, y)
+		screen.SetContent(cx, y, mainc, combc, style.Background(tcell.ColorNavy))
+	}
+}
+
+// InputHandler handles selection and scrolling keys
+func (l *LogList) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return l.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		switch event.Key() {
+		case tcell.KeyUp:
+			l.moveSelection(-1)
+		case tcell.KeyDown:
+			l.moveSelection(1)
+		case tcell.KeyPgUp:
+			l.moveSelection(-l.pageSize())
+		case tcell.KeyPgDn:
+			l.moveSelection(l.pageSize())
+		case tcell.KeyHome:
+			l.ScrollToBeginning()
+		case tcell.KeyEnd:
+			l.ScrollToEnd()
+		}
+	})
+}
+
+// MouseHandler handles scrolling with the mouse wheel
+func (l *LogList) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return l.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		if !l.InRect(event.Position()) {
+			return false, nil
+		}
+		switch action {
+		case tview.MouseLeftClick:
+			setFocus(l)
+			_, y := event.Position()
+			_, top, _, _ := l.GetInnerRect()
+			if row := l.GetScrollOffset() + y - top; row < l.rows.RowCount() {
+				l.Select(row)
+			}
+			consumed = true
+		case tview.MouseScrollUp:
+			l.scroll(-1)
+			consumed = true
+		case tview.MouseScrollDown:
+			l.scroll(1)
+			consumed = true
+		}
+		return
+	})
+}
+
+// moveSelection moves the selected row by delta, notifying when moving up past the top
+func (l *LogList) moveSelection(delta int) {
+	selected := l.GetSelectedRow()
+	if delta < 0 && selected <= 0 {
+		if l.topReached != nil {
+			l.topReached()
+		}
+		return
+	}
+	if delta > 0 && l.trackEnd {
+		return // Already on the last row
+	}
+
+	// Any manual selection leaves follow mode; it is turned back on explicitly
+	l.setFollow(false)
+	l.selected = selected + delta
+	l.clampSelection()
+	l.showSelected()
+}
+
+// scroll moves the visible rows by delta, notifying when scrolling up past the top
+func (l *LogList) scroll(delta int) {
+	offset := l.GetScrollOffset()
+	if delta < 0 && offset == 0 {
+		if l.topReached != nil {
+			l.topReached()
+		}
+		return
+	}
+	if delta > 0 && l.trackEnd {
+		return // Already showing the last row
+	}
+
+	// Any manual scrolling leaves follow mode; it is turned back on explicitly
+	l.setFollow(false)
+	l.offset = offset + delta
+	l.clampOffset()
+	l.selectVisible()
+}
+
+// setFollow turns follow mode on or off, notifying the change
+func (l *LogList) setFollow(follow bool) {
+	if l.trackEnd == follow {
+		return
+	}
+	if !follow {
+		// Freeze the offset and selection where follow mode left them
+		l.offset = l.maxOffset()
+		l.selected = l.rows.RowCount() - 1
+	}
+	l.trackEnd = follow
+	if l.followChanged != nil {
+		l.followChanged(follow)
+	}
+}
+
+// pageSize returns the number of rows scrolled by a page
+func (l *LogList) pageSize() int {
+	if l.height > 1 {
+		return l.height - 1
+	}
+	return 1
+}
+
+// maxOffset returns the offset showing the last row at the bottom
+func (l *LogList) maxOffset() int {
+	if max := l.rows.RowCount() - l.height; max > 0 {
+		return max
+	}
+	return 0
+}
+
+// clampSelection keeps the selected row within the rows
+func (l *LogList) clampSelection() {
+	if max := l.rows.RowCount() - 1; l.selected > max {
+		l.selected = max
+	}
+	if l.selected < 0 {
+		l.selected = 0
+	}
+}
+
+// selectVisible moves the selection the least needed to keep it on screen
+func (l *LogList) selectVisible() {
+	if l.selected < l.offset {
+		l.selected = l.offset
+	}
+	if l.height > 0 && l.selected >= l.offset+l.height {
+		l.selected = l.offset + l.height - 1
+	}
+	l.clampSelection()
+}
+
+// showSelected scrolls the least needed to make the selected row visible
+func (l *LogList) showSelected() {
+	if l.selected < l.offset {
+		l.offset = l.selected
+	}
+	if l.height > 0 && l.selected >= l.offset+l.height {
+		l.offset = l.selected - l.height + 1
+	}
+	l.clampOffset()
+}
+
+// clampOffset keeps the offset within the rows
+func (l *LogList) clampOffset() {
+	if max := l.maxOffset(); l.offset > max {
+		l.offset = max
+	}
+	if l.offset < 0 {
+		l.offset = 0
+	}
+}
@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+// Prompt is a single line input shown at the bottom of a view to ask for a value
+type Prompt struct {
+	*tview.InputField
+	app       *App
+	container *tview.Flex
+	returnTo  tview.Primitive
+	visible   bool
+	onDone    func(text string)
+}
+
+// NewPrompt creates a prompt that is added to container while visible and
+// hands focus back to returnTo once it is closed
+func NewPrompt(app *App, container *tview.Flex, returnTo tview.Primitive) *Prompt {
+	p := &Prompt{
+		InputField: tview.NewInputField(),
+		app:        app,
+		container:  container,
+		returnTo:   returnTo,
+	}
+
+	p.
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldTextColor(tcell.ColorWhite)
+
+	p.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			p.Hide()
+			return nil
+		case tcell.KeyEnter:
+			text := p.GetText()
+			done := p.onDone
+			p.Hide()
+			if done != nil {
+				done(text)
+			}
+			return nil
+		}
+		return event
+	})
+
+	return p
+}
+
+// Ask shows the prompt with the given label and initial value.
+// done is called with the entered text when the user presses Enter.
+func (p *Prompt) Ask(label, value string, done func(text string)) {
+	p.onDone = done
+	p.SetLabel(label)
+	p.SetText(value)
+	if !p.visible {
+		p.visible = true
+		p.container.AddItem(p, 1, 0, false)
+	}
+	p.app.SetFocus(p)
+}
+
+// Hide removes the prompt and returns focus to the view
+func (p *Prompt) Hide() {
+	if !p.visible {
+		return
+	}
+	p.visible = false
+	p.onDone = nil
+	p.container.RemoveItem(p)
+	p.app.SetFocus(p.returnTo)
+}
+
+// IsVisible returns whether the prompt is visible
+func (p *Prompt) IsVisible() bool {
+	return p.visible
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sparkBlocks are the characters of the bar heights, lowest first
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// SparkBar is one bar of a sparkline
+type SparkBar struct {
+	Value int
+	// Color name of the bar, as used in color tags
+	Color string
+}
+
+// Sparkline renders bars as block characters scaled to the highest value.
+// Empty bars are blank and the bar at index selected, if any, is reversed.
+func Sparkline(bars []SparkBar, selected int) string {
+	max := 0
+	for _, bar := range bars {
+		if bar.Value > max {
+			max = bar.Value
+		}
+	}
+
+	var b strings.Builder
+	for i, bar := range bars {
+		block := ' '
+		if bar.Value > 0 {
+			level := (bar.Value*len(sparkBlocks) + max - 1) / max
+			block = sparkBlocks[level-1]
+		}
+		attrs := ""
+		if i == selected {
+			attrs = "r"
+		}
+		fmt.Fprintf(&b, "[%s::%s]%c[-:-:-]", bar.Color, attrs, block)
+	}
+	return b.String()
+}
@@ -6,3 +6,8 @@ import "github.com/lpmourato/c9s/internal/ui/tui"
 func NewApp() *tui.App {
 	return tui.NewApp()
 }
+
+// StartMockLogView runs a standalone mock log view
+func StartMockLogView() error {
+	return tui.StartMockLogView()
+}
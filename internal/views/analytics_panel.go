@@ -0,0 +1,238 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/interfaces"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+const (
+	// analyticsHistory is how far back request log entries are loaded
+	analyticsHistory = 24 * time.Hour
+	// analyticsMaxEntries bounds the number of request log entries loaded
+	analyticsMaxEntries = 5000
+	analyticsPageSize   = 1000
+	analyticsTopPaths   = 5
+)
+
+// analyticsWindow is a window selectable in the analytics panel
+type analyticsWindow struct {
+	Key      rune
+	Duration time.Duration
+	Desc     string
+}
+
+// analyticsWindows are the windows selectable in the analytics panel
+var analyticsWindows = []analyticsWindow{
+	{Key: '1', Duration: 5 * time.Minute, Desc: "5m"},
+	{Key: '2', Duration: 15 * time.Minute, Desc: "15m"},
+	{Key: '3', Duration: time.Hour, Desc: "1h"},
+	{Key: '4', Duration: 6 * time.Hour, Desc: "6h"},
+	{Key: '5', Duration: 24 * time.Hour, Desc: "24h"},
+}
+
+// RequestAnalyticsPanel summarizes the recent requests of a service:
+// latency percentiles, status classes, throughput and failing paths
+type RequestAnalyticsPanel struct {
+	*tview.TextView
+	app    interfaces.UIController
+	ctx    context.Context
+	cancel context.CancelFunc
+	// provider is owned by the panel and closed with it
+	provider model.LogProvider
+
+	entries  []model.LogEntry
+	loadedAt time.Time
+	window   analyticsWindow
+	err      error
+	loading  bool
+}
+
+// NewRequestAnalyticsPanel creates an empty analytics panel
+func NewRequestAnalyticsPanel(app interfaces.UIController) *RequestAnalyticsPanel {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &RequestAnalyticsPanel{
+		TextView: tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		app:      app,
+		ctx:      ctx,
+		cancel:   cancel,
+		window:   analyticsWindows[2],
+	}
+	p.SetBorder(true)
+	p.SetTitleAlign(tview.AlignLeft)
+	p.render()
+	return p
+}
+
+// Load fetches the request log entries of the last 24 hours of a service.
+// The panel takes ownership of the provider.
+func (p *RequestAnalyticsPanel) Load(provider model.LogProvider, projectID, serviceName, region string) {
+	p.provider = provider
+	now := time.Now()
+	opts := model.CloudProviderOptions{
+		ProjectID:   projectID,
+		ServiceName: serviceName,
+		Region:      region,
+		LogIDs:      []string{logging.RequestsLogID},
+		Since:       now.Add(-analyticsHistory),
+	}
+	filter := logging.QueryFilter(provider, opts)
+
+	p.loading = true
+	p.render()
+
+	ctx := p.ctx
+	go func() {
+		entries, err := fetchNewest(ctx, provider, filter, analyticsMaxEntries, analyticsPageSize)
+
+		p.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			p.loading = false
+			p.entries = entries
+			p.loadedAt = now
+			p.err = err
+			p.render()
+		})
+	}()
+}
+
+// SetError shows why the request log entries could not be loaded
+func (p *RequestAnalyticsPanel) SetError(err error) {
+	p.err = err
+	p.render()
+}
+
+// SetWindow summarizes the requests of the window selected by key.
+// It reports whether key selects a window.
+func (p *RequestAnalyticsPanel) SetWindow(key rune) bool {
+	for _, window := range analyticsWindows {
+		if window.Key == key {
+			p.window = window
+			p.render()
+			return true
+		}
+	}
+	return false
+}
+
+// Close stops loading entries and releases the provider
+func (p *RequestAnalyticsPanel) Close() {
+	p.cancel()
+	if p.provider != nil {
+		closeProvider(p.provider)
+		p.provider = nil
+	}
+}
+
+// render shows the summary of the selected window
+func (p *RequestAnalyticsPanel) render() {
+	keys := make([]string, len(analyticsWindows))
+	for i, window := range analyticsWindows {
+		keys[i] = fmt.Sprintf("%c(%s)", window.Key, window.Desc)
+	}
+	p.SetTitle(fmt.Sprintf(" Requests - last %s | %s ", p.window.Desc, strings.Join(keys, " ")))
+
+	p.Clear()
+	switch {
+	case p.loading:
+		fmt.Fprint(p, "[yellow::b]Loading request logs...\n")
+		return
+	case p.err != nil:
+		fmt.Fprintf(p, "[red::]Failed to load request logs: %v\n", p.err)
+		return
+	}
+
+	summary := analytics.SummarizeRequests(p.entries, p.window.Duration, p.loadedAt, analyticsTopPaths)
+	if summary.Total == 0 {
+		fmt.Fprintf(p, "[gray::]No requests in the last %s\n", p.window.Desc)
+		return
+	}
+
+	fmt.Fprint(p, "[yellow::b]Traffic[-:-:-]\n")
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] %d\n", "Requests", summary.Total)
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] %.1f\n", "Per minute", summary.RequestsPerMinute)
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] [%s::]%.2f%%[-::]\n", "Error rate", errorRateColor(summary.ErrorRate()), summary.ErrorRate()*100)
+
+	fmt.Fprint(p, "\n[yellow::b]Latency[-:-:-]\n")
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] %s\n", "p50", formatLatency(summary.P50))
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] %s\n", "p95", formatLatency(summary.P95))
+	fmt.Fprintf(p, "  [white::b]%-14s[-:-:-] %s\n", "p99", formatLatency(summary.P99))
+
+	fmt.Fprint(p, "\n[yellow::b]Status[-:-:-]\n")
+	for _, class := range analytics.StatusClasses {
+		count := summary.StatusClasses[class]
+		if count == 0 && class == analytics.ClassOther {
+			continue
+		}
+		fmt.Fprintf(p, "  [%s::b]%-14s[-:-:-] %d (%.1f%%)\n", statusClassColor(class), class, count,
+			float64(count)*100/float64(summary.Total))
+	}
+
+	if len(summary.TopFailingPaths) > 0 {
+		fmt.Fprint(p, "\n[yellow::b]Top Failing Paths[-:-:-]\n")
+		for _, path := range summary.TopFailingPaths {
+			fmt.Fprintf(p, "  [red::b]%5d[-:-:-] %s\n", path.Count, tview.Escape(path.Path))
+		}
+	}
+
+	if len(p.entries) >= analyticsMaxEntries {
+		fmt.Fprintf(p, "\n[gray::]Based on the latest %d requests\n", analyticsMaxEntries)
+	}
+}
+
+// errorRateColor returns the color of a 5xx rate
+func errorRateColor(rate float64) string {
+	switch {
+	case rate >= 0.05:
+		return "red"
+	case rate > 0:
+		return "yellow"
+	}
+	return "green"
+}
+
+// statusClassColor returns the color of a status class
+func statusClassColor(class string) string {
+	switch class {
+	case analytics.Class2xx:
+		return "green"
+	case analytics.Class3xx:
+		return "aqua"
+	case analytics.Class4xx:
+		return "yellow"
+	case analytics.Class5xx:
+		return "red"
+	}
+	return "gray"
+}
+
+// NewServiceOverview places the analytics panel next to the deployment details.
+// Number keys select the analytics window; leaving the view stops loading.
+func NewServiceOverview(details *DeploymentView, panel *RequestAnalyticsPanel) *tview.Flex {
+	overview := tview.NewFlex().
+		AddItem(details, 0, 3, true).
+		AddItem(panel, 0, 2, false)
+
+	overview.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && panel.SetWindow(event.Rune()) {
+			return nil
+		}
+		// The details close themselves on Escape
+		if event.Key() == tcell.KeyEscape {
+			panel.Close()
+		}
+		return event
+	})
+
+	return overview
+}
@@ -0,0 +1,261 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/audit"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+const (
+	// auditHistory is how far back audit log entries are loaded
+	auditHistory = 30 * 24 * time.Hour
+	// auditMaxEntries bounds the number of audit log entries loaded
+	auditMaxEntries = 500
+	auditPageSize   = 100
+)
+
+// AuditView lists the changes made to a service from its Admin Activity
+// audit logs, with the request diff of the selected change
+type AuditView struct {
+	*tview.Flex
+	table  *tui.Table
+	detail *tview.TextView
+	header *tui.HeaderTable
+	app    *tui.App
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	provider    model.LogProvider
+	serviceName string
+	region      string
+
+	events  []audit.Event
+	loading bool
+	err     error
+}
+
+// NewAuditView creates a view of the changes made to a service. The view
+// takes ownership of the provider.
+func NewAuditView(app *tui.App, provider model.LogProvider, serviceName, region string) *AuditView {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v := &AuditView{
+		Flex:        tview.NewFlex().SetDirection(tview.FlexRow),
+		table:       tui.NewTable(),
+		detail:      tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		header:      tui.NewHeaderTable(),
+		app:         app,
+		ctx:         ctx,
+		cancel:      cancel,
+		provider:    provider,
+		serviceName: serviceName,
+		region:      region,
+	}
+
+	v.header.SetTitle(" Audit ")
+	v.table.SetTitle(fmt.Sprintf(" %s - %s | Changes ", serviceName, region))
+	v.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
+	v.table.SetSelectionChangedFunc(func(row, column int) {
+		v.renderDetail(row)
+	})
+	v.detail.SetBorder(true)
+	v.detail.SetTitle(" Request Diff ")
+	v.detail.SetTitleAlign(tview.AlignLeft)
+
+	v.AddItem(v.header, 5, 0, false)
+	v.AddItem(v.table, 0, 1, true)
+	v.AddItem(v.detail, 0, 1, false)
+
+	v.setupKeys()
+	v.render()
+
+	return v
+}
+
+// setupKeys sets up the key bindings of the audit view
+func (v *AuditView) setupKeys() {
+	keyHandler := tui.NewContextualKeyHandler(v.app)
+	keyHandler.SetContext(tui.ContextAuditView)
+
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		v.close()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		v.close()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionRefresh, func() error {
+		v.Load()
+		return nil
+	})
+
+	// Enter moves to the diff to scroll it, Esc moves back to the changes
+	keyHandler.RegisterHandler(tui.ActionEnter, func() error {
+		v.app.SetFocus(v.detail)
+		return nil
+	})
+	v.detail.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			v.app.SetFocus(v.table)
+			return nil
+		}
+		return event
+	})
+
+	v.table.SetInputCapture(keyHandler.CreateContextualInputCapture())
+}
+
+// Load fetches the audit log entries of the service in the background
+func (v *AuditView) Load() {
+	v.loading = true
+	v.render()
+
+	ctx := v.ctx
+	filter := audit.Filter(v.serviceName, v.region, time.Now().Add(-auditHistory))
+	go func() {
+		entries, err := fetchNewest(ctx, v.provider, filter, auditMaxEntries, auditPageSize)
+		v.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			v.loading = false
+			v.events = audit.Events(entries, v.serviceName)
+			v.err = err
+			v.render()
+		})
+	}()
+}
+
+// render rebuilds the table of changes
+func (v *AuditView) render() {
+	row, _ := v.table.GetSelection()
+	v.table.Clear()
+	v.table.SetColumns([]string{"Time", "Principal", "Method", "Revision", "Status"})
+
+	for i, e := range v.events {
+		status, statusColor := "OK", tcell.ColorGreen
+		if e.Failed() {
+			status, statusColor = "FAILED: "+e.Error, tcell.ColorRed
+		}
+		principal := e.Principal
+		if principal == "" {
+			principal = "-"
+		}
+		v.table.AddStyledRow(i+1, []tui.TableCell{
+			{Text: e.Timestamp.Local().Format("2006-01-02 15:04:05"), TextColor: tcell.ColorWhite, Expansion: 1},
+			{Text: tview.Escape(principal), TextColor: tcell.ColorWhite, Expansion: 2},
+			{Text: e.Method, TextColor: methodColor(e.Method), Expansion: 1},
+			{Text: e.Revision, TextColor: tcell.ColorWhite, Expansion: 1},
+			{Text: tview.Escape(truncate(status, 80)), TextColor: statusColor, Expansion: 2},
+		})
+	}
+
+	if row < 1 {
+		row = 1
+	}
+	if row > len(v.events) {
+		row = len(v.events)
+	}
+	v.table.Select(row, 0)
+	v.renderDetail(row)
+	v.updateHeader()
+}
+
+// renderDetail shows the selected change and its request diff
+func (v *AuditView) renderDetail(row int) {
+	v.detail.Clear()
+	i := row - 1
+	if i < 0 || i >= len(v.events) {
+		return
+	}
+	e := v.events[i]
+
+	fmt.Fprintf(v.detail, "[teal::]Method: [silver::]%s\n", e.MethodName)
+	fmt.Fprintf(v.detail, "[teal::]Resource: [silver::]%s\n", tview.Escape(e.ResourceName))
+	if e.Failed() {
+		fmt.Fprintf(v.detail, "[teal::]Error: [red::]%s\n", tview.Escape(e.Error))
+	}
+	fmt.Fprintln(v.detail)
+
+	if e.Request == nil {
+		fmt.Fprint(v.detail, "[gray::]The audit log of this call has no request\n")
+		return
+	}
+
+	var previous map[string]interface{}
+	if j := audit.Previous(v.events, i); j >= 0 {
+		p := v.events[j]
+		previous = p.Request
+		fmt.Fprintf(v.detail, "[orange::b]Changes since %s by %s[-:-:-]\n",
+			p.Timestamp.Local().Format("2006-01-02 15:04:05"), tview.Escape(p.Principal))
+	} else {
+		fmt.Fprint(v.detail, "[orange::b]Request[-:-:-] [gray::](no earlier request to compare with)\n")
+	}
+
+	changes := audit.Diff(previous, e.Request)
+	if len(changes) == 0 {
+		fmt.Fprint(v.detail, "[gray::]No changes\n")
+	}
+	for _, c := range changes {
+		switch {
+		case c.Old == "":
+			fmt.Fprintf(v.detail, "[green::]+ %s: %s\n", tview.Escape(c.Path), tview.Escape(c.New))
+		case c.New == "":
+			fmt.Fprintf(v.detail, "[red::]- %s: %s\n", tview.Escape(c.Path), tview.Escape(c.Old))
+		default:
+			fmt.Fprintf(v.detail, "[yellow::]~ %s: [red::]%s[yellow::] → [green::]%s\n",
+				tview.Escape(c.Path), tview.Escape(c.Old), tview.Escape(c.New))
+		}
+	}
+	v.detail.ScrollToBeginning()
+}
+
+// updateHeader refreshes the header with the service and the load status
+func (v *AuditView) updateHeader() {
+	v.header.Clear()
+
+	v.header.AddLabelValueRow(0, "Service", v.serviceName)
+	v.header.AddLabelValueRow(1, "Region", v.region)
+	v.header.AddLabelValueRow(2, "Changes", fmt.Sprintf("%d in the last 30 days", len(v.events)))
+
+	v.header.AddSeparator(2, 4)
+
+	status := "Admin Activity audit logs of run.googleapis.com"
+	switch {
+	case v.loading:
+		status = "[yellow::]Loading audit logs..."
+	case v.err != nil:
+		status = fmt.Sprintf("[red::]Failed to load audit logs: %s", tview.Escape(v.err.Error()))
+	case len(v.events) >= auditMaxEntries:
+		status = fmt.Sprintf("Showing the latest %d changes", auditMaxEntries)
+	}
+	v.header.AddSection(0, 3, "Keyboard Shortcuts", "Enter(Diff) r(Refresh) Esc(Back)")
+	v.header.AddSection(1, 3, "Status", status)
+}
+
+// close stops loading, releases the provider and returns to the main view
+func (v *AuditView) close() {
+	v.cancel()
+	closeProvider(v.provider)
+	v.app.ReturnToMain()
+}
+
+// methodColor returns the color of an Admin API method
+func methodColor(method string) tcell.Color {
+	switch method {
+	case "DeleteService", "DeleteRevision":
+		return tcell.ColorRed
+	case "SetIamPolicy":
+		return tcell.ColorYellow
+	case "CreateService":
+		return tcell.ColorGreen
+	}
+	return tcell.ColorAqua
+}
@@ -1,29 +1,53 @@
 package views
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/derailed/tcell/v2"
 	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/alerts"
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/buildinfo"
+	"github.com/lpmourato/c9s/internal/cache"
 	"github.com/lpmourato/c9s/internal/config"
 	"github.com/lpmourato/c9s/internal/datasource"
+	"github.com/lpmourato/c9s/internal/logging"
 	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
 	"github.com/lpmourato/c9s/internal/ui/tui"
 )
 
 // CloudRunView represents the Cloud Run services view
 type CloudRunView struct {
 	*tui.Table
-	app          *tui.App
-	headerTable  *tui.HeaderTable
-	commandInput *tui.CommandInput
-	config       *config.CloudRunConfig
-	dataSource   datasource.DataSource
-	services     []model.Service
-	filter       string // Current service name filter
+	app           *tui.App
+	headerTable   *tui.HeaderTable
+	commandInput  *tui.CommandInput
+	config        *config.CloudRunConfig
+	dataSource    datasource.DataSource
+	services      []model.Service
+	filter        string          // Current service name filter
+	marked        map[string]bool // Services marked for a merged log view, by serviceKey
+	alertRules    []*alerts.Rule  // Alert rules evaluated by the log views
+	failedRegions []string        // Regions whose services could not be listed
+
+	// Optional cold starts column, loaded from the system logs by serviceKey
+	showColdStarts    bool
+	coldStarts        map[string]analytics.ColdStartSummary
+	coldStartWindows  map[string]time.Duration
+	coldStartErrs     map[string]error
+	cancelColdStarts  context.CancelFunc
+	coldStartProvider model.LogProvider
 }
 
+// coldStartsColumn is the title of the optional cold starts column
+const coldStartsColumn = "Cold Starts (24h)"
+
 // Verify CloudRunView implements CommandHandler interface
 var _ tui.CommandHandler = (*CloudRunView)(nil)
 
@@ -57,7 +81,9 @@ func (v *CloudRunView) HandleProject(project string) error {
 	}
 
 	// Update view with new project and data source
+	v.stopColdStarts()
 	v.config.ProjectID = project
+	v.config.CacheServices = true
 	v.dataSource = newDS
 
 	// Reload services for the new project
@@ -74,7 +100,7 @@ func (v *CloudRunView) HandleService(service string) error {
 	v.filter = service
 	// Clear and reload the table with the filter
 	v.Clear()
-	v.SetColumns([]string{"Name", "Region", "URL", "Status", "Last Deploy", "Traffic"})
+	v.SetColumns(v.columns())
 
 	// Apply filter and update table
 	rowIndex := 1 // Skip header
@@ -99,6 +125,105 @@ func (v *CloudRunView) HandleClear() error {
 	return v.HandleService("") // Reuse service handler with empty filter
 }
 
+// HandleRequests implements CommandHandler. It shows the requests of the named
+// service, or of the selected service when no name is given.
+func (v *CloudRunView) HandleRequests(service string) error {
+	name, region, ok := v.findService(service)
+	if !ok {
+		return fmt.Errorf("service %s not found", service)
+	}
+
+	provider, err := v.dataSource.NewLogProvider()
+	if err != nil {
+		v.app.ShowError(fmt.Sprintf("Failed to open requests: %v", err))
+		return err
+	}
+
+	requestsView := NewRequestsView(v.app, provider, v.config.ProjectID, name, region)
+	go requestsView.StreamRequests()
+
+	v.app.SwitchToView(requestsView)
+	return nil
+}
+
+// HandleAudit implements CommandHandler. It shows the changes made to the
+// named service, or to the selected service when no name is given.
+func (v *CloudRunView) HandleAudit(service string) error {
+	name, region, ok := v.findService(service)
+	if !ok {
+		return fmt.Errorf("service %s not found", service)
+	}
+
+	provider, err := v.dataSource.NewLogProvider()
+	if err != nil {
+		v.app.ShowError(fmt.Sprintf("Failed to open audit logs: %v", err))
+		return err
+	}
+
+	auditView := NewAuditView(v.app, provider, name, region)
+	auditView.Load()
+
+	v.app.SwitchToView(auditView)
+	return nil
+}
+
+// HandleDoctor implements CommandHandler. It checks the environment of the
+// current project and region, or of all regions.
+func (v *CloudRunView) HandleDoctor() error {
+	regions := datasource.Regions
+	if v.config.Region != "" {
+		regions = []string{v.config.Region}
+	}
+
+	doctorView := NewDoctorView(v.app, v.dataSource.NewDiagnostics(), v.config.ProjectID, regions)
+	doctorView.Run()
+
+	v.app.SwitchToView(doctorView)
+	return nil
+}
+
+// HandleOpenLogs implements CommandHandler. It opens a log view of the
+// entries of a log file or directory.
+func (v *CloudRunView) HandleOpenLogs(path string) error {
+	provider, err := logging.NewFileLogProvider(path)
+	if err != nil {
+		v.app.ShowError(fmt.Sprintf("Failed to open logs: %v", err))
+		return err
+	}
+
+	logView := NewFileLogView(v.app, provider)
+	logView.SetBufferSize(v.config.LogBufferSize)
+	logView.SetAlertRules(v.alertRules)
+	go logView.StreamLogs()
+
+	v.app.SwitchToView(logView)
+	return nil
+}
+
+// SetAlertRules sets the alert rules evaluated while the log views stream
+func (v *CloudRunView) SetAlertRules(rules []*alerts.Rule) {
+	v.alertRules = rules
+}
+
+// findService returns the name and region of the named service, or of the
+// selected service when name is empty
+func (v *CloudRunView) findService(name string) (string, string, bool) {
+	if name == "" {
+		row, _ := v.GetSelection()
+		if row == 0 {
+			return "", "", false // Header row
+		}
+		return v.GetCell(row, 0).Text, v.GetCell(row, 1).Text, true
+	}
+
+	for _, svc := range v.services {
+		if svc.GetName() == name && (v.config.Region == "" || svc.GetRegion() == v.config.Region) {
+			return svc.GetName(), svc.GetRegion(), true
+		}
+	}
+	return "", "", false
+}
+
 // HandleQuit implements CommandHandler
 func (v *CloudRunView) HandleQuit() {
 	v.app.Stop()
@@ -120,16 +245,17 @@ func NewCloudRunView(app *tui.App, cfg *config.CloudRunConfig, ds datasource.Dat
 		headerTable: headerTable,
 		config:      cfg,
 		dataSource:  ds,
+		marked:      make(map[string]bool),
 	}
 
 	// Set up the table columns and style
-	view.SetColumns([]string{"Name", "Region", "URL", "Status", "Last Deploy", "Traffic"})
+	view.SetColumns(view.columns())
 	view.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
 
 	// Create main content flex (header + table)
 	mainFlex := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(headerTable, 5, 0, false).
+		AddItem(headerTable, 6, 0, false).
 		AddItem(table, 0, 1, true)
 
 	// Create command container with keyboard handling
@@ -150,6 +276,20 @@ func NewCloudRunView(app *tui.App, cfg *config.CloudRunConfig, ds datasource.Dat
 		return nil
 	})
 
+	// Space marks services whose logs are merged into one view
+	keyHandler.RegisterRuneBinding(' ', tui.ActionToggleMark)
+	keyHandler.RegisterHandler(tui.ActionToggleMark, func() error {
+		view.toggleMark()
+		return nil
+	})
+
+	// C shows the cold starts of the last 24 hours of each service
+	keyHandler.RegisterRuneBinding('c', tui.ActionToggleColdStarts)
+	keyHandler.RegisterRuneBinding('C', tui.ActionToggleColdStarts)
+	keyHandler.RegisterHandler(tui.ActionToggleColdStarts, func() error {
+		return view.toggleColdStarts()
+	})
+
 	keyHandler.RegisterHandler(tui.ActionShowDeploymentDetails, func() error {
 		view.showDeploymentDetails()
 		return nil
@@ -175,7 +315,9 @@ func NewCloudRunView(app *tui.App, cfg *config.CloudRunConfig, ds datasource.Dat
 		if view.commandInput.IsVisible() {
 			view.commandInput.Hide()
 			keyHandler.SetContext(tui.ContextMain)
+			return nil
 		}
+		view.clearMarks()
 		return nil
 	})
 
@@ -217,13 +359,24 @@ func (v *CloudRunView) loadServices() error {
 	} else {
 		v.services, err = v.dataSource.GetServices()
 	}
-	if err != nil {
+	// The services of the regions that could be listed are still shown
+	var regionErr *datasource.RegionError
+	if err != nil && !errors.As(err, &regionErr) {
 		return err
 	}
+	if v.config.CacheServices {
+		// The cache only serves shell completion, so failing to update it is ignored
+		_ = cache.Record(v.config.ProjectID, datasource.ListedRegions(v.config.Region, err), v.services)
+	}
+	v.failedRegions = nil
+	if regionErr != nil {
+		v.failedRegions = regionErr.Regions()
+	}
+	v.updateHeader()
 
 	// Clear and reload table
 	v.Clear()
-	v.SetColumns([]string{"Name", "Region", "URL", "Status", "Last Deploy", "Traffic"})
+	v.SetColumns(v.columns())
 
 	for i, svc := range v.services {
 		if v.filter == "" || strings.Contains(strings.ToLower(svc.GetName()), strings.ToLower(v.filter)) {
@@ -234,62 +387,277 @@ func (v *CloudRunView) loadServices() error {
 	if len(v.services) > 0 {
 		v.Select(1, 0)
 	}
+	if v.showColdStarts {
+		v.loadColdStarts()
+	}
 	return nil
 }
 
+// columns returns the columns of the services table
+func (v *CloudRunView) columns() []string {
+	if !v.showColdStarts {
+		return output.ServiceColumns
+	}
+	return append(append([]string(nil), output.ServiceColumns...), coldStartsColumn)
+}
+
 // updateServiceRow updates a single row in the table with service data
 func (v *CloudRunView) updateServiceRow(row int, svc model.Service) {
+	text := output.ServiceRow(svc, time.Local)
 	cells := []tui.TableCell{
 		{
-			Text:      svc.GetName(),
+			Text:      text[0],
 			Expansion: 1,
 		},
 		{
-			Text:      svc.GetRegion(),
+			Text:      text[1],
 			Expansion: 1,
 		},
 		{
-			Text:      svc.GetURL(),
+			Text:      text[2],
 			Expansion: 2,
 		},
 		{
-			Text:      svc.GetStatus(),
+			Text:      text[3],
 			TextColor: tui.StatusColor(svc.GetStatus()),
 			Expansion: 1,
 		},
 		{
-			Text:      svc.GetLastDeploy().Local().Format("2006-01-02 15:04:05 MST"),
+			Text:      text[4],
 			Expansion: 1,
 			Align:     tview.AlignRight,
 		},
 		{
-			Text:      svc.GetTraffic(),
+			Text:      text[5],
 			TextColor: tui.TrafficColor(svc.GetTraffic()),
 			Expansion: 2,
 		},
 	}
+	if v.showColdStarts {
+		cells = append(cells, v.coldStartCell(svc))
+	}
 	v.AddStyledRow(row, cells)
+	v.styleMark(row)
+}
+
+// coldStartCell returns the cold starts cell of a service
+func (v *CloudRunView) coldStartCell(svc model.Service) tui.TableCell {
+	key := serviceKey(svc.GetName(), svc.GetRegion())
+	cell := tui.TableCell{Text: "...", TextColor: tcell.ColorGray, Expansion: 1, Align: tview.AlignRight}
+	if err := v.coldStartErrs[key]; err != nil {
+		cell.Text, cell.TextColor = "error", tcell.ColorRed
+	} else if summary, ok := v.coldStarts[key]; ok {
+		cell.Text = formatColdStarts(summary, v.coldStartWindows[key])
+		cell.TextColor = tcell.GetColor(coldStartColor(summary.PerHour))
+	}
+	return cell
+}
+
+// toggleColdStarts shows or hides the cold starts column, loading the cold
+// starts when it is shown
+func (v *CloudRunView) toggleColdStarts() error {
+	v.showColdStarts = !v.showColdStarts
+	if v.showColdStarts {
+		v.loadColdStarts()
+	} else {
+		v.stopColdStarts()
+	}
+
+	row, _ := v.GetSelection()
+	if err := v.HandleService(v.filter); err != nil {
+		return err
+	}
+	if row > 0 && row < v.GetRowCount() {
+		v.Select(row, 0)
+	}
+	return nil
+}
+
+// loadColdStarts detects the cold starts of every service in the background,
+// a few services at a time, filling the cold starts column as they are found
+func (v *CloudRunView) loadColdStarts() {
+	if v.cancelColdStarts != nil {
+		v.cancelColdStarts()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	v.cancelColdStarts = cancel
+	v.coldStarts = make(map[string]analytics.ColdStartSummary)
+	v.coldStartWindows = make(map[string]time.Duration)
+	v.coldStartErrs = make(map[string]error)
+
+	// The provider is kept until the cold starts are hidden
+	var providerErr error
+	if v.coldStartProvider == nil {
+		v.coldStartProvider, providerErr = v.dataSource.NewLogProvider()
+	}
+	provider := v.coldStartProvider
+
+	services := v.services
+	queue := make(chan model.Service)
+	go func() {
+		defer close(queue)
+		for _, svc := range services {
+			select {
+			case queue <- svc:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < coldStartWorkers; i++ {
+		go func() {
+			for svc := range queue {
+				svc := svc
+				var starts []analytics.ColdStart
+				var window time.Duration
+				err := providerErr
+				if err == nil {
+					starts, window, err = loadColdStarts(ctx, provider, svc.GetName(), svc.GetRegion(), false)
+				}
+				var summary analytics.ColdStartSummary
+				if summaries := analytics.SummarizeColdStartsByService(starts, window); len(summaries) > 0 {
+					summary = summaries[0]
+				}
+				if ctx.Err() != nil {
+					return
+				}
+
+				v.app.QueueUpdateDraw(func() {
+					if ctx.Err() != nil {
+						return
+					}
+					key := serviceKey(svc.GetName(), svc.GetRegion())
+					if err != nil {
+						v.coldStartErrs[key] = err
+					} else {
+						v.coldStarts[key] = summary
+						v.coldStartWindows[key] = window
+					}
+					v.updateColdStartCell(svc)
+				})
+			}
+		}()
+	}
+}
+
+// stopColdStarts cancels the loading of the cold starts and releases their provider
+func (v *CloudRunView) stopColdStarts() {
+	if v.cancelColdStarts != nil {
+		v.cancelColdStarts()
+		v.cancelColdStarts = nil
+	}
+	if v.coldStartProvider != nil {
+		closeProvider(v.coldStartProvider)
+		v.coldStartProvider = nil
+	}
+}
+
+// updateColdStartCell refreshes the cold starts cell of a service row
+func (v *CloudRunView) updateColdStartCell(svc model.Service) {
+	if !v.showColdStarts {
+		return
+	}
+	for row := 1; row < v.GetRowCount(); row++ {
+		if v.GetCell(row, 0).Text == svc.GetName() && v.GetCell(row, 1).Text == svc.GetRegion() {
+			v.updateServiceRow(row, svc)
+			return
+		}
+	}
+}
+
+// serviceKey identifies a service row by name and region
+func serviceKey(name, region string) string {
+	return name + "/" + region
+}
+
+// toggleMark marks or unmarks the selected service for a merged log view
+func (v *CloudRunView) toggleMark() {
+	row, _ := v.GetSelection()
+	if row == 0 {
+		return // Header row
+	}
+
+	key := serviceKey(v.GetCell(row, 0).Text, v.GetCell(row, 1).Text)
+	if v.marked[key] {
+		delete(v.marked, key)
+	} else {
+		v.marked[key] = true
+	}
+	v.styleMark(row)
+
+	// Move down so several services can be marked in a row
+	if row+1 < v.GetRowCount() {
+		v.Select(row+1, 0)
+	}
+}
+
+// clearMarks unmarks all services
+func (v *CloudRunView) clearMarks() {
+	if len(v.marked) == 0 {
+		return
+	}
+	v.marked = make(map[string]bool)
+	for row := 1; row < v.GetRowCount(); row++ {
+		v.styleMark(row)
+	}
+}
+
+// styleMark highlights a row when its service is marked
+func (v *CloudRunView) styleMark(row int) {
+	background := tcell.ColorDefault
+	if v.marked[serviceKey(v.GetCell(row, 0).Text, v.GetCell(row, 1).Text)] {
+		background = tcell.ColorDarkSlateGray
+	}
+	for col := 0; col < v.GetColumnCount(); col++ {
+		v.GetCell(row, col).SetBackgroundColor(background)
+	}
+}
+
+// markedServices returns the marked services, in table order
+func (v *CloudRunView) markedServices() []model.Service {
+	var marked []model.Service
+	for _, svc := range v.services {
+		if v.marked[serviceKey(svc.GetName(), svc.GetRegion())] {
+			marked = append(marked, svc)
+		}
+	}
+	return marked
 }
 
 // updateHeader updates the header content with session info
 func (v *CloudRunView) updateHeader() {
 	v.headerTable.Clear()
 
-	// Left column: Project and Region info
+	// Left column: Project, Region and version info
 	v.headerTable.AddLabelValueRow(0, "Project ID", v.config.ProjectID)
 	v.headerTable.AddLabelValueRow(1, "Region", v.config.Region)
+	v.headerTable.AddLabelValueRow(2, "c9s Version", v.version())
 
 	// Add separator
-	v.headerTable.AddSeparator(2, 3)
+	v.headerTable.AddSeparator(2, 4)
 
 	// Right column: Shortcuts and Commands
-	v.headerTable.AddSection(0, 3, "Keyboard Shortcuts", "Enter(Logs), D(Service Details)")
-	v.headerTable.AddSection(1, 3, "Commands", ":region(rg) :project(proj) :service(svc) :clear(cl) :quit(q)")
+	v.headerTable.AddSection(0, 3, "Keyboard Shortcuts", "Enter(Logs), Space(Mark for Merged Logs), D(Service Details), C(Cold Starts)")
+	v.headerTable.AddSection(1, 3, "Commands", ":region(rg) :project(proj) :service(svc) :requests(req) :audit(au) :doctor(dr) :clear(cl) :quit(q)")
+	if len(v.failedRegions) > 0 {
+		v.headerTable.AddSection(2, 3, "Failed Regions", "[red::]"+strings.Join(v.failedRegions, ", ")+"[gray::] (r to retry, :doctor to diagnose)")
+	}
 
 	// Command input/hint row
 	cmdHint := "Type Shift+: for commands"
-	v.headerTable.AddCommandHint(2, cmdHint, false)
-} // showServiceDescription displays detailed information about the selected service
+	v.headerTable.AddCommandHint(3, cmdHint, false)
+}
+
+// version returns the version of c9s shown in the header
+func (v *CloudRunView) version() string {
+	if v.config.Version == "" {
+		return buildinfo.Unknown
+	}
+	return v.config.Version
+}
+
+// showServiceDescription displays detailed information about the selected service
 func (v *CloudRunView) showServiceDescription() {
 	row, _ := v.GetSelection()
 	if row == 0 {
@@ -300,8 +668,13 @@ func (v *CloudRunView) showServiceDescription() {
 	v.SetTitle(fmt.Sprintf(" Cloud Run Services - %s ", serviceName))
 }
 
-// showLogs displays logs for the selected service
+// showLogs displays logs for the selected service, or the merged logs of the marked services
 func (v *CloudRunView) showLogs() {
+	if len(v.marked) > 0 {
+		v.showMergedLogs()
+		return
+	}
+
 	row, _ := v.GetSelection()
 	if row == 0 {
 		return // Header row
@@ -318,6 +691,14 @@ func (v *CloudRunView) showLogs() {
 		return
 	}
 
+	logView.SetBufferSize(v.config.LogBufferSize)
+	logView.SetAlertRules(v.alertRules)
+
+	// Apply the time range requested on the command line
+	if err := logView.SetTimeRange(v.config.LogSince, v.config.LogUntil); err != nil {
+		v.app.ShowError(fmt.Sprintf("Invalid log time range: %v", err))
+	}
+
 	// Start streaming immediately since NewLogView now sets up the streamer
 	go logView.StreamLogs()
 
@@ -325,6 +706,52 @@ func (v *CloudRunView) showLogs() {
 	v.app.SwitchToView(logView)
 }
 
+// showMergedLogs displays the logs of all marked services in one view
+func (v *CloudRunView) showMergedLogs() {
+	services := v.markedServices()
+
+	// Services with the same name in several regions are told apart by region
+	names := make(map[string]int)
+	for _, svc := range services {
+		names[svc.GetName()]++
+	}
+
+	provider := v.dataSource.GetProvider()
+	sources := make([]logging.MergeSource, 0, len(services))
+	for _, svc := range services {
+		streamer, err := provider.NewLogStreamer(svc.GetName(), svc.GetRegion())
+		if err != nil {
+			// Release the streamers opened so far
+			logging.NewMergedStreamer(sources, 0).Close()
+			v.app.ShowError(fmt.Sprintf("Failed to open logs of %s: %v", svc.GetName(), err))
+			return
+		}
+		name := svc.GetName()
+		if names[name] > 1 {
+			name += "@" + svc.GetRegion()
+		}
+		sources = append(sources, logging.MergeSource{Name: name, Streamer: streamer})
+	}
+
+	logView := NewMergedLogView(v.app, sources)
+	logView.SetBufferSize(v.config.LogBufferSize)
+	logView.SetAlertRules(v.alertRules)
+
+	// Trace lookups query the logs of the whole project
+	logView.SetTraceProviderFunc(v.dataSource.NewLogProvider)
+
+	go logView.StreamLogs()
+
+	v.app.SwitchToView(logView)
+}
+
+// closeProvider releases the client held by a log provider, if any
+func closeProvider(provider model.LogProvider) {
+	if c, ok := provider.(io.Closer); ok {
+		c.Close()
+	}
+}
+
 // showDeploymentDetails displays deployment details for the selected service
 func (v *CloudRunView) showDeploymentDetails() {
 	row, _ := v.GetSelection()
@@ -341,6 +768,21 @@ func (v *CloudRunView) showDeploymentDetails() {
 	// Start loading details using the provider from the data source
 	deployView.LoadDetails(v.dataSource.GetProvider())
 
+	// Cold starts are detected from the system and request logs
+	if provider, err := v.dataSource.NewLogProvider(); err != nil {
+		deployView.SetColdStartsError(err)
+	} else {
+		deployView.LoadColdStarts(provider)
+	}
+
+	// Request analytics are computed from the request log of the service
+	panel := NewRequestAnalyticsPanel(v.app)
+	if provider, err := v.dataSource.NewLogProvider(); err != nil {
+		panel.SetError(err)
+	} else {
+		panel.Load(provider, v.config.ProjectID, serviceName, region)
+	}
+
 	// Switch to deployment view
-	v.app.SwitchToView(deployView)
+	v.app.SwitchToView(NewServiceOverview(deployView, panel))
 }
@@ -0,0 +1,152 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+const (
+	// coldStartHistory is how far back cold starts are detected
+	coldStartHistory = 24 * time.Hour
+	// coldStartMaxEntries bounds the number of system log entries loaded
+	coldStartMaxEntries = 2000
+	coldStartPageSize   = 500
+	// coldStartWorkers bounds the number of services whose cold starts are
+	// loaded at the same time
+	coldStartWorkers = 4
+	// coldStartMaxInstances bounds the number of new instances whose first
+	// request is looked up, latest first
+	coldStartMaxInstances = 20
+	// coldStartQuery selects the system log entries of instance starts and
+	// startup probe results
+	coldStartQuery = `textPayload:"Starting new instance" OR textPayload:"probe succeeded"`
+)
+
+// loadColdStarts detects the cold starts of a service during the last 24
+// hours from its system logs. With firstRequests, the request log is also
+// searched for the first request of the latest new instances. It returns the
+// span the loaded logs cover, shorter than 24 hours when the number of
+// entries reached coldStartMaxEntries.
+func loadColdStarts(ctx context.Context, provider model.LogProvider, serviceName, region string, firstRequests bool) ([]analytics.ColdStart, time.Duration, error) {
+	now := time.Now()
+	since := now.Add(-coldStartHistory)
+	filter := logging.QueryFilter(provider, model.CloudProviderOptions{
+		ServiceName: serviceName,
+		Region:      region,
+		LogIDs:      []string{logging.SystemLogID},
+		Query:       coldStartQuery,
+		Since:       since,
+	})
+	entries, err := fetchNewest(ctx, provider, filter, coldStartMaxEntries, coldStartPageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	window := coveredSpan(entries, coldStartMaxEntries, now, coldStartHistory)
+
+	starts := analytics.DetectColdStarts(entries)
+	if !firstRequests {
+		return starts, window, nil
+	}
+
+	looked := 0
+	for i := len(starts) - 1; i >= 0 && looked < coldStartMaxInstances; i-- {
+		start := starts[i]
+		if start.InstanceID == "" {
+			continue
+		}
+		looked++
+		filter := logging.QueryFilter(provider, model.CloudProviderOptions{
+			ServiceName: serviceName,
+			Region:      region,
+			LogIDs:      []string{logging.RequestsLogID},
+			Query:       fmt.Sprintf(`labels.instanceId="%s"`, start.InstanceID),
+			Since:       start.StartedAt,
+		})
+		page, err := provider.FetchPage(ctx, model.LogPageRequest{Filter: filter, PageSize: 1})
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, page.Entries...)
+	}
+	return analytics.DetectColdStarts(entries), window, nil
+}
+
+// coveredSpan returns the span covered by entries loaded newest first: the
+// whole history, or back to the oldest entry when max entries were loaded
+func coveredSpan(entries []model.LogEntry, max int, now time.Time, history time.Duration) time.Duration {
+	if len(entries) < max {
+		return history
+	}
+	span := now.Sub(entries[len(entries)-1].Timestamp)
+	if span <= 0 || span > history {
+		return history
+	}
+	// Rates over a few seconds would be meaningless
+	if span < time.Minute {
+		span = time.Minute
+	}
+	return span
+}
+
+// formatSpan returns a short description of a span, e.g. "24h" or "35m"
+func formatSpan(span time.Duration) string {
+	if span >= time.Hour {
+		return fmt.Sprintf("%dh", int(span.Round(time.Hour)/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int(span.Round(time.Minute)/time.Minute))
+}
+
+// fetchNewest loads up to max entries matching filter, newest first
+func fetchNewest(ctx context.Context, provider model.LogProvider, filter string, max, pageSize int) ([]model.LogEntry, error) {
+	var entries []model.LogEntry
+	token := ""
+	for len(entries) < max {
+		page, err := provider.FetchPage(ctx, model.LogPageRequest{
+			Filter:      filter,
+			PageSize:    pageSize,
+			PageToken:   token,
+			NewestFirst: true,
+		})
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, page.Entries...)
+		if token = page.NextPageToken; token == "" {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// formatColdStarts returns the short description of a cold start summary
+// used in the services table, e.g. "12 (0.5/h) 2.1s". Summaries of fewer
+// than 24 hours of logs tell their span, e.g. "40 in 3h (13.3/h) 2.1s".
+func formatColdStarts(summary analytics.ColdStartSummary, window time.Duration) string {
+	if summary.Count == 0 {
+		return "0"
+	}
+	text := fmt.Sprintf("%d (%.1f/h)", summary.Count, summary.PerHour)
+	if window > 0 && window < coldStartHistory {
+		text = fmt.Sprintf("%d in %s (%.1f/h)", summary.Count, formatSpan(window), summary.PerHour)
+	}
+	if summary.StartupP50 > 0 {
+		text += " " + formatLatency(summary.StartupP50)
+	}
+	return text
+}
+
+// coldStartColor returns the color of a number of cold starts per hour
+func coldStartColor(perHour float64) string {
+	switch {
+	case perHour >= 5:
+		return "red"
+	case perHour >= 1:
+		return "yellow"
+	}
+	return "green"
+}
@@ -0,0 +1,60 @@
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestCoveredSpan(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	// entries returns n entries, newest first, the oldest at now - oldest
+	entries := func(n int, oldest time.Duration) []model.LogEntry {
+		list := make([]model.LogEntry, n)
+		for i := range list {
+			list[i].Timestamp = now.Add(-oldest * time.Duration(i+1) / time.Duration(n))
+		}
+		return list
+	}
+
+	tests := []struct {
+		name    string
+		entries []model.LogEntry
+		want    time.Duration
+	}{
+		{"below the limit", entries(3, time.Hour), 24 * time.Hour},
+		{"truncated", entries(4, 3*time.Hour), 3 * time.Hour},
+		{"truncated within seconds", entries(4, 10*time.Second), time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := coveredSpan(tt.entries, 4, now, 24*time.Hour); got != tt.want {
+				t.Errorf("coveredSpan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatColdStarts(t *testing.T) {
+	summary := analytics.ColdStartSummary{Count: 12, PerHour: 0.5, StartupP50: 2100 * time.Millisecond}
+
+	tests := []struct {
+		name    string
+		summary analytics.ColdStartSummary
+		window  time.Duration
+		want    string
+	}{
+		{"none", analytics.ColdStartSummary{}, coldStartHistory, "0"},
+		{"whole history", summary, coldStartHistory, "12 (0.5/h) 2.1s"},
+		{"truncated", analytics.ColdStartSummary{Count: 40, PerHour: 13.3}, 3 * time.Hour, "40 in 3h (13.3/h)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatColdStarts(tt.summary, tt.window); got != tt.want {
+				t.Errorf("formatColdStarts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
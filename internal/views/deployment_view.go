@@ -8,8 +8,10 @@ import (
 
 	"github.com/derailed/tcell/v2"
 	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/analytics"
 	"github.com/lpmourato/c9s/internal/interfaces"
 	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/output"
 )
 
 // DeploymentView shows Cloud Run service deployment details
@@ -18,15 +20,30 @@ type DeploymentView struct {
 	app         interfaces.UIController
 	serviceName string
 	region      string
+	ctx         context.Context
+	cancel      context.CancelFunc
+
+	details *model.ServiceDetails
+	// Cold starts of the last 24 hours, loaded from the logs, and the span
+	// the loaded logs cover
+	coldStarts        []analytics.ColdStart
+	coldStartsWindow  time.Duration
+	coldStartsErr     error
+	coldStartsLoading bool
+	// coldStartsProvider is owned by the view and closed with it
+	coldStartsProvider model.LogProvider
 }
 
 // NewDeploymentView creates a new deployment details view
 func NewDeploymentView(app interfaces.UIController, serviceName, region string) *DeploymentView {
+	ctx, cancel := context.WithCancel(context.Background())
 	v := &DeploymentView{
 		TextView:    tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
 		app:         app,
 		serviceName: serviceName,
 		region:      region,
+		ctx:         ctx,
+		cancel:      cancel,
 	}
 
 	v.SetBorder(true)
@@ -37,6 +54,7 @@ func NewDeploymentView(app interfaces.UIController, serviceName, region string)
 	v.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 		switch event.Key() {
 		case tcell.KeyEscape:
+			v.Close()
 			app.ReturnToMain()
 			return nil
 		case tcell.KeyUp:
@@ -70,232 +88,114 @@ func (v *DeploymentView) LoadDetails(provider model.CloudProvider) {
 		}
 
 		v.app.QueueUpdateDraw(func() {
-			v.Clear()
-			v.displayDetails(details)
+			v.details = details
+			v.render()
 			v.ScrollToBeginning()
 		})
 	}()
 }
 
-func (v *DeploymentView) displayDetails(details *model.ServiceDetails) {
-	// Service Information
-	v.writeSectionHeader("Service Information")
-	v.writeKeyValue("Name", details.Name)
-	v.writeKeyValue("Region", details.Region)
-	v.writeKeyValue("URL", details.URL)
-	v.writeKeyValue("Status", getStatusText(details.Ready))
-	v.writeKeyValue("Creation Time", formatTime(details.CreationTime))
-	v.writeKeyValue("Last Updated", formatTime(details.LastUpdated))
-	if details.Creator != "" {
-		v.writeKeyValue("Creator", details.Creator)
-	}
-	if details.LastModifier != "" {
-		v.writeKeyValue("Last Modifier", details.LastModifier)
-	}
-	v.writeLine("")
-
-	// Service Metadata
-	if details.UID != "" || details.Generation > 0 || len(details.Labels) > 0 {
-		v.writeSectionHeader("Service Metadata")
-		if details.UID != "" {
-			v.writeKeyValue("UID", details.UID)
-		}
-		if details.Generation > 0 {
-			v.writeKeyValue("Generation", fmt.Sprintf("%d", details.Generation))
-		}
-		if details.LaunchStage != "" {
-			v.writeKeyValue("Launch Stage", details.LaunchStage)
-		}
-
-		// Display important labels
-		if len(details.Labels) > 0 {
-			v.writeKeyValue("Labels", "")
-			for k, val := range details.Labels {
-				v.writeIndentedLine(1, "[dim::]%s: [silver::]%s", k, val)
-			}
-		}
-		v.writeLine("")
-	}
-
-	// Container Configuration
-	v.writeSectionHeader("Container Configuration")
-	v.writeKeyValue("Image", details.ContainerImage)
-	if details.ImageDigest != "" {
-		v.writeKeyValue("Image Digest", truncateDigest(details.ImageDigest))
-	}
-	if details.ContainerName != "" {
-		v.writeKeyValue("Container Name", details.ContainerName)
-	}
-	v.writeKeyValue("CPU", details.CPU)
-	v.writeKeyValue("Memory", details.Memory)
-	v.writeKeyValue("Port", fmt.Sprintf("%d", details.Port))
-	if details.ContainerConcurrency > 0 {
-		v.writeKeyValue("Concurrency", fmt.Sprintf("%d", details.ContainerConcurrency))
-	}
-	if details.TimeoutSeconds > 0 {
-		v.writeKeyValue("Timeout", fmt.Sprintf("%ds", details.TimeoutSeconds))
-	}
-	v.writeLine("")
-
-	// Scaling Configuration
-	v.writeSectionHeader("Scaling Configuration")
-	v.writeKeyValue("Minimum Instances", fmt.Sprintf("%d", details.MinInstances))
-	v.writeKeyValue("Maximum Instances", fmt.Sprintf("%d", details.MaxInstances))
-	v.writeLine("")
-
-	// Network & Security
-	if details.ServiceAccount != "" || details.VPCConnector != "" || details.IngressSettings != "" {
-		v.writeSectionHeader("Network & Security")
-		if details.ServiceAccount != "" {
-			v.writeKeyValue("Service Account", details.ServiceAccount)
-		}
-		if details.VPCConnector != "" {
-			v.writeKeyValue("VPC Connector", details.VPCConnector)
-		}
-		if details.VPCEgress != "" {
-			v.writeKeyValue("VPC Egress", details.VPCEgress)
-		}
-		if details.IngressSettings != "" {
-			v.writeKeyValue("Ingress", details.IngressSettings)
-		}
-		if details.ExecutionEnv != "" {
-			v.writeKeyValue("Execution Environment", details.ExecutionEnv)
-		}
-		v.writeKeyValue("CPU Throttling", getBoolText(details.CPUThrottling))
-		v.writeLine("")
-	}
-
-	// Health Checks
-	if details.LivenessProbe != nil || details.ReadinessProbe != nil || details.StartupProbe != nil {
-		v.writeSectionHeader("Health Checks")
-		if details.LivenessProbe != nil {
-			v.displayHealthProbe("Liveness Probe", details.LivenessProbe)
-		}
-		if details.ReadinessProbe != nil {
-			v.displayHealthProbe("Readiness Probe", details.ReadinessProbe)
-		}
-		if details.StartupProbe != nil {
-			v.displayHealthProbe("Startup Probe", details.StartupProbe)
-		}
-		v.writeLine("")
-	}
-
-	// Traffic Configuration
-	if len(details.Traffic) > 0 {
-		v.writeSectionHeader("Traffic Configuration")
-		for _, t := range details.Traffic {
-			status := ""
-			if t.Latest {
-				status = " [green::](latest)"
-			}
-			if t.Tag != "" {
-				status += fmt.Sprintf(" [blue::](%s)", t.Tag)
+// LoadColdStarts detects the cold starts of the last 24 hours of the service
+// from its system and request logs. The view takes ownership of the provider.
+func (v *DeploymentView) LoadColdStarts(provider model.LogProvider) {
+	v.coldStartsLoading = true
+	v.coldStartsProvider = provider
+	ctx := v.ctx
+	go func() {
+		starts, window, err := loadColdStarts(ctx, provider, v.serviceName, v.region, true)
+		v.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
 			}
-			v.writeKeyValue("Revision "+t.RevisionName, fmt.Sprintf("%d%%%s", t.Percent, status))
-		}
-		v.writeLine("")
-	}
+			v.coldStartsLoading = false
+			v.coldStarts = starts
+			v.coldStartsWindow = window
+			v.coldStartsErr = err
+			v.render()
+		})
+	}()
+}
 
-	// Revision Information
-	if details.LatestRevision != "" || details.LatestReadyRevision != "" {
-		v.writeSectionHeader("Revision Information")
-		if details.LatestRevision != "" {
-			v.writeKeyValue("Latest Revision", details.LatestRevision)
-		}
-		if details.LatestReadyRevision != "" {
-			v.writeKeyValue("Latest Ready Revision", details.LatestReadyRevision)
-		}
-		if !details.RevisionCreationTime.IsZero() {
-			v.writeKeyValue("Revision Created", formatTime(details.RevisionCreationTime))
-		}
+// SetColdStartsError shows why the cold starts could not be loaded
+func (v *DeploymentView) SetColdStartsError(err error) {
+	v.coldStartsErr = err
+	v.render()
+}
 
-		// Container statuses
-		if len(details.ContainerStatuses) > 0 {
-			v.writeKeyValue("Container Statuses", "")
-			for _, cs := range details.ContainerStatuses {
-				status := "[red]Not Ready"
-				if cs.Ready {
-					status = "[green::]Ready"
-				}
-				v.writeIndentedLine(1, "[dim::]%s: %s (restarts: %d)", cs.Name, status, cs.RestartCount)
-			}
-		}
-		v.writeLine("")
+// Close stops loading cold starts and releases their provider
+func (v *DeploymentView) Close() {
+	v.cancel()
+	if v.coldStartsProvider != nil {
+		closeProvider(v.coldStartsProvider)
+		v.coldStartsProvider = nil
 	}
+}
 
-	// Environment Variables
-	if len(details.EnvVars) > 0 {
-		v.writeSectionHeader("Environment Variables")
-		for k, val := range details.EnvVars {
-			// Mask sensitive values
-			if isSensitive(k) {
-				val = "********"
-			}
-			v.writeKeyValue(k, val)
-		}
-		v.writeLine("")
+// render shows the loaded details, keeping the scroll position
+func (v *DeploymentView) render() {
+	if v.details == nil {
+		return
 	}
+	row, col := v.GetScrollOffset()
+	v.Clear()
+	v.displayDetails(v.details)
+	v.ScrollTo(row, col)
+}
 
-	// Secrets & Volumes
-	if len(details.Secrets) > 0 || len(details.Volumes) > 0 {
-		v.writeSectionHeader("Secrets & Volumes")
-
-		if len(details.Secrets) > 0 {
-			v.writeKeyValue("Secrets", "")
-			for _, secret := range details.Secrets {
-				v.writeIndentedLine(1, "	[dim::]%s[silver::]%s", secret.Name, secret.MountPath)
-				// TODO: no need for extra loop for instance
-				// for _, item := range secret.Items {
-				// 	fmt.Fprintf(v, "%s as [silver::]%s", item.Key, item.Path)
-				// 	v.writeIndentedLine(1, "	[dim::]%s:[silver::]%s", item.Key, item.Path)
-				// }
-			}
-		}
+// detailsStyle renders the shared service details with tview colors
+var detailsStyle = output.Style{
+	Section: "[orange::b]",
+	Reset:   "[-:-:-]",
+	Key:     "[teal::]",
+	Value:   "[silver::]",
+	Dim:     "[dim::]",
+	Good:    "[green::]",
+	Bad:     "[red::]",
+	Tag:     "[blue::]",
+	Escape:  tview.Escape,
+}
 
-		if len(details.Volumes) > 0 {
-			v.writeKeyValue("Volumes", "")
-			for _, vol := range details.Volumes {
-				readOnlyText := ""
-				if vol.ReadOnly {
-					readOnlyText = " [dim::](read-only)"
-				}
-				// TODO: check missing info
-				v.writeIndentedLine(1, "	[dim::]%s (%s):[silver::]%s%s", vol.Name, vol.VolumeType, vol.MountPath, readOnlyText)
-			}
+func (v *DeploymentView) displayDetails(details *model.ServiceDetails) {
+	dw := output.NewDetailsWriter(detailsStyle, time.Local)
+	dw.After = func(section string) {
+		if section == "Scaling Configuration" {
+			v.displayColdStarts(details)
 		}
-		v.writeLine("")
 	}
+	dw.Write(v, output.MaskSecrets(details))
+}
 
-	// Service Conditions
-	if len(details.RevisionConditions) > 0 {
-		v.writeSectionHeader("Service Conditions")
-		for _, cond := range details.RevisionConditions {
-			status := "[red::]" + cond.Status + ""
-			if cond.Status == "True" {
-				status = "[green::]" + cond.Status + ""
+// displayColdStarts shows the cold starts of each revision
+func (v *DeploymentView) displayColdStarts(details *model.ServiceDetails) {
+	v.writeSectionHeader("Cold Starts (last 24h)")
+	switch {
+	case v.coldStartsErr != nil:
+		v.writeIndentedLine(1, "[red::]Failed to load cold starts: %s", tview.Escape(v.coldStartsErr.Error()))
+	case v.coldStartsLoading:
+		v.writeIndentedLine(1, "[yellow::]Loading system logs...")
+	case len(v.coldStarts) == 0:
+		v.writeIndentedLine(1, "[gray::]No cold starts")
+	default:
+		if v.coldStartsWindow < coldStartHistory {
+			v.writeIndentedLine(1, "[gray::]Based on the latest %d system log entries, covering %s",
+				coldStartMaxEntries, formatSpan(v.coldStartsWindow))
+		}
+		for _, summary := range analytics.SummarizeColdStartsByRevision(v.coldStarts, v.coldStartsWindow) {
+			v.writeKeyValue("Revision "+summary.Name, fmt.Sprintf("[%s::]%d[silver::] (%.1f/h), last %s",
+				coldStartColor(summary.PerHour), summary.Count, summary.PerHour, formatTime(summary.LastStart)))
+			if summary.StartupP50 > 0 {
+				v.writeIndentedLine(2, "[dim::]Startup: p50 %s, p95 %s",
+					formatLatency(summary.StartupP50), formatLatency(summary.StartupP95))
 			}
-			v.writeKeyValue(cond.Type, fmt.Sprintf("%s (%s)", status, cond.Reason))
-			if cond.Message != "" {
-				v.writeIndentedLine(2, "[dim::]%s", cond.Message)
+			if summary.FirstRequestP50 > 0 {
+				v.writeIndentedLine(2, "[dim::]First request: p50 %s", formatLatency(summary.FirstRequestP50))
 			}
 		}
-		v.writeLine("")
-	}
-
-	// Additional Information
-	if details.LogURL != "" || details.SelfLink != "" {
-		v.writeSectionHeader("Additional Information")
-		if details.LogURL != "" {
-			v.writeKeyValue("Logs", details.LogURL)
-		}
-		if details.SelfLink != "" {
-			v.writeKeyValue("Self Link", details.SelfLink)
-		}
-		if details.OperationID != "" {
-			v.writeKeyValue("Operation ID", details.OperationID)
+		if details.MinInstances == 0 {
+			v.writeIndentedLine(1, "[gray::]Minimum instances or startup CPU boost can reduce cold starts")
 		}
-		v.writeLine("")
 	}
+	v.writeLine("")
 }
 
 func (v *DeploymentView) writeSectionHeader(title string) {
@@ -315,57 +215,9 @@ func (v *DeploymentView) writeIndentedLine(indent int, format string, args ...in
 	fmt.Fprintf(v, indentStr+format+"\n", args...)
 }
 
-func (v *DeploymentView) displayHealthProbe(name string, probe *model.HealthProbe) {
-	if probe.HTTPGet != nil {
-		path := probe.HTTPGet.Path
-		if path == "" {
-			path = "/"
-		}
-		v.writeKeyValue(name, fmt.Sprintf("HTTP %s:%d%s (delay: %ds, period: %ds)",
-			probe.HTTPGet.Scheme, probe.HTTPGet.Port, path,
-			probe.InitialDelaySeconds, probe.PeriodSeconds))
-	}
-}
-
-func getStatusText(ready bool) string {
-	if ready {
-		return "[green::]Ready"
-	}
-	return "[red::]Not Ready"
-}
-
-func getBoolText(value bool) string {
-	if value {
-		return "[green::]Enabled"
-	}
-	return "[red::]Disabled"
-}
-
 func formatTime(t time.Time) string {
 	if t.IsZero() {
 		return "N/A"
 	}
-	return t.Local().Format("2006-01-02 15:04:05 MST")
-}
-
-func truncateDigest(digest string) string {
-	if len(digest) > 64 {
-		return digest[:64] + "..."
-	}
-	return digest
-}
-
-func isSensitive(key string) bool {
-	key = strings.ToUpper(key)
-	sensitiveKeys := []string{
-		"PASSWORD", "SECRET", "KEY", "TOKEN", "CREDENTIAL",
-		"AUTH", "PRIVATE", "CERT", "SIGNATURE",
-	}
-
-	for _, k := range sensitiveKeys {
-		if strings.Contains(key, k) {
-			return true
-		}
-	}
-	return false
+	return t.Local().Format(output.DeployTimeLayout)
 }
@@ -0,0 +1,192 @@
+package views
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/doctor"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+// DoctorView runs the environment checks and lists their results, with the
+// fix hint of the selected check
+type DoctorView struct {
+	*tview.Flex
+	table  *tui.Table
+	detail *tview.TextView
+	header *tui.HeaderTable
+	app    *tui.App
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	env       doctor.Environment
+	projectID string
+	regions   []string
+
+	results []doctor.Result
+	running bool
+}
+
+// NewDoctorView creates a view of the checks of a project and its regions
+func NewDoctorView(app *tui.App, env doctor.Environment, projectID string, regions []string) *DoctorView {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v := &DoctorView{
+		Flex:      tview.NewFlex().SetDirection(tview.FlexRow),
+		table:     tui.NewTable(),
+		detail:    tview.NewTextView().SetDynamicColors(true).SetWordWrap(true),
+		header:    tui.NewHeaderTable(),
+		app:       app,
+		ctx:       ctx,
+		cancel:    cancel,
+		env:       env,
+		projectID: projectID,
+		regions:   regions,
+	}
+
+	v.header.SetTitle(" Doctor ")
+	v.table.SetTitle(" Checks ")
+	v.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
+	v.table.SetSelectionChangedFunc(func(row, column int) {
+		v.renderDetail(row)
+	})
+	v.detail.SetBorder(true)
+	v.detail.SetTitle(" Fix ")
+	v.detail.SetTitleAlign(tview.AlignLeft)
+
+	v.AddItem(v.header, 5, 0, false)
+	v.AddItem(v.table, 0, 1, true)
+	v.AddItem(v.detail, 6, 0, false)
+
+	v.setupKeys()
+	v.render()
+
+	return v
+}
+
+// setupKeys sets up the key bindings of the doctor view
+func (v *DoctorView) setupKeys() {
+	keyHandler := tui.NewContextualKeyHandler(v.app)
+	keyHandler.SetContext(tui.ContextDoctorView)
+
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		v.close()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		v.close()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionRefresh, func() error {
+		if !v.running {
+			v.Run()
+		}
+		return nil
+	})
+
+	v.table.SetInputCapture(keyHandler.CreateContextualInputCapture())
+}
+
+// Run runs the checks in the background
+func (v *DoctorView) Run() {
+	v.running = true
+	v.render()
+
+	ctx := v.ctx
+	go func() {
+		results := doctor.Run(ctx, v.env, v.projectID, v.regions)
+		v.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			v.running = false
+			v.results = results
+			v.render()
+		})
+	}()
+}
+
+// render rebuilds the table of results
+func (v *DoctorView) render() {
+	row, _ := v.table.GetSelection()
+	v.table.Clear()
+	v.table.SetColumns([]string{"Status", "Check", "Detail"})
+
+	for i, r := range v.results {
+		color, _ := checkColor(r.Status)
+		v.table.AddStyledRow(i+1, []tui.TableCell{
+			{Text: string(r.Status), TextColor: color, Expansion: 0},
+			{Text: r.Check, TextColor: tcell.ColorWhite, Expansion: 1},
+			{Text: tview.Escape(truncate(r.Detail, 120)), TextColor: tcell.ColorWhite, Expansion: 3},
+		})
+	}
+
+	if row < 1 {
+		row = 1
+	}
+	if row > len(v.results) {
+		row = len(v.results)
+	}
+	v.table.Select(row, 0)
+	v.renderDetail(row)
+	v.updateHeader()
+}
+
+// renderDetail shows the detail and the fix hint of the selected check
+func (v *DoctorView) renderDetail(row int) {
+	v.detail.Clear()
+	i := row - 1
+	if i < 0 || i >= len(v.results) {
+		return
+	}
+	r := v.results[i]
+
+	_, tag := checkColor(r.Status)
+	fmt.Fprintf(v.detail, "[teal::]%s: [%s::]%s[-:-:-] %s\n", r.Check, tag, r.Status, tview.Escape(r.Detail))
+	if r.Hint != "" {
+		fmt.Fprintf(v.detail, "[yellow::]Fix: [silver::]%s\n", tview.Escape(r.Hint))
+	}
+}
+
+// updateHeader refreshes the header with the project and the check summary
+func (v *DoctorView) updateHeader() {
+	v.header.Clear()
+
+	project := v.projectID
+	if project == "" {
+		project = "(not set)"
+	}
+	v.header.AddLabelValueRow(0, "Project ID", project)
+	v.header.AddLabelValueRow(1, "Regions", fmt.Sprintf("%d", len(v.regions)))
+
+	v.header.AddSeparator(2, 3)
+
+	status := "[green::]All checks passed"
+	switch {
+	case v.running:
+		status = "[yellow::]Running checks..."
+	case doctor.Failed(v.results) > 0:
+		status = fmt.Sprintf("[red::]%d of %d checks failed", doctor.Failed(v.results), len(v.results))
+	}
+	v.header.AddSection(0, 3, "Keyboard Shortcuts", "r(Run again) Esc(Back)")
+	v.header.AddSection(1, 3, "Status", status)
+}
+
+// close stops the checks and returns to the main view
+func (v *DoctorView) close() {
+	v.cancel()
+	v.app.ReturnToMain()
+}
+
+// checkColor returns the color of the status of a check, and its color tag
+func checkColor(status doctor.Status) (tcell.Color, string) {
+	switch status {
+	case doctor.Pass:
+		return tcell.ColorGreen, "green"
+	case doctor.Fail:
+		return tcell.ColorRed, "red"
+	}
+	return tcell.ColorGray, "gray"
+}
@@ -0,0 +1,12 @@
+package views
+
+import "github.com/derailed/tview"
+
+// syncUI is a UIController running queued updates synchronously, for the
+// tests and benchmarks of the views
+type syncUI struct{}
+
+func (syncUI) SwitchToView(tview.Primitive) {}
+func (syncUI) ReturnToMain()                {}
+func (syncUI) ShowError(string)             {}
+func (syncUI) QueueUpdateDraw(f func())     { f() }
@@ -0,0 +1,80 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/alerts"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+// alertBannerDuration is how long the banner of an alert stays visible
+const alertBannerDuration = 15 * time.Second
+
+// SetAlertRules sets the alert rules evaluated on the streamed entries.
+// It must be called before streaming starts.
+func (v *LogView) SetAlertRules(rules []*alerts.Rule) {
+	v.alertRules = rules
+	switch {
+	case v.provider != nil:
+		v.streamer = v.newStreamer()
+	case v.streamer != nil:
+		v.streamer = logging.WithStages(v.streamer, v.stages()...)
+	}
+}
+
+// newStreamer creates the log service of the current options
+func (v *LogView) newStreamer() model.LogStreamer {
+	return logging.NewLogService(v.provider, v.opts, v.stages()...)
+}
+
+// stages returns the pipeline stages of a new stream: the evaluation of the
+// alert rules, if any
+func (v *LogView) stages() []logging.Stage {
+	if len(v.alertRules) == 0 {
+		return nil
+	}
+	return []logging.Stage{alerts.NewStage(v.alertRules, v.onAlert)}
+}
+
+// onAlert runs the hooks of an alert and shows it. It is called on the
+// pipeline goroutine.
+func (v *LogView) onAlert(alert alerts.Alert) {
+	go func() {
+		if err := v.notifier.Notify(context.Background(), alert); err != nil {
+			v.app.QueueUpdateDraw(func() {
+				v.notify(fmt.Sprintf("[red::]%s", tview.Escape(err.Error())))
+			})
+		}
+	}()
+	v.app.QueueUpdateDraw(func() {
+		v.showAlert(alert)
+	})
+}
+
+// showAlert shows the banner of an alert above the logs and rings the bell
+func (v *LogView) showAlert(alert alerts.Alert) {
+	v.alertCount++
+	v.banner.SetText(fmt.Sprintf("[white:red:b] ALERT [-:-:-] [red::b]%s[-::-] %d matches in %s: %s",
+		tview.Escape(alert.Rule.Name), alert.Count, alert.Rule.Window, tview.Escape(truncate(alert.Entry.Message, 200))))
+	v.ResizeItem(v.banner, 1, 0)
+	v.updateTitle()
+
+	if tuiApp, ok := v.app.(*tui.App); ok {
+		tuiApp.Beep()
+	}
+
+	// The banner is hidden unless another alert replaced it meanwhile
+	shown := v.alertCount
+	time.AfterFunc(alertBannerDuration, func() {
+		v.app.QueueUpdateDraw(func() {
+			if v.alertCount == shown {
+				v.ResizeItem(v.banner, 0, 0)
+			}
+		})
+	})
+}
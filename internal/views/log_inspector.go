@@ -0,0 +1,352 @@
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+// entryField is a single field of a log entry shown by the inspector
+type entryField struct {
+	// Path of the field in the Logging query language, e.g. jsonPayload.user.id
+	Path  string
+	Value string
+	// Expression matching entries with the same value; empty when the field
+	// cannot be filtered on
+	Filter string
+}
+
+// entrySection is a titled group of entry fields
+type entrySection struct {
+	Title  string
+	Fields []entryField
+}
+
+// LogInspector is a pane showing every field of a log entry. A field can be
+// copied to the clipboard or used to filter the logs.
+type LogInspector struct {
+	*tview.Flex
+	table   *tui.Table
+	raw     *tview.TextView
+	entry   model.LogEntry
+	fields  map[int]entryField // fields by table row
+	showRaw bool
+
+	onFilter func(field entryField)
+	onClose  func()
+	notify   func(msg string)
+}
+
+// NewLogInspector creates an inspector pane. onFilter is called with the field
+// to filter the logs on and onClose when the pane is closed.
+func NewLogInspector(app *tui.App, onFilter func(field entryField), onClose func(), notify func(msg string)) *LogInspector {
+	i := &LogInspector{
+		Flex:     tview.NewFlex().SetDirection(tview.FlexRow),
+		table:    tui.NewTable(),
+		raw:      tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		onFilter: onFilter,
+		onClose:  onClose,
+		notify:   notify,
+	}
+
+	i.table.SetBorders(false)
+	i.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
+	i.raw.SetBorder(true)
+	i.raw.SetTitleAlign(tview.AlignLeft)
+	i.AddItem(i.table, 0, 1, true)
+
+	keyHandler := tui.NewContextualKeyHandler(app)
+	keyHandler.SetContext(tui.ContextLogInspector)
+
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		i.onClose()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		i.onClose()
+		return nil
+	})
+
+	// Enter filters the logs by the selected field value
+	keyHandler.RegisterHandler(tui.ActionEnter, func() error {
+		if field, ok := i.selectedField(); ok {
+			if field.Filter == "" {
+				i.notify(fmt.Sprintf("[yellow::]Cannot filter on %s", field.Path))
+				return nil
+			}
+			i.onFilter(field)
+		}
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('y', tui.ActionCopyValue)
+	keyHandler.RegisterHandler(tui.ActionCopyValue, func() error {
+		if field, ok := i.selectedField(); ok {
+			i.copy(field.Path, field.Value)
+		}
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('Y', tui.ActionCopyEntry)
+	keyHandler.RegisterHandler(tui.ActionCopyEntry, func() error {
+		i.copy("entry", entryJSON(i.entry))
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('J', tui.ActionToggleRaw)
+	keyHandler.RegisterHandler(tui.ActionToggleRaw, func() error {
+		i.toggleRaw(app)
+		return nil
+	})
+
+	capture := keyHandler.CreateContextualInputCapture()
+	i.table.SetInputCapture(capture)
+	i.raw.SetInputCapture(capture)
+
+	return i
+}
+
+// SetEntry shows the fields of an entry
+func (i *LogInspector) SetEntry(entry model.LogEntry) {
+	i.entry = entry
+	i.fields = make(map[int]entryField)
+	i.table.Clear()
+	i.table.SetTitle(fmt.Sprintf(" Log Entry %s | Enter(Filter) y(Copy Value) Y(Copy Entry) J(JSON) Esc(Close) ", entry.InsertID))
+
+	row := 0
+	for _, section := range entrySections(entry) {
+		if len(section.Fields) == 0 {
+			continue
+		}
+		i.table.SetCell(row, 0, tui.NewTableCell(section.Title).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false))
+		row++
+		for _, field := range section.Fields {
+			i.table.SetCell(row, 0, tui.NewTableCell(field.Path).
+				SetTextColor(tcell.ColorAqua).
+				SetExpansion(0))
+			i.table.SetCell(row, 1, tui.NewTableCell(tview.Escape(field.Value)).
+				SetTextColor(tcell.ColorWhite))
+			i.fields[row] = field
+			row++
+		}
+	}
+	i.table.Select(1, 0)
+	i.table.ScrollToBeginning()
+
+	i.raw.SetTitle(" Log Entry (JSON) ")
+	i.raw.SetText(tview.Escape(entryJSON(entry)))
+	i.raw.ScrollToBeginning()
+}
+
+// selectedField returns the field of the selected row
+func (i *LogInspector) selectedField() (entryField, bool) {
+	row, _ := i.table.GetSelection()
+	field, ok := i.fields[row]
+	return field, ok
+}
+
+// toggleRaw switches between the field table and the JSON of the entry
+func (i *LogInspector) toggleRaw(app *tui.App) {
+	i.showRaw = !i.showRaw
+	i.Clear()
+	if i.showRaw {
+		i.AddItem(i.raw, 0, 1, true)
+		app.SetFocus(i.raw)
+		return
+	}
+	i.AddItem(i.table, 0, 1, true)
+	app.SetFocus(i.table)
+}
+
+// copy copies a value to the clipboard and reports the result
+func (i *LogInspector) copy(what, value string) {
+	if err := tui.CopyToClipboard(value); err != nil {
+		i.notify(fmt.Sprintf("[red::]Copy failed: %v", err))
+		return
+	}
+	i.notify(fmt.Sprintf("Copied %s to the clipboard", what))
+}
+
+// entryJSON renders an entry as indented JSON
+func entryJSON(entry model.LogEntry) string {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("failed to encode entry: %v", err)
+	}
+	return string(data)
+}
+
+// entrySections groups the fields of an entry for display
+func entrySections(entry model.LogEntry) []entrySection {
+	general := []entryField{
+		{Path: "timestamp", Value: entry.Timestamp.Format(time.RFC3339Nano)},
+	}
+	if entry.Severity != "" {
+		general = append(general, entryField{Path: "severity", Value: entry.Severity, Filter: "severity=" + entry.Severity})
+	}
+	if entry.Payload == nil && entry.Message != "" {
+		general = append(general, stringField("textPayload", entry.Message))
+	}
+	if entry.InsertID != "" {
+		general = append(general, stringField("insertId", entry.InsertID))
+	}
+	if entry.LogName != "" {
+		general = append(general, stringField("logName", entry.LogName))
+	}
+	if entry.Source != "" {
+		general = append(general, entryField{Path: "source", Value: entry.Source})
+	}
+
+	var resource []entryField
+	if entry.ResourceType != "" {
+		resource = append(resource, stringField("resource.type", entry.ResourceType))
+	}
+	resource = append(resource, labelFields("resource.labels", entry.ResourceLabels)...)
+
+	var request []entryField
+	if req := entry.HTTPRequest; req != nil {
+		request = setFields(
+			stringField("httpRequest.requestMethod", req.Method),
+			stringField("httpRequest.requestUrl", req.URL),
+			numberField("httpRequest.status", int64(req.Status)),
+			numberField("httpRequest.requestSize", req.RequestSize),
+			numberField("httpRequest.responseSize", req.ResponseSize),
+			stringField("httpRequest.userAgent", req.UserAgent),
+			stringField("httpRequest.remoteIp", req.RemoteIP),
+			stringField("httpRequest.protocol", req.Protocol),
+			entryField{Path: "httpRequest.latency", Value: req.Latency.String()},
+		)
+	}
+
+	var location []entryField
+	if loc := entry.SourceLocation; loc != nil {
+		location = setFields(
+			stringField("sourceLocation.file", loc.File),
+			numberField("sourceLocation.line", loc.Line),
+			stringField("sourceLocation.function", loc.Function),
+		)
+	}
+
+	var trace []entryField
+	if entry.Trace != "" {
+		trace = append(trace, stringField("trace", entry.Trace))
+	}
+	if entry.SpanID != "" {
+		trace = append(trace, stringField("spanId", entry.SpanID))
+	}
+
+	// Proto payloads carry their type, JSON payloads do not
+	payloadRoot := "jsonPayload"
+	if _, ok := entry.Payload["@type"]; ok {
+		payloadRoot = "protoPayload"
+	}
+	var payload []entryField
+	flattenPayload(payloadRoot, entry.Payload, &payload)
+
+	return []entrySection{
+		{Title: "Entry", Fields: general},
+		{Title: "Resource", Fields: resource},
+		{Title: "Labels", Fields: labelFields("labels", entry.Labels)},
+		{Title: "HTTP Request", Fields: request},
+		{Title: "Source Location", Fields: location},
+		{Title: "Trace", Fields: trace},
+		{Title: "Payload", Fields: payload},
+	}
+}
+
+// setFields returns the fields that have a value, dropping empty strings and zero numbers
+func setFields(fields ...entryField) []entryField {
+	var set []entryField
+	for _, field := range fields {
+		if field.Value != "" && field.Value != "0" && field.Value != "0s" {
+			set = append(set, field)
+		}
+	}
+	return set
+}
+
+// labelFields returns the fields of a label map, sorted by key
+func labelFields(root string, labels map[string]string) []entryField {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]entryField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, stringField(fieldPath(root, key), labels[key]))
+	}
+	return fields
+}
+
+// flattenPayload appends the leaf fields of a payload, sorted by key
+func flattenPayload(path string, value interface{}, fields *[]entryField) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			flattenPayload(fieldPath(path, key), v[key], fields)
+		}
+	case string:
+		*fields = append(*fields, stringField(path, v))
+	case float64:
+		value := strconv.FormatFloat(v, 'f', -1, 64)
+		*fields = append(*fields, entryField{Path: path, Value: value, Filter: path + "=" + value})
+	case bool:
+		value := strconv.FormatBool(v)
+		*fields = append(*fields, entryField{Path: path, Value: value, Filter: path + "=" + value})
+	case nil:
+		*fields = append(*fields, entryField{Path: path, Value: "null"})
+	default:
+		// Arrays are shown as JSON and cannot be filtered on as a whole
+		data, _ := json.Marshal(v)
+		*fields = append(*fields, entryField{Path: path, Value: string(data)})
+	}
+}
+
+// identifierPattern matches field names that need no quoting in the query language
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// fieldPath appends a field name to a path, quoting it when needed
+func fieldPath(root, name string) string {
+	if !identifierPattern.MatchString(name) {
+		name = strconv.Quote(name)
+	}
+	return root + "." + name
+}
+
+// stringField returns a field matching its exact string value
+func stringField(path, value string) entryField {
+	return entryField{Path: path, Value: value, Filter: path + "=" + strconv.Quote(value)}
+}
+
+// numberField returns a field matching its exact numeric value
+func numberField(path string, value int64) entryField {
+	text := strconv.FormatInt(value, 10)
+	return entryField{Path: path, Value: text, Filter: path + "=" + text}
+}
+
+// appendQuery adds an expression to a query with AND
+func appendQuery(query, expr string) string {
+	if query = strings.TrimSpace(query); query == "" {
+		return expr
+	}
+	return query + " AND " + expr
+}
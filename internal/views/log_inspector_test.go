@@ -0,0 +1,129 @@
+package views
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestFlattenPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    string
+		payload map[string]interface{}
+		want    []entryField
+	}{
+		{
+			name: "nested JSON payload",
+			root: "jsonPayload",
+			payload: map[string]interface{}{
+				"user":  map[string]interface{}{"id": float64(42), "admin": true},
+				"msg":   `said "hi"`,
+				"items": []interface{}{"a", "b"},
+				"gone":  nil,
+			},
+			want: []entryField{
+				{Path: "jsonPayload.gone", Value: "null"},
+				{Path: "jsonPayload.items", Value: `["a","b"]`},
+				{Path: "jsonPayload.msg", Value: `said "hi"`, Filter: `jsonPayload.msg="said \"hi\""`},
+				{Path: "jsonPayload.user.admin", Value: "true", Filter: "jsonPayload.user.admin=true"},
+				{Path: "jsonPayload.user.id", Value: "42", Filter: "jsonPayload.user.id=42"},
+			},
+		},
+		{
+			name: "keys that need quoting",
+			root: "protoPayload",
+			payload: map[string]interface{}{
+				"@type":     "type.googleapis.com/google.cloud.audit.AuditLog",
+				"service-x": map[string]interface{}{"method.name": "Replace"},
+			},
+			want: []entryField{
+				{Path: `protoPayload."@type"`, Value: "type.googleapis.com/google.cloud.audit.AuditLog",
+					Filter: `protoPayload."@type"="type.googleapis.com/google.cloud.audit.AuditLog"`},
+				{Path: `protoPayload."service-x"."method.name"`, Value: "Replace",
+					Filter: `protoPayload."service-x"."method.name"="Replace"`},
+			},
+		},
+		{
+			name:    "empty payload",
+			root:    "jsonPayload",
+			payload: map[string]interface{}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []entryField
+			flattenPayload(tt.root, tt.payload, &got)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+				t.Errorf("flattenPayload() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntrySections(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		entry model.LogEntry
+		// Paths of the fields of each non-empty section, by title
+		want map[string][]string
+	}{
+		{
+			name:  "text payload without labels",
+			entry: model.LogEntry{Timestamp: ts, Severity: "INFO", Message: "started", Labels: map[string]string{}},
+			want: map[string][]string{
+				"Entry": {"timestamp", "severity", "textPayload"},
+			},
+		},
+		{
+			name: "nested JSON payload",
+			entry: model.LogEntry{
+				Timestamp:      ts,
+				Message:        "order placed",
+				ResourceLabels: map[string]string{"service_name": "checkout", "location": "europe-west1"},
+				Labels:         map[string]string{"instanceId": "abc"},
+				Payload:        map[string]interface{}{"order": map[string]interface{}{"id": "o-1"}},
+				Trace:          "projects/p/traces/t",
+			},
+			want: map[string][]string{
+				"Entry":    {"timestamp"},
+				"Resource": {"resource.labels.location", "resource.labels.service_name"},
+				"Labels":   {"labels.instanceId"},
+				"Trace":    {"trace"},
+				"Payload":  {"jsonPayload.order.id"},
+			},
+		},
+		{
+			name: "proto payload",
+			entry: model.LogEntry{
+				Timestamp:   ts,
+				HTTPRequest: &model.HTTPRequest{Method: "GET", Status: 200},
+				Payload: map[string]interface{}{
+					"@type":      "type.googleapis.com/google.cloud.audit.AuditLog",
+					"methodName": "google.cloud.run.v1.Services.ReplaceService",
+				},
+			},
+			want: map[string][]string{
+				"Entry":        {"timestamp"},
+				"HTTP Request": {"httpRequest.requestMethod", "httpRequest.status"},
+				"Payload":      {`protoPayload."@type"`, "protoPayload.methodName"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string][]string{}
+			for _, section := range entrySections(tt.entry) {
+				for _, field := range section.Fields {
+					got[section.Title] = append(got[section.Title], field.Path)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("entrySections() =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+// patternColumns are the columns of the patterns table
+var patternColumns = []string{"COUNT", "LEVEL", "FIRST SEEN", "LAST SEEN", "PATTERN"}
+
+// LogPatterns is a pane listing the patterns of the loaded log messages.
+// Selecting a pattern filters the logs to it.
+type LogPatterns struct {
+	*tui.Table
+	patterns []analytics.Pattern
+
+	onSelect func(pattern analytics.Pattern)
+	onClose  func()
+}
+
+// NewLogPatterns creates a patterns pane. onSelect is called with the pattern
+// to filter the logs on and onClose when the pane is closed.
+func NewLogPatterns(app *tui.App, onSelect func(pattern analytics.Pattern), onClose func()) *LogPatterns {
+	p := &LogPatterns{
+		Table:    tui.NewTable(),
+		onSelect: onSelect,
+		onClose:  onClose,
+	}
+
+	p.SetBorders(false)
+	p.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
+	p.SetColumns(patternColumns)
+
+	keyHandler := tui.NewContextualKeyHandler(app)
+	keyHandler.SetContext(tui.ContextLogPatterns)
+
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		p.onClose()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		p.onClose()
+		return nil
+	})
+	keyHandler.RegisterRuneBinding('P', tui.ActionShowPatterns)
+	keyHandler.RegisterHandler(tui.ActionShowPatterns, func() error {
+		p.onClose()
+		return nil
+	})
+
+	keyHandler.RegisterHandler(tui.ActionEnter, func() error {
+		row, _ := p.GetSelection()
+		if row >= 1 && row <= len(p.patterns) {
+			p.onSelect(p.patterns[row-1])
+		}
+		return nil
+	})
+
+	p.SetInputCapture(keyHandler.CreateContextualInputCapture())
+
+	return p
+}
+
+// SetPatterns shows the patterns of entries, most frequent first
+func (p *LogPatterns) SetPatterns(patterns []analytics.Pattern, entries int) {
+	p.patterns = patterns
+	p.Clear()
+	p.SetTitle(fmt.Sprintf(" Patterns - %d of %d entries | Enter(Filter) Esc(Close) ", len(patterns), entries))
+
+	const layout = "01-02 15:04:05"
+	for i, pattern := range patterns {
+		row := i + 1
+		p.SetCell(row, 0, tui.NewTableCell(fmt.Sprintf("%d", pattern.Count)).
+			SetAlign(tview.AlignRight).
+			SetExpansion(0))
+		p.SetCell(row, 1, tui.NewTableCell(pattern.Severity).
+			SetTextColor(tcell.GetColor(levelColor(pattern.Severity))).
+			SetExpansion(0))
+		p.SetCell(row, 2, tui.NewTableCell(pattern.FirstSeen.Local().Format(layout)).
+			SetTextColor(tcell.ColorGray).
+			SetExpansion(0))
+		p.SetCell(row, 3, tui.NewTableCell(pattern.LastSeen.Local().Format(layout)).
+			SetTextColor(tcell.ColorGray).
+			SetExpansion(0))
+		p.SetCell(row, 4, tui.NewTableCell(tview.Escape(pattern.Template)).
+			SetTextColor(tcell.ColorWhite))
+	}
+	p.Select(1, 0)
+	p.ScrollToBeginning()
+}
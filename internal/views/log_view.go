@@ -3,25 +3,127 @@ package views
 import (
 	"context"
 	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/derailed/tcell/v2"
 	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/alerts"
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/export"
 	"github.com/lpmourato/c9s/internal/interfaces"
 	"github.com/lpmourato/c9s/internal/logging"
 	"github.com/lpmourato/c9s/internal/model"
 	"github.com/lpmourato/c9s/internal/ui/tui"
 )
 
+// severityFilters maps the log view filter actions to the minimum severity they show
+var severityFilters = map[tui.KeyAction]string{
+	tui.ActionSeverityAll:     "",
+	tui.ActionSeverityError:   model.SeverityError,
+	tui.ActionSeverityWarning: model.SeverityWarning,
+	tui.ActionSeverityInfo:    model.SeverityInfo,
+	tui.ActionSeverityDebug:   model.SeverityDebug,
+}
+
+// sparklineBins is the maximum number of bars of the activity sparkline
+const sparklineBins = 60
+
+// sourceColors are the colors of the service prefixes in a merged log view
+var sourceColors = []string{"aqua", "fuchsia", "orange", "lime", "violet", "gold", "skyblue", "salmon", "springgreen"}
+
+// Ensure LogView provides the rows of its log list
+var _ tui.LogRows = (*LogView)(nil)
+
 type LogView struct {
-	*tview.TextView
+	*tview.Flex
+	list        *tui.LogList
+	header      *tui.HeaderTable
+	prompt      *tui.Prompt
 	app         interfaces.UIController
 	serviceName string
 	region      string
 	ctx         context.Context
 	cancel      context.CancelFunc
 	streamer    model.LogStreamer
-	topMessage  string
+
+	// Stream configuration, kept so the stream can be restarted with new options
+	provider model.LogProvider
+	opts     model.CloudProviderOptions
+	title    string
+	// Log file or directory the entries are read from, if any
+	file string
+	// Family of Cloud Run logs the stream is restricted to
+	logKind logging.LogKind
+
+	// Entries received so far, bounded by the ring buffer, and the sequence
+	// numbers of those passing the active severity filter, oldest first
+	buffer       *logging.RingBuffer
+	visible      []int64
+	minSeverity  string
+	serverFilter bool
+
+	// Paging state for loading older entries
+	loadingOlder bool
+	noOlder      bool
+
+	// Active tee file receiving new entries, if any
+	tee    *teeWriter
+	status string
+
+	// Rows added since follow mode was turned off
+	newLines int
+	// Entries held back while rendering is frozen
+	frozen     bool
+	frozenHeld []model.LogEntry
+	// Entries lost because the view could not keep up with the stream
+	dropped int
+
+	// Names of the merged sources and those hidden from the view
+	sources       []string
+	hiddenSources map[string]bool
+
+	// Provider used to look up the logs of a trace, created on the first
+	// lookup by newTraceProvider when unset, and the colors of the spans shown
+	traceProvider    model.LogProvider
+	newTraceProvider func() (model.LogProvider, error)
+	spanColors       map[string]string
+
+	// View shown when leaving this one; the main view when nil
+	returnTo tview.Primitive
+	// Clients owned by the view, released when it closes
+	closers []io.Closer
+
+	// Detail pane of the selected entry, shown below the logs while open
+	inspector  *LogInspector
+	inspecting bool
+
+	// Consecutive identical messages are shown as one row while collapsing,
+	// with the number of entries of each row
+	collapse bool
+	repeats  []int
+
+	// Pattern list shown in place of the logs while open, and the template
+	// of the pattern the logs are filtered to
+	patterns        *LogPatterns
+	showingPatterns bool
+	pattern         string
+
+	// Per-minute counts of the buffered entries by severity, and the start of
+	// the sparkline bar selected with [ and ], if any
+	histogram   *analytics.SeverityHistogram
+	selectedBin time.Time
+
+	// Alert rules evaluated on the stream, the hooks they run, the banner of
+	// the latest alert and the number of alerts raised
+	alertRules []*alerts.Rule
+	notifier   *alerts.Notifier
+	banner     *tview.TextView
+	alertCount int
 }
 
 func NewLogView(app interfaces.UIController, projectID, serviceName, region string) (*LogView, error) {
@@ -30,13 +132,16 @@ func NewLogView(app interfaces.UIController, projectID, serviceName, region stri
 		return nil, fmt.Errorf("failed to create log streamer: %v", err)
 	}
 
-	return NewLogViewWithProvider(app, provider, projectID, serviceName, region)
+	v, err := NewLogViewWithProvider(app, provider, projectID, serviceName, region)
+	if err != nil {
+		return nil, err
+	}
+	v.own(provider)
+	return v, nil
 }
 
 // NewMockLogView creates a new log view with mock data for testing
 func NewMockLogView(app interfaces.UIController, serviceName, region string) *LogView {
-	ctx, cancel := context.WithCancel(context.Background())
-
 	// Create mock provider
 	mockProvider := &model.MockLogProvider{ServiceName: serviceName}
 
@@ -45,124 +150,479 @@ func NewMockLogView(app interfaces.UIController, serviceName, region string) *Lo
 		Region:      region,
 	}
 
-	streamer := logging.NewLogService(mockProvider, opts)
+	v := newLogView(app, mockProvider, opts, fmt.Sprintf("%s - %s (MOCK)", serviceName, region))
 
-	v := &LogView{
-		TextView:    tview.NewTextView().SetDynamicColors(true),
-		app:         app,
-		serviceName: serviceName,
-		region:      region,
-		ctx:         ctx,
-		cancel:      cancel,
-		streamer:    streamer,
+	// Show loading message (for mock view)
+	v.notify(fmt.Sprintf("Starting mock log stream for [yellow::b]%s[-:-:-]...", serviceName))
+
+	return v
+}
+
+// NewLogViewWithProvider creates a new log view with the specified provider
+func NewLogViewWithProvider(app interfaces.UIController, provider model.LogProvider, projectID, serviceName, region string) (*LogView, error) {
+	opts := model.CloudProviderOptions{
+		ProjectID:   projectID,
+		ServiceName: serviceName,
+		Region:      region,
 	}
 
-	v.SetBorder(true)
-	v.SetTitle(fmt.Sprintf(" %s - %s (MOCK) ", serviceName, region))
-	v.SetTitleAlign(tview.AlignLeft)
+	v := newLogView(app, provider, opts, fmt.Sprintf("%s - %s", serviceName, region))
 
-	// Show loading message (for mock view)
-	loadingMsg := fmt.Sprintf("[gray::b]Starting mock log stream for [yellow::b]%s[gray::b]...\n\n",
-		serviceName)
-	fmt.Fprint(v, loadingMsg)
+	// Show loading message
+	v.notify(fmt.Sprintf("Loading logs from [yellow::b]%s[-:-:-] in region [yellow::b]%s[-:-:-]...", serviceName, region))
 
-	// Set up centralized key handler for log view
-	if tuiApp, ok := app.(*tui.App); ok {
-		keyHandler := tui.NewContextualKeyHandler(tuiApp)
-		keyHandler.SetContext(tui.ContextLogView)
+	return v, nil
+}
 
-		keyHandler.RegisterHandler(tui.ActionEscape, func() error {
-			v.cancel()         // Stop log streaming
-			app.ReturnToMain() // Always return to main view
-			return nil
-		})
+// NewMergedLogView creates a log view combining the streams of several services
+// in timestamp order, each line prefixed with the name of its service
+func NewMergedLogView(app interfaces.UIController, sources []logging.MergeSource) *LogView {
+	merged := logging.NewMergedStreamer(sources, logging.DefaultReorderWindow)
+	names := merged.Sources()
 
-		keyHandler.RegisterHandler(tui.ActionQuit, func() error {
-			v.cancel()
-			app.ReturnToMain()
-			return nil
+	opts := model.CloudProviderOptions{ServiceName: "merged"}
+	v := newLogView(app, nil, opts, "Merged - "+strings.Join(names, ", "))
+	v.streamer = merged
+	v.own(merged)
+	v.sources = names
+	v.hiddenSources = make(map[string]bool)
+	v.updateHeader()
+
+	v.notify(fmt.Sprintf("Loading logs from [yellow::b]%d[-:-:-] services...", len(names)))
+
+	return v
+}
+
+// NewTraceLogView creates a log view of all the logs of a trace, across the
+// services of the project, grouped by span
+func NewTraceLogView(app interfaces.UIController, provider model.LogProvider, projectID, trace string) *LogView {
+	opts := model.CloudProviderOptions{
+		ProjectID: projectID,
+		Trace:     trace,
+	}
+
+	id := traceID(trace)
+	v := newLogView(app, provider, opts, "Trace - "+id)
+	v.serviceName = "trace-" + id
+
+	v.notify(fmt.Sprintf("Loading logs of trace [yellow::b]%s[-:-:-]...", id))
+
+	return v
+}
+
+// NewFileLogView creates a log view of all the entries of a file-backed provider
+func NewFileLogView(app interfaces.UIController, provider *logging.FileLogProvider) *LogView {
+	name := filepath.Base(provider.Path())
+	v := newLogView(app, provider, model.CloudProviderOptions{}, "File - "+name)
+	v.serviceName = strings.TrimSuffix(name, filepath.Ext(name))
+	v.file = provider.Path()
+	v.updateHeader()
+
+	v.notify(fmt.Sprintf("Loading [yellow::b]%d[-:-:-] entries from [yellow::b]%s[-:-:-]...", provider.Len(), tview.Escape(provider.Path())))
+
+	return v
+}
+
+// newLogView builds the log view shared by the GCP and mock constructors
+func newLogView(app interfaces.UIController, provider model.LogProvider, opts model.CloudProviderOptions, title string) *LogView {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v := &LogView{
+		Flex:          tview.NewFlex().SetDirection(tview.FlexRow),
+		header:        tui.NewHeaderTable(),
+		app:           app,
+		serviceName:   opts.ServiceName,
+		region:        opts.Region,
+		ctx:           ctx,
+		cancel:        cancel,
+		provider:      provider,
+		traceProvider: provider,
+		opts:          opts,
+		title:         title,
+		buffer:        logging.NewRingBuffer(logging.DefaultRingBufferSize),
+		histogram:     analytics.NewSeverityHistogram(time.Minute),
+		notifier:      alerts.NewNotifier(),
+		banner:        tview.NewTextView().SetDynamicColors(true),
+	}
+	if provider != nil {
+		v.streamer = logging.NewLogService(provider, opts)
+	}
+
+	// Scrolling up past the first row loads older pages
+	v.list = tui.NewLogList(v).
+		SetTopReachedFunc(v.loadOlder).
+		SetFollowChangedFunc(func(following bool) {
+			if following {
+				v.newLines = 0
+				v.selectedBin = time.Time{}
+				v.updateActivity()
+			}
+			v.updateTitle()
 		})
+	v.list.SetBorder(true)
+	v.list.SetTitleAlign(tview.AlignLeft)
+	v.header.SetTitle(" Log Query ")
 
-		// Set up key bindings
-		v.SetInputCapture(keyHandler.CreateContextualInputCapture())
-	} else {
+	v.AddItem(v.header, 7, 0, false)
+	v.AddItem(v.banner, 0, 0, false)
+	v.AddItem(v.list, 0, 1, true)
+
+	v.updateTitle()
+	v.updateHeader()
+	v.setupKeys()
+
+	return v
+}
+
+// setupKeys sets up the key bindings of the log view
+func (v *LogView) setupKeys() {
+	tuiApp, ok := v.app.(*tui.App)
+	if !ok {
 		// Fallback to original key handling if not tui.App
-		v.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		v.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
 			if event.Key() == tcell.KeyEscape || (event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q')) {
 				v.cancel()
-				app.ReturnToMain()
+				v.app.ReturnToMain()
 				return nil
 			}
 			return event
 		})
+		return
 	}
 
-	return v
-}
+	// Set up centralized key handler for log view
+	keyHandler := tui.NewContextualKeyHandler(tuiApp)
+	keyHandler.SetContext(tui.ContextLogView)
 
-// NewLogViewWithProvider creates a new log view with the specified provider
-func NewLogViewWithProvider(app interfaces.UIController, provider model.LogProvider, projectID, serviceName, region string) (*LogView, error) {
-	ctx, cancel := context.WithCancel(context.Background())
+	// Escape clears the pattern filter before leaving the view
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		if v.pattern != "" {
+			v.SetPattern("")
+			return nil
+		}
+		v.back()
+		return nil
+	})
 
-	opts := model.CloudProviderOptions{
-		ProjectID:   projectID,
-		ServiceName: serviceName,
-		Region:      region,
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		v.back()
+		return nil
+	})
+
+	// Severity filters: 0 shows everything, 1-4 narrow down to a minimum level
+	keyHandler.RegisterRuneBinding('0', tui.ActionSeverityAll)
+	keyHandler.RegisterRuneBinding('1', tui.ActionSeverityError)
+	keyHandler.RegisterRuneBinding('2', tui.ActionSeverityWarning)
+	keyHandler.RegisterRuneBinding('3', tui.ActionSeverityInfo)
+	keyHandler.RegisterRuneBinding('4', tui.ActionSeverityDebug)
+	for action, severity := range severityFilters {
+		severity := severity
+		keyHandler.RegisterHandler(action, func() error {
+			v.SetMinSeverity(severity)
+			return nil
+		})
 	}
 
-	streamer := logging.NewLogService(provider, opts)
+	keyHandler.RegisterRuneBinding('s', tui.ActionToggleServerSeverity)
+	keyHandler.RegisterRuneBinding('S', tui.ActionToggleServerSeverity)
+	keyHandler.RegisterHandler(tui.ActionToggleServerSeverity, func() error {
+		v.ToggleServerFilter()
+		return nil
+	})
 
-	v := &LogView{
-		TextView:    tview.NewTextView().SetDynamicColors(true),
-		app:         app,
-		serviceName: serviceName,
-		region:      region,
-		ctx:         ctx,
-		cancel:      cancel,
-		streamer:    streamer,
-	}
+	// Query editing: the prompt edits one part of the composed query, r re-runs it
+	v.prompt = tui.NewPrompt(tuiApp, v.Flex, v.list)
 
-	v.SetBorder(true)
-	v.SetTitle(fmt.Sprintf(" %s - %s ", serviceName, region))
-	v.SetTitleAlign(tview.AlignLeft)
+	keyHandler.RegisterRuneBinding('/', tui.ActionEditQuery)
+	keyHandler.RegisterHandler(tui.ActionEditQuery, func() error {
+		v.prompt.Ask("Query: ", v.opts.Query, v.SetQuery)
+		return nil
+	})
 
-	// Show loading message
-	loadingMsg := fmt.Sprintf("[gray::b]Loading logs from [yellow::b]%s[gray::b] in region [yellow::b]%s[gray::b]...\n\n",
-		serviceName, region)
-	fmt.Fprint(v, loadingMsg)
+	keyHandler.RegisterRuneBinding('v', tui.ActionEditRevision)
+	keyHandler.RegisterRuneBinding('V', tui.ActionEditRevision)
+	keyHandler.RegisterHandler(tui.ActionEditRevision, func() error {
+		v.prompt.Ask("Revision: ", v.opts.Revision, v.SetRevision)
+		return nil
+	})
 
-	// Set up key bindings
-	// Set up centralized key handler for log view
-	if tuiApp, ok := app.(*tui.App); ok {
-		keyHandler := tui.NewContextualKeyHandler(tuiApp)
-		keyHandler.SetContext(tui.ContextLogView)
+	keyHandler.RegisterHandler(tui.ActionRefresh, func() error {
+		v.Rerun()
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('T', tui.ActionEditTimeRange)
+	keyHandler.RegisterHandler(tui.ActionEditTimeRange, func() error {
+		v.prompt.Ask("Time range (e.g. 1h, yesterday 14:02..14:20): ", v.timeRangeText(), v.applyTimeRange)
+		return nil
+	})
+
+	// Enter opens the selected entry in the inspector
+	v.inspector = NewLogInspector(tuiApp, v.filterByField, v.closeInspector, v.notify)
+	keyHandler.RegisterHandler(tui.ActionEnter, func() error {
+		v.Inspect()
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('t', tui.ActionShowTrace)
+	keyHandler.RegisterHandler(tui.ActionShowTrace, func() error {
+		v.ShowTrace()
+		return nil
+	})
+
+	// Follow mode turns off when scrolling up; G or f turn it back on
+	keyHandler.RegisterRuneBinding('G', tui.ActionFollow)
+	keyHandler.RegisterRuneBinding('f', tui.ActionFollow)
+	keyHandler.RegisterHandler(tui.ActionFollow, func() error {
+		v.list.ScrollToEnd()
+		return nil
+	})
 
-		keyHandler.RegisterHandler(tui.ActionEscape, func() error {
-			v.cancel()         // Stop log streaming
-			app.ReturnToMain() // Always return to main view
+	// l switches between all, application, request and system logs
+	keyHandler.RegisterRuneBinding('l', tui.ActionToggleLogKind)
+	keyHandler.RegisterHandler(tui.ActionToggleLogKind, func() error {
+		v.ToggleLogKind()
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('c', tui.ActionToggleCollapse)
+	keyHandler.RegisterHandler(tui.ActionToggleCollapse, func() error {
+		v.ToggleCollapse()
+		return nil
+	})
+
+	// P lists the message patterns, selecting one filters the logs to it
+	v.patterns = NewLogPatterns(tuiApp, func(pattern analytics.Pattern) {
+		v.closePatterns()
+		v.SetPattern(pattern.Template)
+	}, v.closePatterns)
+	keyHandler.RegisterRuneBinding('P', tui.ActionShowPatterns)
+	keyHandler.RegisterHandler(tui.ActionShowPatterns, func() error {
+		v.ShowPatterns()
+		return nil
+	})
+
+	// [ and ] select a bar of the activity sparkline and jump to its time
+	keyHandler.RegisterRuneBinding('[', tui.ActionPreviousBucket)
+	keyHandler.RegisterHandler(tui.ActionPreviousBucket, func() error {
+		v.SelectBin(-1)
+		return nil
+	})
+	keyHandler.RegisterRuneBinding(']', tui.ActionNextBucket)
+	keyHandler.RegisterHandler(tui.ActionNextBucket, func() error {
+		v.SelectBin(1)
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('p', tui.ActionToggleFreeze)
+	keyHandler.RegisterHandler(tui.ActionToggleFreeze, func() error {
+		v.ToggleFreeze()
+		return nil
+	})
+
+	// Export: w saves the loaded entries, W tees new entries to a file
+	keyHandler.RegisterRuneBinding('w', tui.ActionSaveLogs)
+	keyHandler.RegisterHandler(tui.ActionSaveLogs, func() error {
+		v.promptExport(v.SaveLogs)
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('W', tui.ActionToggleTee)
+	keyHandler.RegisterHandler(tui.ActionToggleTee, func() error {
+		if v.tee != nil {
+			v.StopTee()
 			return nil
-		})
+		}
+		v.promptExport(v.StartTee)
+		return nil
+	})
 
-		keyHandler.RegisterHandler(tui.ActionQuit, func() error {
-			v.cancel()
-			app.ReturnToMain()
+	// F1-F9 toggle the services of a merged view
+	capture := keyHandler.CreateContextualInputCapture()
+	v.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if n := int(event.Key() - tcell.KeyF1); n >= 0 && n < len(v.sources) && n < 9 {
+			v.ToggleSource(n)
 			return nil
-		})
+		}
+		return capture(event)
+	})
+}
 
-		v.SetInputCapture(keyHandler.CreateContextualInputCapture())
-	} else {
-		// Fallback to original key handling
-		v.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			if event.Key() == tcell.KeyEscape || (event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q')) {
-				v.cancel()
-				app.ReturnToMain()
-				return nil
-			}
-			return event
-		})
+// SetBufferSize sets the maximum number of entries kept by the view.
+// It must be called before streaming starts.
+func (v *LogView) SetBufferSize(size int) {
+	v.buffer = logging.NewRingBuffer(size)
+	v.visible = nil
+	v.histogram.Reset()
+}
+
+// RowCount implements tui.LogRows
+func (v *LogView) RowCount() int {
+	return len(v.visible)
+}
+
+// RowText implements tui.LogRows
+func (v *LogView) RowText(row int) string {
+	entry, _ := v.buffer.Get(v.visible[row])
+	var line string
+	switch {
+	case v.opts.Trace != "":
+		line = v.traceLine(entry)
+	case entry.Source == "":
+		line = formatLogLine(entry)
+	default:
+		line = fmt.Sprintf("[%s::b]%-*s[-:-:-] %s", v.sourceColor(entry.Source), v.sourceWidth(), entry.Source, formatLogLine(entry))
+	}
+	if v.repeats != nil && v.repeats[row] > 1 {
+		line += fmt.Sprintf(" [aqua::b](×%d)[-:-:-]", v.repeats[row])
 	}
+	return line
+}
 
-	return v, nil
+// traceLine renders an entry of a trace prefixed with its span and service
+func (v *LogView) traceLine(entry model.LogEntry) string {
+	span := entry.SpanID
+	if span == "" {
+		span = "-"
+	}
+	color, ok := v.spanColors[span]
+	if !ok {
+		if v.spanColors == nil {
+			v.spanColors = make(map[string]string)
+		}
+		color = sourceColors[len(v.spanColors)%len(sourceColors)]
+		v.spanColors[span] = color
+	}
+
+	service := entry.ResourceLabels["service_name"]
+	if service == "" {
+		service = entry.ResourceType
+	}
+	return fmt.Sprintf("[%s::b]%-16s[-:-:-] [white::]%-20s[-:-:-] %s", color, span, tview.Escape(service), formatLogLine(entry))
+}
+
+// traceID returns the ID of a trace from its full "projects/<project>/traces/<id>" name
+func traceID(trace string) string {
+	return trace[strings.LastIndex(trace, "/")+1:]
+}
+
+// truncate shortens text to at most max runes, marking the cut with an ellipsis
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return string(runes[:max-1]) + "…"
+}
+
+// sourceColor returns the prefix color of a merged source
+func (v *LogView) sourceColor(source string) string {
+	for i, name := range v.sources {
+		if name == source {
+			return sourceColors[i%len(sourceColors)]
+		}
+	}
+	return "white"
+}
+
+// sourceWidth returns the width of the longest source name
+func (v *LogView) sourceWidth() int {
+	width := 0
+	for _, name := range v.sources {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	return width
+}
+
+// SetTraceProviderFunc sets how the provider used to look up the logs of a
+// trace is created. It is created on the first lookup and closed with the view.
+func (v *LogView) SetTraceProviderFunc(newProvider func() (model.LogProvider, error)) {
+	v.newTraceProvider = newProvider
+}
+
+// SelectedEntry returns the entry of the selected row
+func (v *LogView) SelectedEntry() (model.LogEntry, bool) {
+	row := v.list.GetSelectedRow()
+	if row < 0 || row >= len(v.visible) {
+		return model.LogEntry{}, false
+	}
+	return v.buffer.Get(v.visible[row])
+}
+
+// Inspect opens the selected entry in the inspector pane
+func (v *LogView) Inspect() {
+	tuiApp, ok := v.app.(*tui.App)
+	entry, found := v.SelectedEntry()
+	if !ok || !found {
+		return
+	}
+
+	// Keep the inspected row selected while new entries arrive
+	v.list.Select(v.list.GetSelectedRow())
+
+	v.inspector.SetEntry(entry)
+	if !v.inspecting {
+		v.inspecting = true
+		v.AddItem(v.inspector, 0, 1, true)
+	}
+	tuiApp.SetFocus(v.inspector)
+}
+
+// closeInspector closes the inspector pane and returns to the logs
+func (v *LogView) closeInspector() {
+	if !v.inspecting {
+		return
+	}
+	v.inspecting = false
+	v.RemoveItem(v.inspector)
+	if tuiApp, ok := v.app.(*tui.App); ok {
+		tuiApp.SetFocus(v.list)
+	}
+}
+
+// filterByField adds a match on an inspected field to the query and re-runs it
+func (v *LogView) filterByField(field entryField) {
+	v.closeInspector()
+	if v.provider == nil {
+		v.notify("[yellow::]Filtering by field is not available for this view")
+		return
+	}
+	v.SetQuery(appendQuery(v.opts.Query, field.Filter))
+}
+
+// ShowTrace opens a log view of all the logs in the trace of the selected entry
+func (v *LogView) ShowTrace() {
+	entry, ok := v.SelectedEntry()
+	if !ok {
+		return
+	}
+	if entry.Trace == "" {
+		v.notify("[yellow::]The selected entry is not part of a trace")
+		return
+	}
+	if v.traceProvider == nil && v.newTraceProvider != nil {
+		provider, err := v.newTraceProvider()
+		if err != nil {
+			v.notify(fmt.Sprintf("[red::]Trace lookup failed: %v", err))
+			return
+		}
+		v.traceProvider = provider
+		v.own(provider)
+	}
+	if v.traceProvider == nil {
+		v.notify("[yellow::]Trace lookup is not available for this view")
+		return
+	}
+
+	traceView := NewTraceLogView(v.app, v.traceProvider, v.opts.ProjectID, entry.Trace)
+	traceView.SetBufferSize(v.buffer.Capacity())
+	traceView.returnTo = v
+
+	go traceView.StreamLogs()
+
+	v.app.SwitchToView(traceView)
 }
 
 // SetStreamer sets the log streamer
@@ -172,89 +632,911 @@ func (v *LogView) SetStreamer(streamer model.LogStreamer) {
 
 // StreamLogs starts streaming logs
 func (v *LogView) StreamLogs() {
-	v.streamLogs()
+	v.streamLogs(v.ctx, v.streamer)
+}
+
+// SetMinSeverity sets the minimum severity shown in the view.
+// When the server-side filter is active the stream is restarted with the new level.
+func (v *LogView) SetMinSeverity(severity string) {
+	if v.minSeverity == severity {
+		return
+	}
+	v.minSeverity = severity
+	v.updateTitle()
+
+	if v.serverFilter {
+		v.restartStream()
+		return
+	}
+	v.render()
 }
 
-func (v *LogView) streamLogs() {
-	logChan := v.streamer.StreamLogs(v.ctx)
+// SetQuery sets the user expression added to the composed query and re-runs it
+func (v *LogView) SetQuery(query string) {
+	v.opts.Query = strings.TrimSpace(query)
+	v.Rerun()
+}
 
-	for entry := range logChan {
-		entry := entry // capture for goroutine
+// SetRevision restricts the logs to a single revision and re-runs the query.
+// An empty revision shows the logs of all revisions.
+func (v *LogView) SetRevision(revision string) {
+	v.opts.Revision = strings.TrimSpace(revision)
+	v.Rerun()
+}
+
+// SetTimeRange sets the time range loaded when the stream starts.
+// Both bounds are optional and accept the formats of logging.ParseTime.
+func (v *LogView) SetTimeRange(since, until string) error {
+	sinceTime, untilTime, err := logging.ParseTimeBounds(since, until, time.Now())
+	if err != nil {
+		return err
+	}
+
+	v.opts.Since, v.opts.Until = sinceTime, untilTime
+	if v.provider != nil {
+		v.streamer = v.newStreamer()
+	}
+	v.updateHeader()
+	return nil
+}
+
+// applyTimeRange parses a range typed in the prompt and re-runs the query
+func (v *LogView) applyTimeRange(text string) {
+	since, until, err := logging.ParseTimeRange(text, time.Now())
+	if err != nil {
+		v.notify(fmt.Sprintf("[red::]Invalid time range: %v", err))
+		return
+	}
+	v.opts.Since, v.opts.Until = since, until
+	v.Rerun()
+}
+
+// timeRangeText renders the current time range in a form accepted by the prompt
+func (v *LogView) timeRangeText() string {
+	const layout = "2006-01-02 15:04:05"
+	switch {
+	case v.opts.Since.IsZero():
+		return ""
+	case v.opts.Until.IsZero():
+		return v.opts.Since.Local().Format(layout)
+	}
+	return v.opts.Since.Local().Format(layout) + ".." + v.opts.Until.Local().Format(layout)
+}
+
+// loadOlder loads the page of entries preceding the oldest entry in the view
+func (v *LogView) loadOlder() {
+	pager, ok := v.streamer.(model.LogPager)
+	if !ok || v.loadingOlder || v.noOlder {
+		return
+	}
+	v.loadingOlder = true
+	v.list.SetTitle(v.list.GetTitle() + "loading older... ")
+
+	ctx := v.ctx
+	go func() {
+		entries, more, err := pager.FetchOlder(ctx)
 		v.app.QueueUpdateDraw(func() {
-			timestamp := entry.Timestamp.Local().Format("2006-01-02 15:04:05.000 MST")
-			message := entry.Message
-
-			// If this is an initial status message, set as topMessage
-			if strings.HasPrefix(message, "Initial load: searching for logs from") {
-				v.topMessage = fmt.Sprintf("[gray::b]%s[-:-:-]\n", message)
-				v.SetText(v.topMessage)
-				v.ScrollToBeginning()
+			if ctx.Err() != nil {
 				return
 			}
+			v.loadingOlder = false
+			v.updateTitle()
+			if err != nil {
+				v.notify(fmt.Sprintf("[red::]Failed to load older logs: %v", err))
+				return
+			}
+			v.noOlder = !more
 
-			// Parse level from the message content when severity is DEFAULT
-			level := "INFO" // default level
-			message = strings.TrimSpace(message)
-
-			// Check for common log level patterns in the message
-			switch {
-			case strings.Contains(message, "ERROR"):
-				level = "ERROR"
-			case strings.Contains(message, "WARN") || strings.Contains(message, "WARNING"):
-				level = "WARN"
-			case strings.Contains(message, "INFO"):
-				level = "INFO"
-			case strings.Contains(message, "DEBUG"):
-				level = "DEBUG"
-			case strings.Contains(message, "TRACE"):
-				level = "TRACE"
+			// Older entries only fill the free space of the buffer
+			kept := v.buffer.Prepend(entries)
+			if kept < len(entries) {
+				v.noOlder = true
+				v.notify("Log buffer is full, older logs were not loaded")
+			} else if v.noOlder {
+				v.notify("Reached the oldest logs")
 			}
 
-			message = strings.TrimSpace(message)
-
-			// K9s-style coloring
-			var levelColor string
-
-			// Apply coloring based on level
-			switch level {
-			case "ERROR", "CRITICAL", "FATAL":
-				levelColor = "[red::b]"
-			case "WARN":
-				levelColor = "[yellow::b]"
-			case "INFO":
-				levelColor = "[green::b]"
-			case "DEBUG":
-				levelColor = "[gray::b]"
-			case "TRACE":
-				levelColor = "[blue::b]"
-			default:
-				levelColor = "[green::b]" // Use INFO color for any unrecognized level
+			for _, entry := range entries[len(entries)-kept:] {
+				v.histogram.Add(entry.Timestamp, entryLevel(entry))
 			}
+			v.updateActivity()
 
-			// Escape any existing color codes in the message
-			message = strings.ReplaceAll(message, "[", "[[")
-
-			// Format: gray timestamp, bold colored level, white message
-			logLine := fmt.Sprintf("[gray::b]%s[-:-:-] %s%-7s[-:-:-] [white::b]%s[-:-:-]\n",
-				timestamp,
-				levelColor,
-				level,
-				message,
-			)
-
-			// Get current content and append new log line, always keeping topMessage at the top
-			currentContent := v.GetText(false)
-			if v.topMessage != "" {
-				// Remove topMessage from currentContent if present
-				currentContent = strings.TrimPrefix(currentContent, v.topMessage)
-				newContent := v.topMessage + currentContent + logLine
-				v.SetText(newContent)
-			} else {
-				v.SetText(currentContent + logLine)
+			// Keep the previously visible rows in place
+			if v.opts.Trace != "" || v.collapse {
+				// Grouped rows are rebuilt, older entries add rows before them
+				rows := len(v.visible)
+				v.render()
+				v.list.ShiftRows(len(v.visible) - rows)
+				return
+			}
+			var older []int64
+			seq := v.buffer.FirstSeq()
+			for _, entry := range entries[len(entries)-kept:] {
+				if v.matchesFilter(entry) {
+					older = append(older, seq)
+				}
+				seq++
 			}
+			v.visible = append(older, v.visible...)
+			v.list.ShiftRows(len(older))
+		})
+	}()
+}
 
-			// Keep the view scrolled to end to show latest logs
-			v.ScrollToEnd()
+// promptExport asks for the export file, and the columns for CSV files, then calls done
+func (v *LogView) promptExport(done func(path string, format export.Format, columns []string)) {
+	defaultPath := fmt.Sprintf("%s-%s.jsonl", v.serviceName, time.Now().Format("20060102-150405"))
+	v.prompt.Ask("Save to (.jsonl, .csv or .txt): ", defaultPath, func(path string) {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			return
+		}
+		format := export.FormatForPath(path)
+		if format != export.CSV {
+			done(path, format, nil)
+			return
+		}
+		v.prompt.Ask("CSV columns: ", strings.Join(export.DefaultColumns, ","), func(text string) {
+			columns, err := export.ParseColumns(text)
+			if err != nil {
+				v.notify(fmt.Sprintf("[red::]Export failed: %v", err))
+				return
+			}
+			done(path, format, columns)
+		})
+	})
+}
+
+// SaveLogs writes the loaded entries that pass the active filters to a file
+func (v *LogView) SaveLogs(path string, format export.Format, columns []string) {
+	entries := v.filteredEntries()
+
+	go func() {
+		written, err := export.WriteFile(path, format, columns, entries)
+		v.app.QueueUpdateDraw(func() {
+			if err != nil {
+				v.notify(fmt.Sprintf("[red::]Export failed: %v", err))
+				return
+			}
+			v.notify(fmt.Sprintf("Saved %d entries to %s", len(entries), written))
+		})
+	}()
+}
+
+// StartTee starts appending new entries that pass the active filters to a file
+func (v *LogView) StartTee(path string, format export.Format, columns []string) {
+	tee, err := export.NewTee(path, format, columns, export.DefaultMaxSize)
+	if err != nil {
+		v.notify(fmt.Sprintf("[red::]Tee failed: %v", err))
+		return
+	}
+	var w *teeWriter
+	w = newTeeWriter(tee, func(err error) {
+		v.app.QueueUpdateDraw(func() {
+			if v.tee != w {
+				return
+			}
+			v.StopTee()
+			v.notify(fmt.Sprintf("[red::]Tee failed: %v", err))
 		})
+	})
+	v.tee = w
+	v.updateTitle()
+	v.notify("Appending new entries to " + tee.Path)
+}
+
+// StopTee stops appending new entries to the tee file
+func (v *LogView) StopTee() {
+	if v.tee == nil {
+		return
+	}
+	path := v.tee.Path
+	v.tee.Close()
+	v.tee = nil
+	v.updateTitle()
+	v.notify("Stopped appending to " + path)
+}
+
+// ToggleSource shows or hides the lines of the nth merged source
+func (v *LogView) ToggleSource(n int) {
+	if n < 0 || n >= len(v.sources) {
+		return
+	}
+	name := v.sources[n]
+	v.hiddenSources[name] = !v.hiddenSources[name]
+	v.render()
+	v.updateHeader()
+}
+
+// ToggleCollapse turns collapsing consecutive identical messages on or off
+func (v *LogView) ToggleCollapse() {
+	v.collapse = !v.collapse
+	v.render()
+	v.updateTitle()
+}
+
+// ShowPatterns lists the patterns of the loaded messages passing the
+// severity and source filters in place of the logs
+func (v *LogView) ShowPatterns() {
+	tuiApp, ok := v.app.(*tui.App)
+	if !ok {
+		return
+	}
+	v.closeInspector()
+
+	set := analytics.NewPatternSet()
+	entries := 0
+	for seq := v.buffer.FirstSeq(); seq < v.buffer.NextSeq(); seq++ {
+		if entry, _ := v.buffer.Get(seq); v.matchesLevelAndSource(entry) {
+			set.Add(entry.Message, entryLevel(entry), entry.Timestamp)
+			entries++
+		}
+	}
+	v.patterns.SetPatterns(set.Patterns(), entries)
+
+	if !v.showingPatterns {
+		v.showingPatterns = true
+		v.RemoveItem(v.list)
+		v.AddItem(v.patterns, 0, 1, true)
+	}
+	tuiApp.SetFocus(v.patterns)
+}
+
+// closePatterns closes the pattern list and shows the logs again
+func (v *LogView) closePatterns() {
+	if !v.showingPatterns {
+		return
+	}
+	v.showingPatterns = false
+	v.RemoveItem(v.patterns)
+	v.AddItem(v.list, 0, 1, true)
+	if tuiApp, ok := v.app.(*tui.App); ok {
+		tuiApp.SetFocus(v.list)
+	}
+}
+
+// SetPattern filters the logs to the messages of a pattern template.
+// An empty template shows all messages again.
+func (v *LogView) SetPattern(template string) {
+	v.pattern = template
+	v.render()
+	v.list.ScrollToEnd()
+	v.updateTitle()
+}
+
+// ToggleFreeze stops or resumes rendering. While frozen, streamed entries are
+// held back and added to the view once rendering resumes.
+func (v *LogView) ToggleFreeze() {
+	v.frozen = !v.frozen
+	if !v.frozen {
+		held := v.frozenHeld
+		v.frozenHeld = nil
+		v.appendEntries(held)
+	}
+	v.updateTitle()
+}
+
+// back closes the view and shows the view it was opened from
+func (v *LogView) back() {
+	v.close()
+	if v.returnTo != nil {
+		v.app.SwitchToView(v.returnTo)
+		return
+	}
+	v.app.ReturnToMain()
+}
+
+// close stops streaming and releases the resources held by the view
+func (v *LogView) close() {
+	v.cancel()
+	if v.tee != nil {
+		v.tee.Close()
+		v.tee = nil
+	}
+	for _, c := range v.closers {
+		c.Close()
+	}
+	v.closers = nil
+}
+
+// own makes the view release a provider or streamer holding a client when it closes
+func (v *LogView) own(resource interface{}) {
+	if c, ok := resource.(io.Closer); ok {
+		v.closers = append(v.closers, c)
+	}
+}
+
+// notify shows a status message in the header
+func (v *LogView) notify(msg string) {
+	v.status = msg
+	v.updateHeader()
+}
+
+// Rerun restarts the stream with the current query
+func (v *LogView) Rerun() {
+	if v.provider == nil {
+		return // Custom streamer, nothing to restart
+	}
+	v.restartStream()
+}
+
+// ToggleLogKind restricts the stream to the next family of Cloud Run logs:
+// all, application (stdout and stderr), requests and system logs
+func (v *LogView) ToggleLogKind() {
+	if v.provider == nil || v.opts.Trace != "" {
+		return // Custom streamer or trace, nothing to restrict
+	}
+	v.logKind = v.logKind.Next()
+	v.opts.LogIDs = v.logKind.LogIDs()
+	v.restartStream()
+}
+
+// ToggleServerFilter toggles pushing the severity filter into the provider query
+func (v *LogView) ToggleServerFilter() {
+	if v.provider == nil {
+		return // Custom streamer, nothing to restart
+	}
+	v.serverFilter = !v.serverFilter
+	v.updateTitle()
+	v.restartStream()
+}
+
+// restartStream cancels the running stream and starts a new one with the current options
+func (v *LogView) restartStream() {
+	v.cancel()
+	v.ctx, v.cancel = context.WithCancel(context.Background())
+
+	v.opts.MinSeverity = ""
+	if v.serverFilter {
+		v.opts.MinSeverity = v.minSeverity
+	}
+	v.streamer = v.newStreamer()
+
+	v.buffer.Clear()
+	v.histogram.Reset()
+	v.selectedBin = time.Time{}
+	v.visible = nil
+	v.loadingOlder = false
+	v.noOlder = false
+	v.frozen = false
+	v.frozenHeld = nil
+	v.dropped = 0
+	v.list.ScrollToEnd()
+	v.updateTitle()
+	v.updateHeader()
+
+	go v.streamLogs(v.ctx, v.streamer)
+}
+
+// updateTitle refreshes the view title with the active severity filter
+func (v *LogView) updateTitle() {
+	title := " " + v.title
+	if v.minSeverity != "" {
+		title += fmt.Sprintf(" | %s+", v.minSeverity)
+		if v.serverFilter {
+			title += " (server)"
+		}
+	}
+	if v.logKind != logging.AllLogs {
+		title += fmt.Sprintf(" | %s logs", v.logKind)
+	}
+	if v.pattern != "" {
+		title += " | pattern: " + tview.Escape(truncate(v.pattern, 40))
+	}
+	if v.collapse {
+		title += " | collapsed"
+	}
+	if v.tee != nil {
+		title += " | tee"
+	}
+	if v.dropped > 0 {
+		title += fmt.Sprintf(" | [yellow::]dropped %d[-::]", v.dropped)
+	}
+	if v.alertCount > 0 {
+		title += fmt.Sprintf(" | [red::]alerts %d[-::]", v.alertCount)
+	}
+	switch {
+	case v.frozen:
+		title += fmt.Sprintf(" | FROZEN — %d buffered", len(v.frozenHeld))
+	case !v.list.IsFollowing():
+		title += fmt.Sprintf(" | PAUSED — %d new lines", v.newLines)
+	}
+	v.list.SetTitle(title + " ")
+}
+
+// updateHeader refreshes the header with the stream context and composed query
+func (v *LogView) updateHeader() {
+	v.header.Clear()
+
+	revision := v.opts.Revision
+	if revision == "" {
+		revision = "all"
+	}
+	query := v.opts.Query
+	if query == "" {
+		query = "(none)"
+	}
+	composed := ""
+	if v.provider != nil {
+		opts := v.opts
+		if v.serverFilter {
+			opts.MinSeverity = v.minSeverity
+		}
+		composed = logging.QueryFilter(v.provider, opts)
+	}
+
+	// Left column: stream context
+	switch {
+	case v.opts.Trace != "":
+		v.header.AddLabelValueRow(0, "Trace", traceID(v.opts.Trace))
+	case len(v.sources) > 0:
+		v.header.AddLabelValueRow(0, "Services", v.sourcesText())
+	case v.file != "":
+		v.header.AddLabelValueRow(0, "File", v.file)
+	default:
+		v.header.AddLabelValueRow(0, "Service", v.serviceName)
+	}
+	v.header.AddLabelValueRow(1, "Region", v.region)
+	v.header.AddLabelValueRow(2, "Revision", revision)
+	v.header.AddLabelValueRow(3, "Time Range", logging.FormatTimeRange(v.opts.Since, v.opts.Until))
+
+	v.header.AddSeparator(2, 5)
+
+	// Right column: shortcuts and query
+	status := v.status
+	if status == "" {
+		status = "↑ at the top loads older logs"
+	}
+	shortcuts := "0-4(Severity) s(Server Filter) /(Query) v(Revision) T(Time Range) r(Re-run) l(Logs) Enter(Inspect) t(Trace) c(Collapse) P(Patterns) [/](Activity) G/f(Follow) p(Freeze) w(Save) W(Tee) Esc(Back)"
+	if len(v.sources) > 0 {
+		shortcuts = "0-4(Severity) F1-F9(Toggle Service) Enter(Inspect) t(Trace) c(Collapse) P(Patterns) [/](Activity) G/f(Follow) p(Freeze) w(Save) W(Tee) Esc(Back)"
+	}
+	v.header.AddSection(0, 3, "Keyboard Shortcuts", shortcuts)
+	v.header.AddSection(1, 3, "Expression", tview.Escape(query))
+	v.header.AddSection(2, 3, "Composed Query", tview.Escape(composed))
+	v.header.AddSection(3, 3, "Status", status)
+	v.updateActivity()
+}
+
+// updateActivity refreshes the number of loaded entries and the activity
+// sparkline: one bar per minute, or per group of minutes for long windows,
+// as high as its number of entries and colored by its worst severity
+func (v *LogView) updateActivity() {
+	v.header.AddLabelValueRow(4, "Loaded", fmt.Sprintf("%d entries", v.buffer.Len()))
+
+	bins := v.histogram.Bins(sparklineBins)
+	if len(bins) == 0 {
+		v.header.AddSection(4, 3, "Activity", "(no entries)")
+		return
+	}
+
+	bars := make([]tui.SparkBar, len(bins))
+	for i, bin := range bins {
+		color := "green"
+		switch {
+		case bin.Errors > 0:
+			color = "red"
+		case bin.Warnings > 0:
+			color = "yellow"
+		}
+		bars[i] = tui.SparkBar{Value: bin.Total(), Color: color}
+	}
+
+	const layout = "15:04"
+	selected := v.selectedBinIndex(bins)
+	caption := fmt.Sprintf("%s-%s, %s per bar",
+		bins[0].Start.Local().Format(layout), bins[len(bins)-1].End.Local().Format(layout),
+		bins[0].End.Sub(bins[0].Start))
+	if selected >= 0 {
+		bin := bins[selected]
+		caption = fmt.Sprintf("%s-%s: [red::]%d errors[gray::], [yellow::]%d warnings[gray::], %d others",
+			bin.Start.Local().Format(layout), bin.End.Local().Format(layout), bin.Errors, bin.Warnings, bin.Others)
+	}
+	v.header.AddSparkline(4, 3, "Activity", bars, selected, caption)
+}
+
+// selectedBinIndex returns the index of the bin holding the selected time, or -1
+func (v *LogView) selectedBinIndex(bins []analytics.HistogramBin) int {
+	if v.selectedBin.IsZero() {
+		return -1
+	}
+	for i, bin := range bins {
+		if !v.selectedBin.Before(bin.Start) && v.selectedBin.Before(bin.End) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SelectBin moves the sparkline selection by delta bars, starting from the
+// newest bar, and scrolls the logs to the first entry of the selected bar
+func (v *LogView) SelectBin(delta int) {
+	bins := v.histogram.Bins(sparklineBins)
+	if len(bins) == 0 {
+		return
+	}
+	i := v.selectedBinIndex(bins)
+	if i < 0 {
+		i = len(bins) - 1
+	} else {
+		i += delta
+	}
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(bins) {
+		i = len(bins) - 1
+	}
+
+	v.jumpTo(bins[i].Start)
+	v.selectedBin = bins[i].Start
+	v.updateActivity()
+}
+
+// jumpTo selects the first visible row at or after t
+func (v *LogView) jumpTo(t time.Time) {
+	for row, seq := range v.visible {
+		if entry, _ := v.buffer.Get(seq); !entry.Timestamp.Before(t) {
+			v.list.ScrollTo(row)
+			v.list.Select(row)
+			return
+		}
+	}
+}
+
+// sourcesText lists the merged sources with their keys, hidden ones grayed out
+func (v *LogView) sourcesText() string {
+	parts := make([]string, len(v.sources))
+	for i, name := range v.sources {
+		color := v.sourceColor(name)
+		if v.hiddenSources[name] {
+			color = "gray"
+		}
+		parts[i] = fmt.Sprintf("[%s::]F%d %s[-::]", color, i+1, name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// render rebuilds the visible rows from the buffered entries
+func (v *LogView) render() {
+	v.visible = v.visible[:0]
+	for seq := v.buffer.FirstSeq(); seq < v.buffer.NextSeq(); seq++ {
+		if entry, _ := v.buffer.Get(seq); v.matchesFilter(entry) {
+			v.visible = append(v.visible, seq)
+		}
+	}
+	if v.opts.Trace != "" {
+		v.groupBySpan()
+	}
+
+	v.repeats = nil
+	if v.collapse {
+		rows := v.visible
+		v.visible = make([]int64, 0, len(rows))
+		v.repeats = make([]int, 0, len(rows))
+		for _, seq := range rows {
+			entry, _ := v.buffer.Get(seq)
+			v.addRow(seq, entry)
+		}
+	}
+}
+
+// addRow adds the row of an entry passing the filters. While collapsing, an
+// entry repeating the message of the last row is counted in that row instead.
+// It reports whether a row was added.
+func (v *LogView) addRow(seq int64, entry model.LogEntry) bool {
+	if v.collapse && len(v.visible) > 0 {
+		last := len(v.visible) - 1
+		if prev, _ := v.buffer.Get(v.visible[last]); sameLine(prev, entry) {
+			v.visible[last] = seq
+			v.repeats[last]++
+			return false
+		}
+	}
+	v.visible = append(v.visible, seq)
+	if v.collapse {
+		v.repeats = append(v.repeats, 1)
+	}
+	return true
+}
+
+// sameLine reports whether two entries render the same message
+func sameLine(a, b model.LogEntry) bool {
+	return a.Message == b.Message && a.Source == b.Source && entryLevel(a) == entryLevel(b)
+}
+
+// filteredEntries returns the loaded entries passing the active filters, including collapsed repeats
+func (v *LogView) filteredEntries() []model.LogEntry {
+	if !v.collapse {
+		entries := make([]model.LogEntry, 0, len(v.visible))
+		for _, seq := range v.visible {
+			entry, _ := v.buffer.Get(seq)
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	var entries []model.LogEntry
+	for seq := v.buffer.FirstSeq(); seq < v.buffer.NextSeq(); seq++ {
+		if entry, _ := v.buffer.Get(seq); v.matchesFilter(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// groupBySpan orders the visible rows by span, spans by their first entry and
+// the entries of a span by time
+func (v *LogView) groupBySpan() {
+	first := make(map[string]time.Time)
+	for _, seq := range v.visible {
+		entry, _ := v.buffer.Get(seq)
+		if t, ok := first[entry.SpanID]; !ok || entry.Timestamp.Before(t) {
+			first[entry.SpanID] = entry.Timestamp
+		}
+	}
+
+	sort.SliceStable(v.visible, func(i, j int) bool {
+		a, _ := v.buffer.Get(v.visible[i])
+		b, _ := v.buffer.Get(v.visible[j])
+		if a.SpanID != b.SpanID {
+			if fa, fb := first[a.SpanID], first[b.SpanID]; !fa.Equal(fb) {
+				return fa.Before(fb)
+			}
+			return a.SpanID < b.SpanID
+		}
+		return a.Timestamp.Before(b.Timestamp)
+	})
+}
+
+// matchesFilter reports whether an entry passes the active severity, source and pattern filters
+func (v *LogView) matchesFilter(entry model.LogEntry) bool {
+	if !v.matchesLevelAndSource(entry) {
+		return false
+	}
+	return v.pattern == "" || analytics.Template(entry.Message) == v.pattern
+}
+
+// matchesLevelAndSource reports whether an entry passes the active severity and source filters
+func (v *LogView) matchesLevelAndSource(entry model.LogEntry) bool {
+	if v.hiddenSources[entry.Source] {
+		return false
+	}
+	return model.SeverityAtLeast(entryLevel(entry), v.minSeverity)
+}
+
+// pendingEntries collects the entries of a stream between two draws
+type pendingEntries struct {
+	mu      sync.Mutex
+	entries []model.LogEntry
+	dropped int
+	queued  bool
+}
+
+func (v *LogView) streamLogs(ctx context.Context, streamer model.LogStreamer) {
+	streamBatches(ctx, v.app, streamer, v.buffer.Capacity(), func(batch []model.LogEntry, dropped int) {
+		if dropped > 0 {
+			v.reportDropped(dropped)
+		}
+		v.appendEntries(batch)
+	})
+}
+
+// streamBatches streams entries and hands them to apply on the UI thread,
+// one batch for all the entries arriving before the next draw. When the UI
+// falls behind by more than maxPending entries the oldest pending ones are
+// dropped and their number passed to apply. Batches of a stream cancelled
+// meanwhile are discarded.
+func streamBatches(ctx context.Context, app interfaces.UIController, streamer model.LogStreamer, maxPending int, apply func(batch []model.LogEntry, dropped int)) {
+	logChan := streamer.StreamLogs(ctx)
+	pending := &pendingEntries{}
+
+	for entry := range logChan {
+		pending.mu.Lock()
+		pending.entries = append(pending.entries, entry)
+		if extra := len(pending.entries) - maxPending; extra > 0 {
+			pending.entries = pending.entries[extra:]
+			pending.dropped += extra
+		}
+		queue := !pending.queued
+		pending.queued = true
+		pending.mu.Unlock()
+
+		// Entries arriving before the next draw are appended as one batch
+		if queue {
+			app.QueueUpdateDraw(func() {
+				pending.mu.Lock()
+				batch, dropped := pending.entries, pending.dropped
+				pending.entries, pending.dropped = nil, 0
+				pending.queued = false
+				pending.mu.Unlock()
+
+				// Drop entries from a stream that has been restarted meanwhile
+				if ctx.Err() != nil {
+					return
+				}
+				apply(batch, dropped)
+			})
+		}
+	}
+}
+
+// appendEntries adds a batch of streamed entries to the view
+func (v *LogView) appendEntries(entries []model.LogEntry) {
+	entries = splitStatus(entries, v.notify)
+	if v.frozen {
+		v.holdEntries(entries)
+		return
+	}
+
+	added := len(v.visible)
+	var teed []model.LogEntry
+	for _, entry := range entries {
+		if v.buffer.Len() == v.buffer.Capacity() {
+			evicted, _ := v.buffer.Get(v.buffer.FirstSeq())
+			v.histogram.Remove(evicted.Timestamp, entryLevel(evicted))
+		}
+		seq := v.buffer.Push(entry)
+		v.histogram.Add(entry.Timestamp, entryLevel(entry))
+		if !v.matchesFilter(entry) {
+			continue
+		}
+		v.addRow(seq, entry)
+		if v.tee != nil {
+			teed = append(teed, entry)
+		}
+	}
+	if v.tee != nil {
+		v.tee.Queue(teed)
+	}
+
+	added = len(v.visible) - added
+	v.updateActivity()
+
+	// Forget the rows of entries evicted from the buffer
+	first := v.buffer.FirstSeq()
+	if v.opts.Trace != "" {
+		// Trace rows are grouped by span rather than kept in arrival order
+		v.render()
+	} else if len(v.visible) > 0 && v.visible[0] < first {
+		n := sort.Search(len(v.visible), func(i int) bool { return v.visible[i] >= first })
+		v.visible = v.visible[n:]
+		if v.repeats != nil {
+			v.repeats = v.repeats[n:]
+		}
+		// Keep the rows on screen in place while paused
+		v.list.ShiftRows(-n)
+	}
+
+	// Follow mode keeps the latest logs visible; otherwise count what was missed
+	if !v.list.IsFollowing() && added > 0 {
+		v.newLines += added
+		v.updateTitle()
+	}
+}
+
+// splitStatus passes the status messages of a stream to notify, so that they
+// are shown in the header rather than stored as logs, and returns the others
+func splitStatus(entries []model.LogEntry, notify func(string)) []model.LogEntry {
+	logs := make([]model.LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !logging.IsStatus(entry) {
+			logs = append(logs, entry)
+			continue
+		}
+		msg := tview.Escape(entry.Message)
+		switch strings.ToUpper(entry.Severity) {
+		case model.SeverityError:
+			msg = "[red::]" + msg
+		case model.SeverityWarning:
+			msg = "[yellow::]" + msg
+		}
+		notify(msg)
+	}
+	return logs
+}
+
+// holdEntries keeps entries streamed while frozen, bounded by the buffer size
+func (v *LogView) holdEntries(entries []model.LogEntry) {
+	v.frozenHeld = append(v.frozenHeld, entries...)
+	if extra := len(v.frozenHeld) - v.buffer.Capacity(); extra > 0 {
+		v.frozenHeld = v.frozenHeld[extra:]
+		v.reportDropped(extra)
+	}
+	v.updateTitle()
+}
+
+// reportDropped counts entries that were dropped before reaching the view and warns about them
+func (v *LogView) reportDropped(n int) {
+	v.dropped += n
+	v.notify(fmt.Sprintf("[yellow::]Dropped %d entries: logs arrive faster than the view can keep up", v.dropped))
+	v.updateTitle()
+}
+
+// entryLevel returns the level used to display and filter an entry.
+// Entries without a meaningful severity have their level parsed from the message.
+func entryLevel(entry model.LogEntry) string {
+	switch severity := strings.ToUpper(entry.Severity); severity {
+	case "", model.SeverityDefault:
+	case "WARNING":
+		return "WARN"
+	default:
+		return severity
+	}
+
+	// Check for common log level patterns in the message
+	message := entry.Message
+	switch {
+	case strings.Contains(message, "ERROR"):
+		return "ERROR"
+	case strings.Contains(message, "WARN") || strings.Contains(message, "WARNING"):
+		return "WARN"
+	case strings.Contains(message, "INFO"):
+		return "INFO"
+	case strings.Contains(message, "DEBUG"):
+		return "DEBUG"
+	case strings.Contains(message, "TRACE"):
+		return "TRACE"
+	}
+	return "INFO" // default level
+}
+
+// formatLogLine renders an entry as a colored log row
+func formatLogLine(entry model.LogEntry) string {
+	timestamp := entry.Timestamp.Local().Format("2006-01-02 15:04:05.000 MST")
+	level := entryLevel(entry)
+
+	// Escape any existing color codes in the message and keep it on a single row
+	message := strings.ReplaceAll(strings.TrimSpace(entry.Message), "[", "[[")
+	message = strings.ReplaceAll(message, "\n", " ↵ ")
+
+	// Platform events of the system log are labeled and colored by kind
+	event := ""
+	messageColor := "white"
+	if logging.LogIDOf(entry) == logging.SystemLogID {
+		if kind := analytics.ClassifySystemMessage(entry.Message); kind != analytics.NoSystemEvent {
+			messageColor = systemEventColor(kind)
+			event = fmt.Sprintf("[%s::b]%-5s[-:-:-] ", messageColor, kind)
+		}
+	}
+
+	// Format: gray timestamp, bold colored level, white message
+	return fmt.Sprintf("[gray::b]%s[-:-:-] [%s::b]%-7s[-:-:-] %s[%s::b]%s[-:-:-]",
+		timestamp,
+		levelColor(level),
+		level,
+		event,
+		messageColor,
+		message,
+	)
+}
+
+// systemEventColor returns the color of a Cloud Run platform event
+func systemEventColor(event analytics.SystemEvent) string {
+	switch event {
+	case analytics.OutOfMemory, analytics.ProbeFailure:
+		return "red"
+	case analytics.InstanceStop:
+		return "orange"
+	case analytics.Scaling:
+		return "aqua"
+	}
+	return "lime"
+}
+
+// levelColor returns the K9s-style color of a log level
+func levelColor(level string) string {
+	switch level {
+	case "ERROR", "CRITICAL", "FATAL", "ALERT", "EMERGENCY":
+		return "red"
+	case "WARN":
+		return "yellow"
+	case "INFO", "NOTICE":
+		return "green"
+	case "DEBUG":
+		return "gray"
+	case "TRACE":
+		return "blue"
 	}
+	return "green" // Use INFO color for any unrecognized level
 }
@@ -0,0 +1,67 @@
+package views
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func benchEntry(i int) model.LogEntry {
+	return model.LogEntry{
+		Timestamp: time.Unix(int64(i), 0),
+		Severity:  "INFO",
+		Message:   fmt.Sprintf("request %d processed in 12ms", i),
+	}
+}
+
+// BenchmarkLogViewAppend measures the cost of appending and drawing one line
+// to a view already holding a full buffer. It should stay flat as the size grows.
+func BenchmarkLogViewAppend(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			screen := tcell.NewSimulationScreen("UTF-8")
+			if err := screen.Init(); err != nil {
+				b.Fatal(err)
+			}
+			screen.SetSize(160, 50)
+
+			v := newLogView(syncUI{}, &model.MockLogProvider{}, model.CloudProviderOptions{ServiceName: "bench"}, "bench")
+			v.SetBufferSize(size)
+			v.SetRect(0, 0, 160, 50)
+			for i := 0; i < size; i++ {
+				v.appendEntries([]model.LogEntry{benchEntry(i)})
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				v.appendEntries([]model.LogEntry{benchEntry(size + i)})
+				v.Draw(screen)
+			}
+		})
+	}
+}
+
+func TestLogViewStatusEntries(t *testing.T) {
+	v := newLogView(syncUI{}, &model.MockLogProvider{}, model.CloudProviderOptions{ServiceName: "test"}, "test")
+
+	status := func(severity, msg string) model.LogEntry {
+		return model.LogEntry{Timestamp: time.Unix(0, 0), Severity: severity, Message: msg, LogName: logging.StatusLogName}
+	}
+	v.appendEntries([]model.LogEntry{
+		status("INFO", "Initial load: searching for logs from the last hour..."),
+		benchEntry(1),
+		status("ERROR", "Error fetching logs: [denied]"),
+	})
+
+	if got := v.buffer.Len(); got != 1 {
+		t.Errorf("buffer holds %d entries, want only the log entry", got)
+	}
+	if want := "[red::]" + tview.Escape("Error fetching logs: [denied]"); v.status != want {
+		t.Errorf("status = %q, want %q", v.status, want)
+	}
+}
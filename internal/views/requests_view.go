@@ -0,0 +1,418 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+	"github.com/lpmourato/c9s/internal/analytics"
+	"github.com/lpmourato/c9s/internal/logging"
+	"github.com/lpmourato/c9s/internal/model"
+	"github.com/lpmourato/c9s/internal/ui/tui"
+)
+
+// DefaultRequestsBufferSize is the number of request log entries kept by the requests view
+const DefaultRequestsBufferSize = 5000
+
+// requestColumn is a sortable column of the requests table
+type requestColumn struct {
+	Title string
+	// Key sorting the table by the column
+	Key rune
+	// Text of the cell and comparison used for sorting
+	Text  func(entry model.LogEntry) string
+	Less  func(a, b model.LogEntry) bool
+	Align int
+}
+
+// requestColumns are the columns of the requests table
+var requestColumns = []requestColumn{
+	{
+		Title: "Time", Key: 'T',
+		Text: func(e model.LogEntry) string { return e.Timestamp.Local().Format("15:04:05.000") },
+		Less: func(a, b model.LogEntry) bool { return a.Timestamp.Before(b.Timestamp) },
+	},
+	{
+		Title: "Method", Key: 'M',
+		Text: func(e model.LogEntry) string { return e.HTTPRequest.Method },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.Method < b.HTTPRequest.Method },
+	},
+	{
+		Title: "Status", Key: 'S', Align: tview.AlignRight,
+		Text: func(e model.LogEntry) string { return fmt.Sprintf("%d", e.HTTPRequest.Status) },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.Status < b.HTTPRequest.Status },
+	},
+	{
+		Title: "Latency", Key: 'L', Align: tview.AlignRight,
+		Text: func(e model.LogEntry) string { return formatLatency(e.HTTPRequest.Latency) },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.Latency < b.HTTPRequest.Latency },
+	},
+	{
+		Title: "Size", Key: 'Z', Align: tview.AlignRight,
+		Text: func(e model.LogEntry) string { return formatSize(e.HTTPRequest.ResponseSize) },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.ResponseSize < b.HTTPRequest.ResponseSize },
+	},
+	{
+		Title: "Path", Key: 'P',
+		Text: func(e model.LogEntry) string { return analytics.RequestPath(e.HTTPRequest.URL) },
+		Less: func(a, b model.LogEntry) bool {
+			return analytics.RequestPath(a.HTTPRequest.URL) < analytics.RequestPath(b.HTTPRequest.URL)
+		},
+	},
+	{
+		Title: "User Agent", Key: 'U',
+		Text: func(e model.LogEntry) string { return e.HTTPRequest.UserAgent },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.UserAgent < b.HTTPRequest.UserAgent },
+	},
+	{
+		Title: "Remote IP", Key: 'I',
+		Text: func(e model.LogEntry) string { return e.HTTPRequest.RemoteIP },
+		Less: func(a, b model.LogEntry) bool { return a.HTTPRequest.RemoteIP < b.HTTPRequest.RemoteIP },
+	},
+}
+
+// RequestsView shows the request log of a service as a sortable table
+type RequestsView struct {
+	*tview.Flex
+	table  *tui.Table
+	header *tui.HeaderTable
+	prompt *tui.Prompt
+	app    *tui.App
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	provider model.LogProvider
+	opts     model.CloudProviderOptions
+
+	// Request log entries received so far and the sequence numbers of those shown, in table order
+	buffer *logging.RingBuffer
+	rows   []int64
+
+	// Sort column index and direction
+	sortColumn int
+	sortDesc   bool
+
+	// Quick filters
+	only5xx    bool
+	slowerThan time.Duration
+	pathPrefix string
+	status     string
+}
+
+// NewRequestsView creates a view of the HTTP requests served by a service
+func NewRequestsView(app *tui.App, provider model.LogProvider, projectID, serviceName, region string) *RequestsView {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	v := &RequestsView{
+		Flex:     tview.NewFlex().SetDirection(tview.FlexRow),
+		table:    tui.NewTable(),
+		header:   tui.NewHeaderTable(),
+		app:      app,
+		ctx:      ctx,
+		cancel:   cancel,
+		provider: provider,
+		opts: model.CloudProviderOptions{
+			ProjectID:   projectID,
+			ServiceName: serviceName,
+			Region:      region,
+			LogIDs:      []string{logging.RequestsLogID},
+		},
+		buffer:   logging.NewRingBuffer(DefaultRequestsBufferSize),
+		sortDesc: true, // Newest requests first
+	}
+
+	v.header.SetTitle(" Requests ")
+	v.table.SetTitle(fmt.Sprintf(" %s - %s | Requests ", serviceName, region))
+	v.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorNavy))
+
+	v.AddItem(v.header, 6, 0, false)
+	v.AddItem(v.table, 0, 1, true)
+
+	v.setupKeys()
+	v.render()
+	v.notify(fmt.Sprintf("Loading requests of [yellow::b]%s[-:-:-]...", serviceName))
+
+	return v
+}
+
+// setupKeys sets up the key bindings of the requests view
+func (v *RequestsView) setupKeys() {
+	keyHandler := tui.NewContextualKeyHandler(v.app)
+	keyHandler.SetContext(tui.ContextRequestsView)
+	v.prompt = tui.NewPrompt(v.app, v.Flex, v.table)
+
+	keyHandler.RegisterHandler(tui.ActionEscape, func() error {
+		v.close()
+		return nil
+	})
+	keyHandler.RegisterHandler(tui.ActionQuit, func() error {
+		v.close()
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('5', tui.ActionToggle5xx)
+	keyHandler.RegisterHandler(tui.ActionToggle5xx, func() error {
+		v.only5xx = !v.only5xx
+		v.render()
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('s', tui.ActionEditSlowThreshold)
+	keyHandler.RegisterHandler(tui.ActionEditSlowThreshold, func() error {
+		value := ""
+		if v.slowerThan > 0 {
+			value = v.slowerThan.String()
+		}
+		v.prompt.Ask("Slower than (e.g. 500ms, empty for all): ", value, v.SetSlowThreshold)
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('/', tui.ActionEditQuery)
+	keyHandler.RegisterHandler(tui.ActionEditQuery, func() error {
+		v.prompt.Ask("Path prefix: ", v.pathPrefix, func(text string) {
+			v.pathPrefix = strings.TrimSpace(text)
+			v.render()
+		})
+		return nil
+	})
+
+	keyHandler.RegisterRuneBinding('c', tui.ActionClearFilters)
+	keyHandler.RegisterHandler(tui.ActionClearFilters, func() error {
+		v.only5xx, v.slowerThan, v.pathPrefix = false, 0, ""
+		v.render()
+		return nil
+	})
+
+	// Shift+letter sorts by a column; sorting by the same column again reverses the order
+	capture := keyHandler.CreateContextualInputCapture()
+	v.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune {
+			for i, column := range requestColumns {
+				if event.Rune() == column.Key {
+					v.SortBy(i)
+					return nil
+				}
+			}
+		}
+		return capture(event)
+	})
+}
+
+// StreamRequests starts streaming the request log of the service
+func (v *RequestsView) StreamRequests() {
+	streamer := logging.NewLogService(v.provider, v.opts)
+	streamBatches(v.ctx, v.app, streamer, DefaultRequestsBufferSize, func(batch []model.LogEntry, dropped int) {
+		v.appendEntries(batch)
+		if dropped > 0 {
+			v.notify(fmt.Sprintf("[yellow::]Dropped %d requests: they arrive faster than the view can keep up", dropped))
+		}
+	})
+}
+
+// SortBy sorts the table by a column, reversing the order when it is already sorted by it
+func (v *RequestsView) SortBy(column int) {
+	if v.sortColumn == column {
+		v.sortDesc = !v.sortDesc
+	} else {
+		v.sortColumn = column
+		v.sortDesc = false
+	}
+	v.render()
+}
+
+// SetSlowThreshold shows only the requests slower than a duration; empty shows all requests
+func (v *RequestsView) SetSlowThreshold(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		v.slowerThan = 0
+		v.render()
+		return
+	}
+	threshold, err := time.ParseDuration(text)
+	if err != nil {
+		v.notify(fmt.Sprintf("[red::]Invalid threshold: %v", err))
+		return
+	}
+	v.slowerThan = threshold
+	v.render()
+}
+
+// appendEntries adds the request entries of a batch
+func (v *RequestsView) appendEntries(entries []model.LogEntry) {
+	added := 0
+	for _, entry := range splitStatus(entries, v.notify) {
+		// Entries without a request are application or system logs
+		if entry.HTTPRequest == nil {
+			continue
+		}
+		v.buffer.Push(entry)
+		added++
+	}
+	if added > 0 {
+		v.notify("")
+		v.render()
+	}
+}
+
+// matches reports whether a request passes the quick filters
+func (v *RequestsView) matches(entry model.LogEntry) bool {
+	req := entry.HTTPRequest
+	if v.only5xx && req.Status < 500 {
+		return false
+	}
+	if v.slowerThan > 0 && req.Latency < v.slowerThan {
+		return false
+	}
+	if v.pathPrefix != "" && !strings.HasPrefix(analytics.RequestPath(req.URL), v.pathPrefix) {
+		return false
+	}
+	return true
+}
+
+// render rebuilds the table from the buffered requests
+func (v *RequestsView) render() {
+	v.rows = v.rows[:0]
+	for seq := v.buffer.FirstSeq(); seq < v.buffer.NextSeq(); seq++ {
+		if entry, _ := v.buffer.Get(seq); v.matches(entry) {
+			v.rows = append(v.rows, seq)
+		}
+	}
+
+	column := requestColumns[v.sortColumn]
+	sort.SliceStable(v.rows, func(i, j int) bool {
+		a, _ := v.buffer.Get(v.rows[i])
+		b, _ := v.buffer.Get(v.rows[j])
+		if v.sortDesc {
+			return column.Less(b, a)
+		}
+		return column.Less(a, b)
+	})
+
+	row, _ := v.table.GetSelection()
+	v.table.Clear()
+	titles := make([]string, len(requestColumns))
+	for i, c := range requestColumns {
+		titles[i] = fmt.Sprintf("%s(%c)", c.Title, c.Key)
+		if i == v.sortColumn {
+			if v.sortDesc {
+				titles[i] += "↓"
+			} else {
+				titles[i] += "↑"
+			}
+		}
+	}
+	v.table.SetColumns(titles)
+
+	for i, seq := range v.rows {
+		entry, _ := v.buffer.Get(seq)
+		cells := make([]tui.TableCell, len(requestColumns))
+		for col, c := range requestColumns {
+			cells[col] = tui.TableCell{
+				Text:      tview.Escape(c.Text(entry)),
+				TextColor: tcell.ColorWhite,
+				Expansion: 1,
+				Align:     c.Align,
+			}
+		}
+		cells[2].TextColor = statusColor(entry.HTTPRequest.Status)
+		cells[5].Expansion = 3
+		v.table.AddStyledRow(i+1, cells)
+	}
+
+	if row < 1 {
+		row = 1
+	}
+	if row > len(v.rows) {
+		row = len(v.rows)
+	}
+	v.table.Select(row, 0)
+	v.updateHeader()
+}
+
+// updateHeader refreshes the header with the service and the active filters
+func (v *RequestsView) updateHeader() {
+	v.header.Clear()
+
+	slow := "off"
+	if v.slowerThan > 0 {
+		slow = "> " + v.slowerThan.String()
+	}
+	only5xx := "off"
+	if v.only5xx {
+		only5xx = "on"
+	}
+	prefix := v.pathPrefix
+	if prefix == "" {
+		prefix = "(none)"
+	}
+
+	v.header.AddLabelValueRow(0, "Service", v.opts.ServiceName)
+	v.header.AddLabelValueRow(1, "Region", v.opts.Region)
+	v.header.AddLabelValueRow(2, "Requests", fmt.Sprintf("%d of %d", len(v.rows), v.buffer.Len()))
+
+	v.header.AddSeparator(2, 4)
+
+	status := v.status
+	if status == "" {
+		status = "Shift+letter sorts by a column"
+	}
+	v.header.AddSection(0, 3, "Keyboard Shortcuts", "5(5xx) s(Slow) /(Path Prefix) c(Clear Filters) Esc(Back)")
+	v.header.AddSection(1, 3, "Filters", fmt.Sprintf("5xx: %s | slow: %s | path: %s", only5xx, slow, tview.Escape(prefix)))
+	v.header.AddSection(2, 3, "Status", status)
+}
+
+// notify shows a status message in the header
+func (v *RequestsView) notify(msg string) {
+	v.status = msg
+	v.updateHeader()
+}
+
+// close stops streaming, releases the provider and returns to the main view
+func (v *RequestsView) close() {
+	v.cancel()
+	closeProvider(v.provider)
+	v.app.ReturnToMain()
+}
+
+// statusColor returns the color of an HTTP status code
+func statusColor(status int) tcell.Color {
+	switch {
+	case status >= 500:
+		return tcell.ColorRed
+	case status >= 400:
+		return tcell.ColorYellow
+	case status >= 300:
+		return tcell.ColorAqua
+	default:
+		return tcell.ColorGreen
+	}
+}
+
+// formatLatency renders a latency with a precision fitting its magnitude
+func formatLatency(latency time.Duration) string {
+	switch {
+	case latency >= time.Second:
+		return latency.Round(time.Millisecond).String()
+	case latency >= time.Millisecond:
+		return latency.Round(100 * time.Microsecond).String()
+	}
+	return latency.String()
+}
+
+// formatSize renders a byte count with a binary unit
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%dB", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
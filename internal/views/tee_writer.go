@@ -0,0 +1,83 @@
+package views
+
+import (
+	"sync"
+
+	"github.com/lpmourato/c9s/internal/export"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+// teeWriter appends entries to a tee file from its own goroutine, so that
+// writing and rotating the file never holds up the UI. Queue and Close are
+// called from the UI goroutine.
+type teeWriter struct {
+	// Path is the absolute path of the tee file
+	Path string
+
+	tee     *export.Tee
+	mu      sync.Mutex
+	pending []model.LogEntry
+	failed  bool
+	wake    chan struct{}
+	// done is closed when the writer has stopped and closed the file
+	done chan struct{}
+}
+
+// newTeeWriter starts writing to tee. failed is called from the writer
+// goroutine with the first write error, after which entries are discarded.
+func newTeeWriter(tee *export.Tee, failed func(error)) *teeWriter {
+	t := &teeWriter{Path: tee.Path, tee: tee, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	go t.run(failed)
+	return t
+}
+
+// Queue hands entries to the writer goroutine
+func (t *teeWriter) Queue(entries []model.LogEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	t.mu.Lock()
+	if t.failed {
+		t.mu.Unlock()
+		return
+	}
+	t.pending = append(t.pending, entries...)
+	t.mu.Unlock()
+
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops the writer once the queued entries are written, closing the file
+func (t *teeWriter) Close() {
+	close(t.wake)
+}
+
+func (t *teeWriter) run(failed func(error)) {
+	defer close(t.done)
+	defer t.tee.Close()
+	for {
+		_, open := <-t.wake
+
+		t.mu.Lock()
+		batch := t.pending
+		t.pending = nil
+		t.mu.Unlock()
+
+		for _, entry := range batch {
+			if err := t.tee.Write(entry); err != nil {
+				t.mu.Lock()
+				t.failed = true
+				t.pending = nil
+				t.mu.Unlock()
+				failed(err)
+				return
+			}
+		}
+		if !open {
+			return
+		}
+	}
+}
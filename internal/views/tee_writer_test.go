@@ -0,0 +1,52 @@
+package views
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lpmourato/c9s/internal/export"
+	"github.com/lpmourato/c9s/internal/model"
+)
+
+func TestTeeWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs.txt")
+	tee, err := export.NewTee(path, export.Text, nil, 0)
+	if err != nil {
+		t.Fatalf("NewTee() error = %v", err)
+	}
+
+	failed := make(chan error, 1)
+	w := newTeeWriter(tee, func(err error) { failed <- err })
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w.Queue([]model.LogEntry{{Timestamp: ts, Severity: "INFO", Message: "first"}})
+	w.Queue(nil)
+	w.Queue([]model.LogEntry{{Timestamp: ts, Severity: "INFO", Message: "second"}})
+	w.Close()
+
+	// The file is closed once the queued entries are written
+	select {
+	case <-w.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("writer not stopped after Close()")
+	}
+	if err := tee.Write(model.LogEntry{}); err == nil {
+		t.Error("tee file left open")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "first") || !strings.HasSuffix(lines[1], "second") {
+		t.Errorf("tee file holds %q, want the queued entries in order", lines)
+	}
+	select {
+	case err := <-failed:
+		t.Errorf("writer failed: %v", err)
+	default:
+	}
+}
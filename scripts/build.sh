@@ -9,7 +9,7 @@ echo "Building c9s..."
 mkdir -p bin
 
 # Build for current platform
-go build -ldflags "-X main.version=$(git describe --tags --always --dirty)" -o bin/c9s ./cmd/c9s
+go build -ldflags "-X main.version=$(git describe --tags --always --dirty) -X main.commit=$(git rev-parse HEAD) -X main.date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" -o bin/c9s ./cmd/c9s
 
 echo "Build complete: bin/c9s"
 